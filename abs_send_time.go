@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+)
+
+// headerExtensionID returns the ID of the negotiated header extension with uri, 0 if
+// it isn't negotiated.
+func headerExtensionID(headerExtensions []RTPHeaderExtensionParameter, uri string) uint8 {
+	for _, headerExtension := range headerExtensions {
+		if headerExtension.URI == uri {
+			return uint8(headerExtension.ID) //nolint:gosec // G115
+		}
+	}
+
+	return 0
+}
+
+// streamHeaderExtensionID returns the ID of the header extension with uri of the
+// stream, 0 if it isn't negotiated.
+func streamHeaderExtensionID(streamInfo interceptor.StreamInfo, uri string) uint8 {
+	for _, headerExtension := range streamInfo.RTPHeaderExtensions {
+		if headerExtension.URI == uri {
+			return uint8(headerExtension.ID) //nolint:gosec // G115
+		}
+	}
+
+	return 0
+}
+
+// absSendTime returns the send time of the RTP packet buf from its abs-send-time
+// extension, ok is false if the packet doesn't have it.
+func absSendTime(buf []byte, id uint8, arrival time.Time) (sendTime time.Time, ok bool) {
+	header := rtp.Header{}
+	if _, err := header.Unmarshal(buf); err != nil {
+		return time.Time{}, false
+	}
+
+	payload := header.GetExtension(id)
+	if payload == nil {
+		return time.Time{}, false
+	}
+
+	extension := rtp.AbsSendTimeExtension{}
+	if err := extension.Unmarshal(payload); err != nil {
+		return time.Time{}, false
+	}
+
+	return extension.Estimate(arrival), true
+}
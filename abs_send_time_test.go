@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/sdp/v3"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbsSendTime(t *testing.T) {
+	writer := &srtpWriterFuture{}
+	header := &rtp.Header{Version: 2, SequenceNumber: 1}
+	assert.Same(t, header, writer.withAbsSendTime(header), "nothing is stamped if not negotiated")
+
+	writer.absSendTimeID = 3
+	before := time.Now()
+	stamped := writer.withAbsSendTime(header)
+	assert.Nil(t, header.GetExtension(3), "the header of the caller is unchanged")
+
+	buf, err := (&rtp.Packet{Header: *stamped, Payload: []byte{0x00}}).Marshal()
+	require.NoError(t, err)
+
+	sendTime, ok := absSendTime(buf, 3, time.Now())
+	require.True(t, ok)
+	// abs-send-time has a resolution of 1/262144 second
+	assert.WithinDuration(t, before, sendTime, 10*time.Millisecond)
+
+	_, ok = absSendTime(buf, 4, time.Now())
+	assert.False(t, ok)
+	_, ok = absSendTime(buf[:4], 3, time.Now())
+	assert.False(t, ok)
+}
+
+func TestPeerConnection_AbsSendTime(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	mediaEngine := &MediaEngine{}
+	require.NoError(t, mediaEngine.RegisterDefaultCodecs())
+	require.NoError(t, ConfigureAbsSendTimeHeaderExtension(mediaEngine))
+	pcOffer, pcAnswer, err := NewAPI(WithMediaEngine(mediaEngine)).newPair(Configuration{})
+	require.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	require.NoError(t, err)
+	sender, err := pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	type received struct {
+		packet   *rtp.Packet
+		sendTime interface{}
+	}
+	packets := make(chan received, 1)
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	pcAnswer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		pkt, attributes, readErr := trackRemote.ReadRTP()
+		assert.NoError(t, readErr)
+
+		packets <- received{pkt, attributes.Get(AttributeAbsSendTime)}
+		onTrackFiredFunc()
+	})
+
+	start := time.Now()
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	sendVideoUntilDone(t, onTrackFired.Done(), []*TrackLocalStaticSample{track})
+
+	id := headerExtensionID(sender.GetParameters().HeaderExtensions, sdp.ABSSendTimeURI)
+	require.NotZero(t, id)
+
+	packet := <-packets
+	assert.NotNil(t, packet.packet.GetExtension(id))
+
+	sendTime, ok := packet.sendTime.(time.Time)
+	require.True(t, ok)
+	assert.True(t, sendTime.After(start.Add(-10*time.Millisecond)))
+	assert.True(t, sendTime.Before(time.Now().Add(10*time.Millisecond)))
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
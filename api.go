@@ -38,7 +38,9 @@ func NewAPI(options ...func(*API)) *API {
 		o(api)
 	}
 
-	if api.settingEngine.LoggerFactory == nil {
+	if api.settingEngine.structuredLogger != nil {
+		api.settingEngine.LoggerFactory = newStructuredLoggerFactory(api.settingEngine.structuredLogger, "")
+	} else if api.settingEngine.LoggerFactory == nil {
 		api.settingEngine.LoggerFactory = logging.NewDefaultLoggerFactory()
 	}
 
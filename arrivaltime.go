@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// arrivalTimeRecorder is a receive buffer of a SRTP stream that records the arrival time of
+// its packets.
+type arrivalTimeRecorder interface {
+	// lastArrival returns the arrival time of the packet returned by the last Read.
+	lastArrival() time.Time
+}
+
+// arrivalTimeBuffer wraps the receive buffer of a SRTP stream created by
+// SettingEngine.BufferFactory and records when every packet was written to it by the SRTP
+// session, that is as soon as it was decrypted.
+type arrivalTimeBuffer struct {
+	io.ReadWriteCloser
+
+	mu       sync.Mutex
+	arrivals []time.Time
+	last     time.Time
+}
+
+func newArrivalTimeBuffer(buffer io.ReadWriteCloser) *arrivalTimeBuffer {
+	return &arrivalTimeBuffer{ReadWriteCloser: buffer}
+}
+
+func (b *arrivalTimeBuffer) Write(p []byte) (int, error) {
+	now := time.Now()
+
+	// The lock is held while writing so that a reader can't take the packet before its
+	// arrival time is queued. Writes to the buffer never block.
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n, err := b.ReadWriteCloser.Write(p)
+	if err == nil {
+		b.arrivals = append(b.arrivals, now)
+	}
+
+	return n, err
+}
+
+func (b *arrivalTimeBuffer) Read(p []byte) (int, error) {
+	n, err := b.ReadWriteCloser.Read(p)
+	if err == nil || errors.Is(err, io.ErrShortBuffer) {
+		b.mu.Lock()
+		if len(b.arrivals) > 0 {
+			b.last = b.arrivals[0]
+			b.arrivals = b.arrivals[1:]
+		}
+		b.mu.Unlock()
+	}
+
+	return n, err
+}
+
+// lastArrival returns the arrival time of the packet returned by the last Read.
+func (b *arrivalTimeBuffer) lastArrival() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.last
+}
+
+// Size forwards to the wrapped buffer, see DTLSTransport.bufferedBytes.
+func (b *arrivalTimeBuffer) Size() int {
+	if sizer, ok := b.ReadWriteCloser.(interface{ Size() int }); ok {
+		return sizer.Size()
+	}
+
+	return 0
+}
+
+// SetReadDeadline forwards to the wrapped buffer, it is used by srtp.ReadStreamSRTP.
+func (b *arrivalTimeBuffer) SetReadDeadline(deadline time.Time) error {
+	if deadliner, ok := b.ReadWriteCloser.(interface{ SetReadDeadline(time.Time) error }); ok {
+		return deadliner.SetReadDeadline(deadline)
+	}
+
+	return nil
+}
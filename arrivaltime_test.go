@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/packetio"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrivalTimeBuffer(t *testing.T) {
+	packetBuffer := packetio.NewBuffer()
+	packetBuffer.SetLimitSize(10)
+	buffer := newArrivalTimeBuffer(packetBuffer)
+
+	before := time.Now()
+	_, err := buffer.Write([]byte{1, 2, 3})
+	require.NoError(t, err)
+	_, err = buffer.Write([]byte{4, 5, 6})
+	require.NoError(t, err)
+
+	// A packet dropped by the buffer has no arrival time
+	_, err = buffer.Write(make([]byte, 10))
+	require.ErrorIs(t, err, packetio.ErrFull)
+	assert.Equal(t, packetBuffer.Size(), buffer.Size())
+
+	b := make([]byte, 3)
+	_, err = buffer.Read(b)
+	require.NoError(t, err)
+	first := buffer.lastArrival()
+	assert.False(t, first.Before(before))
+
+	// A truncated packet is consumed with its arrival time
+	_, err = buffer.Read(b[:1])
+	require.ErrorIs(t, err, io.ErrShortBuffer)
+	second := buffer.lastArrival()
+	assert.False(t, second.Before(first))
+	assert.False(t, time.Now().Before(second))
+
+	assert.NoError(t, buffer.SetReadDeadline(time.Now().Add(10*time.Millisecond)))
+	_, err = buffer.Read(b)
+	assert.Error(t, err)
+	assert.Equal(t, second, buffer.lastArrival())
+
+	assert.NoError(t, buffer.Close())
+}
+
+func TestTrackRemote_ArrivalTime(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	require.NoError(t, err)
+	_, err = pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	arrivals := make(chan time.Time, 1)
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	pcAnswer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		_, attributes, readErr := trackRemote.ReadRTP()
+		assert.NoError(t, readErr)
+
+		arrival, ok := attributes.Get(AttributeArrivalTime).(time.Time)
+		assert.True(t, ok)
+		arrivals <- arrival
+		onTrackFiredFunc()
+	})
+
+	start := time.Now()
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	sendVideoUntilDone(t, onTrackFired.Done(), []*TrackLocalStaticSample{track})
+
+	arrival := <-arrivals
+	assert.True(t, arrival.After(start))
+	assert.False(t, time.Now().Before(arrival))
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"fmt"
+)
+
+// BitrateAllocationPolicy decides how PeerConnection.AllocateTargetBitrates splits
+// the available bitrate between the target bitrates of the sending tracks.
+type BitrateAllocationPolicy int
+
+const (
+	// BitrateAllocationPolicyUnknown is the enum's zero-value.
+	BitrateAllocationPolicyUnknown BitrateAllocationPolicy = iota
+
+	// BitrateAllocationPolicyFair shares the available bitrate equally between
+	// all sending tracks, whatever their kind.
+	BitrateAllocationPolicyFair
+
+	// BitrateAllocationPolicyAudioFirst reserves bitrate for the audio tracks
+	// before the video tracks share what is left, so that audio keeps flowing
+	// when the bandwidth drops.
+	BitrateAllocationPolicyAudioFirst
+)
+
+// This is done this way because of a linter.
+const (
+	bitrateAllocationPolicyFairStr       = "fair"
+	bitrateAllocationPolicyAudioFirstStr = "audio-first"
+)
+
+func (p BitrateAllocationPolicy) String() string {
+	switch p {
+	case BitrateAllocationPolicyFair:
+		return bitrateAllocationPolicyFairStr
+	case BitrateAllocationPolicyAudioFirst:
+		return bitrateAllocationPolicyAudioFirstStr
+	default:
+		return ErrUnknownType.Error()
+	}
+}
+
+// AllocateTargetBitrates splits bitrate, in bits per second, between the RTPSenders that are
+// currently sending, following the policy set with SettingEngine.SetBitrateAllocationPolicy.
+// It is usually called from the OnTargetBitrateChange handler of a cc.BandwidthEstimator.
+//
+// The allocation is advisory: it is not wired to a pacer or to the congestion controller, and
+// nothing is dropped or delayed to keep an RTPSender under its target bitrate. Pion doesn't
+// encode media, the target bitrate of every RTPSender is reported by RTPSender.TargetBitrate,
+// RTPSender.OnTargetBitrateChange and the TargetBitrate of its OutboundRTPStreamStats, and the
+// application applies it to its encoders. Only SettingEngine.SetSimulcastLayerPausing acts on
+// the targets, by pausing the simulcast layers they can't carry.
+//
+// With BitrateAllocationPolicyAudioFirst every audio track is served first, up to its max
+// bitrate set by RTPSender.SetMaxBitrate or 64 kbps when it has none, then video tracks share
+// the rest equally. If the bitrate doesn't even cover audio, audio tracks share all of it and
+// video tracks get 0. A max bitrate is never exceeded, bitrate left over by capped video
+// tracks goes to the other video tracks, then to audio tracks without a max bitrate.
+func (pc *PeerConnection) AllocateTargetBitrates(bitrate int) {
+	if bitrate < 0 {
+		bitrate = 0
+	}
+
+	var audio, video []*RTPSender
+	for _, transceiver := range pc.GetTransceivers() {
+		sender := transceiver.Sender()
+		direction := transceiver.Direction()
+		if sender == nil || sender.Track() == nil ||
+			(direction != RTPTransceiverDirectionSendrecv && direction != RTPTransceiverDirectionSendonly) {
+			continue
+		}
+
+		if sender.kind == RTPCodecTypeAudio {
+			audio = append(audio, sender)
+		} else {
+			video = append(video, sender)
+		}
+	}
+
+	policy := pc.api.settingEngine.bitrateAllocationPolicy
+	if policy != BitrateAllocationPolicyAudioFirst {
+		senders := append(append([]*RTPSender{}, audio...), video...)
+		allocations := allocateBitrate(bitrate, maxBitrates(senders, 0))
+		for i, sender := range senders {
+			sender.setTargetBitrate(allocations[i])
+		}
+
+		return
+	}
+
+	audioAllocations := allocateBitrate(bitrate, maxBitrates(audio, defaultAudioReservedBitrate))
+	for _, allocation := range audioAllocations {
+		bitrate -= allocation
+	}
+
+	videoAllocations := allocateBitrate(bitrate, maxBitrates(video, 0))
+	for _, allocation := range videoAllocations {
+		bitrate -= allocation
+	}
+
+	// Audio tracks without a max bitrate only had a reservation, they may use what video left
+	var uncapped []int
+	for i, sender := range audio {
+		if sender.MaxBitrate() == 0 {
+			uncapped = append(uncapped, i)
+		}
+	}
+	for j, allocation := range allocateBitrate(bitrate, make([]int, len(uncapped))) {
+		audioAllocations[uncapped[j]] += allocation
+	}
+
+	for i, sender := range audio {
+		sender.setTargetBitrate(audioAllocations[i])
+	}
+
+	for i, sender := range video {
+		sender.setTargetBitrate(videoAllocations[i])
+	}
+}
+
+// maxBitrates returns the max bitrates of senders, fallback is used for the ones without.
+func maxBitrates(senders []*RTPSender, fallback int) []int {
+	bitrates := make([]int, len(senders))
+	for i, sender := range senders {
+		bitrates[i] = sender.MaxBitrate()
+		if bitrates[i] == 0 {
+			bitrates[i] = fallback
+		}
+	}
+
+	return bitrates
+}
+
+// allocateBitrate shares bitrate equally between streams, a stream never gets more
+// than its max bitrate and what it leaves goes to the others. A max bitrate of 0 is
+// unlimited. The sum of the allocations is bitrate unless every stream is capped.
+func allocateBitrate(bitrate int, maxBitrates []int) []int {
+	allocations := make([]int, len(maxBitrates))
+	remaining := len(maxBitrates)
+	for bitrate > 0 && remaining > 0 {
+		share := bitrate / remaining
+		if share == 0 {
+			share = 1
+		}
+
+		remaining = 0
+		for i, maxBitrate := range maxBitrates {
+			if bitrate == 0 {
+				break
+			}
+			if maxBitrate != 0 && allocations[i] >= maxBitrate {
+				continue
+			}
+
+			allocation := share
+			if maxBitrate != 0 && allocations[i]+allocation > maxBitrate {
+				allocation = maxBitrate - allocations[i]
+			}
+			if allocation > bitrate {
+				allocation = bitrate
+			}
+
+			allocations[i] += allocation
+			bitrate -= allocation
+			if maxBitrate == 0 || allocations[i] < maxBitrate {
+				remaining++
+			}
+		}
+	}
+
+	return allocations
+}
+
+// SetMaxBitrate sets the max bitrate, in bits per second, that AllocateTargetBitrates of the
+// PeerConnection allocates to this RTPSender, 0 removes the limit. It takes effect at the
+// next AllocateTargetBitrates. For audio with BitrateAllocationPolicyAudioFirst it is also the
+// bitrate reserved before video is served.
+func (r *RTPSender) SetMaxBitrate(bitrate int) error {
+	if bitrate < 0 {
+		return fmt.Errorf("%w: %d", errRTPSenderInvalidMaxBitrate, bitrate)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxBitrate = bitrate
+
+	return nil
+}
+
+// MaxBitrate returns the max bitrate set with SetMaxBitrate, 0 if there is none.
+func (r *RTPSender) MaxBitrate() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.maxBitrate
+}
+
+// TargetBitrate returns the bitrate, in bits per second, allocated to this RTPSender by the
+// last AllocateTargetBitrates of the PeerConnection, 0 if none happened.
+func (r *RTPSender) TargetBitrate() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.targetBitrate
+}
+
+// OnTargetBitrateChange sets an event handler which is invoked when AllocateTargetBitrates of
+// the PeerConnection changes the bitrate allocated to this RTPSender.
+func (r *RTPSender) OnTargetBitrateChange(f func(bitrate int)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onTargetBitrateChangeHandler = f
+}
+
+func (r *RTPSender) setTargetBitrate(bitrate int) {
+	r.pauseSimulcastLayers(bitrate)
+
+	r.mu.Lock()
+	if r.targetBitrate == bitrate {
+		r.mu.Unlock()
+
+		return
+	}
+	r.targetBitrate = bitrate
+	handler := r.onTargetBitrateChangeHandler
+	r.mu.Unlock()
+
+	if handler != nil {
+		go handler(bitrate)
+	}
+}
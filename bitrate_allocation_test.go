@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitrateAllocationPolicy_String(t *testing.T) {
+	testCases := []struct {
+		policy         BitrateAllocationPolicy
+		expectedString string
+	}{
+		{BitrateAllocationPolicyUnknown, ErrUnknownType.Error()},
+		{BitrateAllocationPolicyFair, "fair"},
+		{BitrateAllocationPolicyAudioFirst, "audio-first"},
+	}
+
+	for i, testCase := range testCases {
+		assert.Equal(t, testCase.expectedString, testCase.policy.String(), "testCase: %d %v", i, testCase)
+	}
+}
+
+func TestAllocateBitrate(t *testing.T) {
+	for _, testCase := range []struct {
+		name        string
+		bitrate     int
+		maxBitrates []int
+		expected    []int
+	}{
+		{"no streams", 1000, []int{}, []int{}},
+		{"equal share", 900, []int{0, 0, 0}, []int{300, 300, 300}},
+		{"remainder", 10, []int{0, 0, 0}, []int{4, 3, 3}},
+		{"capped stream leaves bitrate", 1000, []int{100, 0, 0}, []int{100, 450, 450}},
+		{"all capped", 1000, []int{100, 200}, []int{100, 200}},
+		{"nothing available", 0, []int{100, 0}, []int{0, 0}},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, allocateBitrate(testCase.bitrate, testCase.maxBitrates))
+		})
+	}
+}
+
+func TestPeerConnection_AllocateTargetBitrates(t *testing.T) {
+	newSenders := func(t *testing.T, policy BitrateAllocationPolicy) (*PeerConnection, *RTPSender, *RTPSender) {
+		t.Helper()
+
+		s := SettingEngine{}
+		s.SetBitrateAllocationPolicy(policy)
+		pc, err := NewAPI(WithSettingEngine(s)).NewPeerConnection(Configuration{})
+		require.NoError(t, err)
+
+		audioTrack, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeOpus}, "audio", "pion")
+		require.NoError(t, err)
+		audio, err := pc.AddTrack(audioTrack)
+		require.NoError(t, err)
+
+		videoTrack, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+		require.NoError(t, err)
+		video, err := pc.AddTrack(videoTrack)
+		require.NoError(t, err)
+
+		return pc, audio, video
+	}
+
+	t.Run("fair", func(t *testing.T) {
+		pc, audio, video := newSenders(t, BitrateAllocationPolicyUnknown)
+
+		pc.AllocateTargetBitrates(100000)
+		assert.Equal(t, 50000, audio.TargetBitrate())
+		assert.Equal(t, 50000, video.TargetBitrate())
+
+		assert.NoError(t, audio.SetMaxBitrate(20000))
+		pc.AllocateTargetBitrates(100000)
+		assert.Equal(t, 20000, audio.TargetBitrate())
+		assert.Equal(t, 80000, video.TargetBitrate())
+
+		assert.NoError(t, pc.Close())
+	})
+
+	t.Run("audio first", func(t *testing.T) {
+		pc, audio, video := newSenders(t, BitrateAllocationPolicyAudioFirst)
+
+		changes := make(chan int, 1)
+		video.OnTargetBitrateChange(func(bitrate int) {
+			changes <- bitrate
+		})
+
+		pc.AllocateTargetBitrates(1000000)
+		assert.Equal(t, defaultAudioReservedBitrate, audio.TargetBitrate())
+		assert.Equal(t, 1000000-defaultAudioReservedBitrate, video.TargetBitrate())
+		assert.Equal(t, 1000000-defaultAudioReservedBitrate, <-changes)
+
+		pc.AllocateTargetBitrates(40000)
+		assert.Equal(t, 40000, audio.TargetBitrate())
+		assert.Equal(t, 0, video.TargetBitrate())
+		assert.Equal(t, 0, <-changes)
+
+		// Bitrate left by capped video goes to audio without a max bitrate
+		assert.NoError(t, video.SetMaxBitrate(500000))
+		pc.AllocateTargetBitrates(1000000)
+		assert.Equal(t, 500000, audio.TargetBitrate())
+		assert.Equal(t, 500000, video.TargetBitrate())
+		assert.Equal(t, 500000, <-changes)
+
+		assert.NoError(t, audio.SetMaxBitrate(32000))
+		pc.AllocateTargetBitrates(100000)
+		assert.Equal(t, 32000, audio.TargetBitrate())
+		assert.Equal(t, 68000, video.TargetBitrate())
+		assert.Equal(t, 68000, <-changes)
+
+		stats, ok := pc.GetStats().GetOutboundRTPStreamStats(video)
+		require.True(t, ok)
+		assert.Equal(t, StatsTypeOutboundRTP, stats.Type)
+		assert.Equal(t, "video", stats.Kind)
+		assert.Equal(t, float64(68000), stats.TargetBitrate)
+
+		assert.NoError(t, pc.Close())
+	})
+
+	t.Run("invalid max bitrate", func(t *testing.T) {
+		pc, audio, _ := newSenders(t, BitrateAllocationPolicyFair)
+
+		assert.ErrorIs(t, audio.SetMaxBitrate(-1), errRTPSenderInvalidMaxBitrate)
+
+		assert.NoError(t, pc.Close())
+	})
+}
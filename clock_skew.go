@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// clockSkewIntervals is the number of intervals a window is split in, the minimum
+	// offset of each interval is a point of the regression.
+	clockSkewIntervals = 32
+
+	// clockSkewMinPoints is the number of points needed before a skew is estimated.
+	clockSkewMinPoints = 8
+
+	// clockSkewMaxOffsetJump resets the estimation when the offset between the media and
+	// the arrival times changes by more, the sender restarted its timestamps.
+	clockSkewMaxOffsetJump = time.Second
+)
+
+type clockSkewPoint struct {
+	elapsed float64 // seconds since the first packet, in receiver time
+	offset  float64 // arrival time minus media time, in seconds
+}
+
+// clockSkewEstimator estimates the drift of the clock of the sender of a track from the
+// RTP timestamps and the arrival times of its packets.
+//
+// The offset between the arrival time and the media time of each packet grows linearly
+// with the skew, plus the network delay. The network delay only adds to the offset, so
+// the minimum offset of each interval of window/clockSkewIntervals is kept as the packet
+// that was delayed the least. The skew is the slope of the least squares line through the
+// minima of the last window.
+type clockSkewEstimator struct {
+	mu sync.Mutex
+
+	interval time.Duration
+
+	started       bool
+	ssrc          SSRC
+	start         time.Time
+	lastTimestamp uint32
+	mediaTime     int64 // RTP timestamp units since the first packet
+
+	intervalIndex int64
+	intervalMin   clockSkewPoint
+	hasMin        bool
+	points        []clockSkewPoint
+
+	skew        float64
+	hasEstimate bool
+}
+
+// newClockSkewEstimator returns an estimator over the given window, or nil if window is 0.
+func newClockSkewEstimator(window time.Duration) *clockSkewEstimator {
+	if window <= 0 {
+		return nil
+	}
+
+	interval := window / clockSkewIntervals
+	if interval <= 0 {
+		interval = 1
+	}
+
+	return &clockSkewEstimator{interval: interval}
+}
+
+// update adds the marshaled RTP packet b, of a codec sampled at clockRate, that arrived at now.
+func (e *clockSkewEstimator) update(b []byte, clockRate uint32, now time.Time) {
+	if e == nil || clockRate == 0 || len(b) < rtpHeaderMinLength {
+		return
+	}
+
+	timestamp := binary.BigEndian.Uint32(b[4:8])
+	ssrc := SSRC(binary.BigEndian.Uint32(b[8:12]))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.started || ssrc != e.ssrc {
+		e.reset(ssrc, timestamp, now)
+	}
+
+	e.mediaTime += int64(int32(timestamp - e.lastTimestamp)) //nolint:gosec // G115
+	e.lastTimestamp = timestamp
+
+	since := now.Sub(e.start)
+	point := clockSkewPoint{
+		elapsed: since.Seconds(),
+		offset:  since.Seconds() - float64(e.mediaTime)/float64(clockRate),
+	}
+
+	if index := int64(since / e.interval); index != e.intervalIndex {
+		if e.hasMin && !e.addPoint(e.intervalMin) {
+			e.reset(ssrc, timestamp, now)
+
+			return
+		}
+		e.intervalIndex = index
+		e.hasMin = false
+	}
+
+	if !e.hasMin || point.offset < e.intervalMin.offset {
+		e.intervalMin = point
+		e.hasMin = true
+	}
+}
+
+func (e *clockSkewEstimator) reset(ssrc SSRC, timestamp uint32, now time.Time) {
+	e.started = true
+	e.ssrc = ssrc
+	e.start = now
+	e.lastTimestamp = timestamp
+	e.mediaTime = 0
+	e.intervalIndex = 0
+	e.hasMin = false
+	e.points = e.points[:0]
+	e.skew = 0
+	e.hasEstimate = false
+}
+
+// addPoint adds the minimum of an interval and updates the estimate, it returns false if
+// the offset jumped and the estimation must restart.
+func (e *clockSkewEstimator) addPoint(point clockSkewPoint) bool {
+	if n := len(e.points); n != 0 &&
+		math.Abs(point.offset-e.points[n-1].offset) > clockSkewMaxOffsetJump.Seconds() {
+		return false
+	}
+
+	if len(e.points) == clockSkewIntervals {
+		copy(e.points, e.points[1:])
+		e.points = e.points[:clockSkewIntervals-1]
+	}
+	e.points = append(e.points, point)
+
+	if len(e.points) < clockSkewMinPoints {
+		return true
+	}
+
+	var sumElapsed, sumOffset float64
+	for _, p := range e.points {
+		sumElapsed += p.elapsed
+		sumOffset += p.offset
+	}
+	count := float64(len(e.points))
+	meanElapsed, meanOffset := sumElapsed/count, sumOffset/count
+
+	var covariance, variance float64
+	for _, p := range e.points {
+		covariance += (p.elapsed - meanElapsed) * (p.offset - meanOffset)
+		variance += (p.elapsed - meanElapsed) * (p.elapsed - meanElapsed)
+	}
+	if variance == 0 {
+		return true
+	}
+
+	// The offset decreases when the media time advances faster than the arrival time,
+	// that is when the clock of the sender is fast.
+	e.skew = -covariance / variance * 1e6
+	e.hasEstimate = true
+
+	return true
+}
+
+func (e *clockSkewEstimator) estimate() (float64, bool) {
+	if e == nil {
+		return 0, false
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.skew, e.hasEstimate
+}
+
+// EstimatedClockSkew returns the estimated drift of the clock of the sender of the track
+// relative to the local clock, in parts per million: a positive skew means the sender
+// produces media faster than it is played out locally. ok is false until enough packets
+// were read, or if the estimation is disabled, see SettingEngine.SetClockSkewEstimationWindow.
+//
+// The skew is the slope of the offset between the RTP timestamps and the arrival times of
+// the packets, fitted with least squares on the packets that were delayed the least by the
+// network over the estimation window. Packets are timed when they are read, the track must
+// be read continuously. An adaptive jitter buffer can account for it by scaling its target
+// delay or by resampling the media by 1 + skew / 1e6. It is also reported by the
+// EstimatedClockSkew of the InboundRTPStreamStats of the track.
+func (t *TrackRemote) EstimatedClockSkew() (skew float64, ok bool) {
+	return t.clockSkew.estimate()
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockSkewEstimator(t *testing.T) {
+	const clockRate = 48000
+
+	marshal := func(ssrc, timestamp uint32) []byte {
+		b, err := (&rtp.Packet{
+			Header:  rtp.Header{Version: 2, SSRC: ssrc, Timestamp: timestamp},
+			Payload: []byte{0x00},
+		}).Marshal()
+		require.NoError(t, err)
+
+		return b
+	}
+
+	// send feeds packets of 20ms of media from a sender whose clock is off by skew ppm, each
+	// delayed by up to 30ms by the network.
+	send := func(estimator *clockSkewEstimator, ssrc uint32, start time.Time, skew float64, duration time.Duration) {
+		random := rand.New(rand.NewSource(1)) //nolint:gosec
+		timestamp := uint32(random.Int31())
+		packetDuration := float64(20*time.Millisecond) / (1 + skew/1e6)
+		for i := 0; time.Duration(float64(i)*packetDuration) < duration; i++ {
+			delay := time.Duration(random.Int63n(int64(30 * time.Millisecond)))
+			arrival := start.Add(time.Duration(float64(i)*packetDuration) + delay)
+			estimator.update(marshal(ssrc, timestamp), clockRate, arrival)
+			timestamp += clockRate / 50
+		}
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		estimator := newClockSkewEstimator(0)
+		assert.Nil(t, estimator)
+		estimator.update(marshal(1, 0), clockRate, time.Now())
+		_, ok := estimator.estimate()
+		assert.False(t, ok)
+	})
+
+	for _, skew := range []float64{100, -250, 0} {
+		estimator := newClockSkewEstimator(time.Minute)
+		send(estimator, 1, time.Now(), skew, 10*time.Second)
+		_, ok := estimator.estimate()
+		assert.False(t, ok)
+
+		estimator = newClockSkewEstimator(time.Minute)
+		send(estimator, 1, time.Now(), skew, 2*time.Minute)
+		estimate, ok := estimator.estimate()
+		assert.True(t, ok)
+		assert.InDelta(t, skew, estimate, 10)
+	}
+
+	t.Run("Reset", func(t *testing.T) {
+		estimator := newClockSkewEstimator(time.Minute)
+		start := time.Now()
+		send(estimator, 1, start, 100, time.Minute)
+		_, ok := estimator.estimate()
+		assert.True(t, ok)
+
+		// A new SSRC restarts the estimation
+		send(estimator, 2, start.Add(time.Minute), 100, 10*time.Second)
+		_, ok = estimator.estimate()
+		assert.False(t, ok)
+	})
+}
+
+func TestPacketArrivalTime(t *testing.T) {
+	arrival := time.Now().Add(-time.Second)
+	attributes := interceptor.Attributes{}
+	attributes.Set(AttributeArrivalTime, arrival)
+	assert.Equal(t, arrival, packetArrivalTime(attributes))
+
+	// Without the attribute the packet arrives when it is read
+	assert.WithinDuration(t, time.Now(), packetArrivalTime(nil), time.Second/2)
+}
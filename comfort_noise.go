@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/internal/util"
+)
+
+// comfortNoiseLevelMask is the mask of the noise level in the first byte of a comfort
+// noise payload, its most significant bit is reserved.
+const comfortNoiseLevelMask = 0x7F
+
+// ComfortNoise is the payload of a comfort noise packet of RFC 3389. A sender that stops
+// sending audio during silence sends a comfort noise packet at the start of the silence,
+// and possibly updates later, so that the receiver plays a noise matching the background
+// instead of silence.
+//
+// Comfort noise is meant for codecs without their own silence handling, such as PCMU,
+// PCMA and G722, it is negotiated with a codec of MimeTypeCN with the clock rate of the
+// audio codec. MediaEngine.RegisterDefaultCodecs registers it with rtp.PayloadTypeCN at
+// 8000, for the codecs above. Opus has its own discontinuous transmission and comfort
+// noise, browsers don't negotiate CN with it.
+type ComfortNoise struct {
+	// NoiseLevel is the level of the noise, in -dBov from 0 to 127.
+	NoiseLevel uint8
+
+	// ReflectionCoefficients describe the spectrum of the noise, quantized as in
+	// section 4 of RFC 3389. Without coefficients the noise is white.
+	ReflectionCoefficients []uint8
+}
+
+// Marshal returns the payload of a comfort noise packet.
+func (c ComfortNoise) Marshal() ([]byte, error) {
+	if c.NoiseLevel > comfortNoiseLevelMask {
+		return nil, errComfortNoiseInvalid
+	}
+
+	return append([]byte{c.NoiseLevel}, c.ReflectionCoefficients...), nil
+}
+
+// Unmarshal parses the payload of a comfort noise packet.
+func (c *ComfortNoise) Unmarshal(payload []byte) error {
+	if len(payload) == 0 {
+		return errComfortNoiseInvalid
+	}
+
+	c.NoiseLevel = payload[0] & comfortNoiseLevelMask
+	c.ReflectionCoefficients = append([]uint8{}, payload[1:]...)
+
+	return nil
+}
+
+func isComfortNoise(codec RTPCodecParameters) bool {
+	return strings.EqualFold(codec.MimeType, MimeTypeCN)
+}
+
+// IsComfortNoise reports whether the packet with header is a comfort noise packet of the
+// track, whose payload is a ComfortNoise. Comfort noise packets share the SSRC and the
+// sequence numbers of the audio and are returned by Read and ReadRTP, so they are not
+// mistaken for lost packets. They never change the Codec of the track.
+func (t *TrackRemote) IsComfortNoise(header *rtp.Header) bool {
+	payloadType := PayloadType(header.PayloadType)
+	if payloadType == t.PayloadType() {
+		return isComfortNoise(t.Codec())
+	}
+
+	// Payload types of audio and video can collide, comfort noise is audio
+	for _, codec := range t.receiver.api.mediaEngine.getCodecsByKind(RTPCodecTypeAudio) {
+		if codec.PayloadType == payloadType && isComfortNoise(codec) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WriteComfortNoise writes a comfort noise packet to the TrackLocalStaticSample, in place of
+// the audio of the next duration, see ComfortNoise. The packet continues the sequence
+// numbers and the timestamps of the samples. A codec of MimeTypeCN with the clock rate of
+// the track must have been negotiated with every PeerConnection the track is bound to,
+// nothing is sent otherwise.
+func (s *TrackLocalStaticSample) WriteComfortNoise(noise ComfortNoise, duration time.Duration) error {
+	payload, err := noise.Marshal()
+	if err != nil {
+		return err
+	}
+
+	s.rtpTrack.writeGroup.lock()
+	defer s.rtpTrack.writeGroup.unlock()
+
+	s.rtpTrack.mu.RLock()
+	defer s.rtpTrack.mu.RUnlock()
+
+	if s.packetizer == nil {
+		return nil
+	}
+
+	clockRate := uint32(s.clockRate)
+	payloadTypes := make([]PayloadType, len(s.rtpTrack.bindings))
+	for i, b := range s.rtpTrack.bindings {
+		found := false
+		for _, codec := range b.codecs {
+			if isComfortNoise(codec) && codec.ClockRate == clockRate {
+				payloadTypes[i], found = codec.PayloadType, true
+
+				break
+			}
+		}
+		if !found {
+			return errComfortNoiseNotNegotiated
+		}
+	}
+
+	samples := uint32(duration.Seconds() * s.clockRate)
+	header := rtp.Header{
+		Version:        2,
+		SequenceNumber: s.sequencer.NextSequenceNumber(),
+		Timestamp:      s.nextTimestamp.Load(),
+	}
+	s.packetizer.SkipSamples(samples)
+	s.nextTimestamp.Store(header.Timestamp + samples)
+
+	writeErrs := []error{}
+	for i, b := range s.rtpTrack.bindings {
+		header.SSRC = uint32(b.ssrc)
+		header.PayloadType = uint8(payloadTypes[i])
+		if _, writeErr := b.writeStream.WriteRTP(&header, payload); writeErr != nil {
+			writeErrs = append(writeErrs, writeErr)
+		}
+	}
+
+	return util.FlattenErrs(writeErrs)
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComfortNoise_Marshal(t *testing.T) {
+	payload, err := ComfortNoise{NoiseLevel: 40, ReflectionCoefficients: []uint8{1, 2}}.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{40, 1, 2}, payload)
+
+	_, err = ComfortNoise{NoiseLevel: 128}.Marshal()
+	assert.ErrorIs(t, err, errComfortNoiseInvalid)
+
+	noise := ComfortNoise{}
+	require.NoError(t, noise.Unmarshal([]byte{0x80 | 40}))
+	assert.Equal(t, ComfortNoise{NoiseLevel: 40, ReflectionCoefficients: []uint8{}}, noise)
+	assert.ErrorIs(t, noise.Unmarshal(nil), errComfortNoiseInvalid)
+}
+
+func TestTrackLocalStaticSample_WriteComfortNoiseNotNegotiated(t *testing.T) {
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypePCMU}, "audio", "pion")
+	require.NoError(t, err)
+	assert.NoError(t, track.WriteComfortNoise(ComfortNoise{}, time.Second), "nothing is sent before Bind")
+
+	pcmu := RTPCodecParameters{RTPCodecCapability: RTPCodecCapability{MimeType: MimeTypePCMU, ClockRate: 8000}}
+	track.rtpTrack.bindings = append(track.rtpTrack.bindings, trackBinding{
+		writeStream: &recordingTrackLocalWriter{},
+		codecs:      []RTPCodecParameters{pcmu},
+	})
+	track.sequencer = rtp.NewRandomSequencer()
+	require.NoError(t, track.createPacketizer(pcmu))
+
+	assert.ErrorIs(t, track.WriteComfortNoise(ComfortNoise{}, time.Second), errComfortNoiseNotNegotiated)
+}
+
+func TestTrackLocalStaticSample_WriteComfortNoise(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	// Comfort noise is one of the default codecs
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypePCMU}, "audio", "pion")
+	require.NoError(t, err)
+	_, err = pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	remoteTracks := make(chan *TrackRemote, 1)
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	pcAnswer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		remoteTracks <- trackRemote
+		onTrackFiredFunc()
+	})
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	sample := media.Sample{Data: make([]byte, 160), Duration: 20 * time.Millisecond}
+	for onTrackFired.Err() == nil {
+		time.Sleep(20 * time.Millisecond)
+		assert.NoError(t, track.WriteSample(sample))
+	}
+	remoteTrack := <-remoteTracks
+
+	packets := make(chan *rtp.Packet, 100)
+	go func() {
+		defer close(packets)
+		for {
+			pkt, _, readErr := remoteTrack.ReadRTP()
+			if readErr != nil {
+				return
+			}
+			packets <- pkt
+		}
+	}()
+
+	// A silence of 100ms between two samples
+	assert.NoError(t, track.WriteSample(sample))
+	assert.NoError(t, track.WriteComfortNoise(ComfortNoise{NoiseLevel: 40}, 100*time.Millisecond))
+	assert.NoError(t, track.WriteSample(sample))
+
+	var previous *rtp.Packet
+	for pkt := range packets {
+		if !remoteTrack.IsComfortNoise(&pkt.Header) {
+			previous = pkt
+
+			continue
+		}
+
+		require.NotNil(t, previous)
+		assert.Equal(t, uint8(rtp.PayloadTypeCN), pkt.PayloadType)
+		assert.Equal(t, previous.SequenceNumber+1, pkt.SequenceNumber)
+		assert.Equal(t, previous.Timestamp+160, pkt.Timestamp)
+
+		noise := ComfortNoise{}
+		assert.NoError(t, noise.Unmarshal(pkt.Payload))
+		assert.Equal(t, uint8(40), noise.NoiseLevel)
+
+		next := <-packets
+		require.NotNil(t, next)
+		assert.False(t, remoteTrack.IsComfortNoise(&next.Header))
+		assert.Equal(t, pkt.SequenceNumber+1, next.SequenceNumber)
+		assert.Equal(t, pkt.Timestamp+800, next.Timestamp)
+
+		break
+	}
+	assert.Equal(t, MimeTypePCMU, remoteTrack.Codec().MimeType)
+
+	closePairNow(t, pcOffer, pcAnswer)
+	for pkt := range packets {
+		assert.Equal(t, uint8(rtp.PayloadTypePCMU), pkt.PayloadType)
+	}
+}
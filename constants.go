@@ -4,6 +4,7 @@
 package webrtc
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/pion/dtls/v3"
@@ -38,10 +39,31 @@ const (
 
 	sdpAttributeSimulcast = "simulcast"
 
+	sdpAttributeBundleOnly = "bundle-only"
+
+	sdpSimulcastDirectionRecv = "recv"
+
+	sdpSemanticTokenSimulcast = "SIM"
+
 	outboundMTU = 1200
 
+	// Receive buffer limits of SRTP and SRTCP streams, matching the defaults of pion/srtp.
+	srtpBufferSize  = 1000 * 1000
+	srtcpBufferSize = 100 * 1000
+
+	// Bytes of received messages a DataChannel.AsConn queues before it stops reading the DataChannel.
+	dataChannelConnQueueSize = 1000 * 1000
+
 	rtpPayloadTypeBitmask = 0x7F
 
+	rtpHeaderMinLength = 12
+
+	// Bitrate reserved for audio tracks without a max bitrate by BitrateAllocationPolicyAudioFirst.
+	defaultAudioReservedBitrate = 64000
+
+	// Sequence number moves larger than this are reported as an RTPAnomaly.
+	rtpAnomalySequenceJumpThreshold = 3000
+
 	incomingUnhandledRTPSsrc = "Incoming unhandled RTP ssrc(%d), OnTrack will not be fired. %v"
 
 	generatedCertificateOrigin = "WebRTC"
@@ -55,6 +77,21 @@ const (
 	// AttributeRtxSequenceNumber is the interceptor attribute added when
 	// Read() returns an RTX packet containing the RTX stream sequence number.
 	AttributeRtxSequenceNumber = "rtx_sequence_number"
+	// AttributeArrivalTime is the interceptor attribute set on every received
+	// RTP packet to the time.Time at which the SRTP session decrypted it, before
+	// the packet is buffered and before interceptors, such as a jitter buffer,
+	// see it. It is read from time.Now and carries the monotonic clock reading,
+	// compare it with time.Since or Time.Sub rather than with wall clock times.
+	AttributeArrivalTime = "arrival_time"
+	// AttributeAbsSendTime is the interceptor attribute set on received RTP
+	// packets that carry the abs-send-time extension, see
+	// ConfigureAbsSendTimeHeaderExtension. It is the time.Time at which the
+	// remote sent the packet, estimated from its arrival time as the extension
+	// only stores 64 seconds. It is a remote wall clock time.
+	AttributeAbsSendTime = "abs_send_time"
+	// AttributeTrackMetadata is the interceptor.StreamInfo attribute set on
+	// local streams whose TrackLocal implements TrackLocalWithMetadata.
+	AttributeTrackMetadata = "track_metadata"
 )
 
 func defaultSrtpProtectionProfiles() []dtls.SRTPProtectionProfile {
@@ -64,3 +101,94 @@ func defaultSrtpProtectionProfiles() []dtls.SRTPProtectionProfile {
 		dtls.SRTP_AES128_CM_HMAC_SHA1_80,
 	}
 }
+
+const (
+	srtpAuthTagLength80 = 80
+	srtpAuthTagLength32 = 32
+)
+
+// srtpProfileAuthTagLengths pairs the 80 and 32 bits variants of the protection profiles
+// whose tag length can be chosen.
+//
+//nolint:gochecknoglobals
+var srtpProfileAuthTagLengths = [][2]dtls.SRTPProtectionProfile{
+	{dtls.SRTP_AES128_CM_HMAC_SHA1_80, dtls.SRTP_AES128_CM_HMAC_SHA1_32},
+	{dtls.SRTP_AES256_CM_SHA1_80, dtls.SRTP_AES256_CM_SHA1_32},
+	{dtls.SRTP_NULL_HMAC_SHA1_80, dtls.SRTP_NULL_HMAC_SHA1_32},
+}
+
+// srtpProtectionProfileName returns the name of a protection profile in the "Profile" column
+// of the IANA DTLS-SRTP protection profile registry. The AES256_CM profiles are not
+// registered, they keep their pion/dtls name.
+func srtpProtectionProfileName(profile dtls.SRTPProtectionProfile) string {
+	switch profile {
+	case dtls.SRTP_AES128_CM_HMAC_SHA1_80:
+		return "SRTP_AES128_CM_HMAC_SHA1_80"
+	case dtls.SRTP_AES128_CM_HMAC_SHA1_32:
+		return "SRTP_AES128_CM_HMAC_SHA1_32"
+	case dtls.SRTP_AES256_CM_SHA1_80:
+		return "SRTP_AES256_CM_SHA1_80"
+	case dtls.SRTP_AES256_CM_SHA1_32:
+		return "SRTP_AES256_CM_SHA1_32"
+	case dtls.SRTP_NULL_HMAC_SHA1_80:
+		return "SRTP_NULL_HMAC_SHA1_80"
+	case dtls.SRTP_NULL_HMAC_SHA1_32:
+		return "SRTP_NULL_HMAC_SHA1_32"
+	case dtls.SRTP_AEAD_AES_128_GCM:
+		return "SRTP_AEAD_AES_128_GCM"
+	case dtls.SRTP_AEAD_AES_256_GCM:
+		return "SRTP_AEAD_AES_256_GCM"
+	default:
+		return fmt.Sprintf("0x%04x", uint16(profile))
+	}
+}
+
+// srtpProfileAuthTagLength returns the length in bits of the authentication tag of profile,
+// or 0 if it is fixed by the profile.
+func srtpProfileAuthTagLength(profile dtls.SRTPProtectionProfile) uint {
+	for _, variants := range srtpProfileAuthTagLengths {
+		switch profile {
+		case variants[0]:
+			return srtpAuthTagLength80
+		case variants[1]:
+			return srtpAuthTagLength32
+		}
+	}
+
+	return 0
+}
+
+// srtpProfilesWithAuthTagLength replaces the profiles by their variant with the tag length
+// bits, see SettingEngine.SetSRTPAuthenticationTagLength.
+func srtpProfilesWithAuthTagLength(
+	profiles []dtls.SRTPProtectionProfile,
+	bits uint,
+) []dtls.SRTPProtectionProfile {
+	variant := 0
+	if bits == srtpAuthTagLength32 {
+		variant = 1
+	}
+
+	var adjustable, fixed []dtls.SRTPProtectionProfile
+	seen := map[dtls.SRTPProtectionProfile]bool{}
+	for _, profile := range profiles {
+		for _, variants := range srtpProfileAuthTagLengths {
+			if profile == variants[0] || profile == variants[1] {
+				profile = variants[variant]
+			}
+		}
+		if seen[profile] {
+			continue
+		}
+		seen[profile] = true
+
+		if srtpProfileAuthTagLength(profile) == 0 && bits == srtpAuthTagLength32 {
+			// The tags of the AEAD profiles are longer than the shortened ones
+			fixed = append(fixed, profile)
+		} else {
+			adjustable = append(adjustable, profile)
+		}
+	}
+
+	return append(adjustable, fixed...)
+}
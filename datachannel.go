@@ -7,6 +7,7 @@
 package webrtc
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -27,19 +28,28 @@ var errSCTPNotEstablished = errors.New("SCTP not established")
 type DataChannel struct {
 	mu sync.RWMutex
 
-	statsID                    string
-	label                      string
-	ordered                    bool
-	maxPacketLifeTime          *uint16
-	maxRetransmits             *uint16
-	protocol                   string
-	negotiated                 bool
-	id                         *uint16
-	readyState                 atomic.Value // DataChannelState
-	bufferedAmountLowThreshold uint64
-	detachCalled               bool
-	readLoopActive             chan struct{}
-	isGracefulClosed           bool
+	statsID                     string
+	label                       string
+	ordered                     bool
+	maxPacketLifeTime           *uint16
+	maxRetransmits              *uint16
+	protocol                    string
+	negotiated                  bool
+	id                          *uint16
+	readyState                  atomic.Value // DataChannelState
+	bufferedAmountLowThreshold  uint64
+	bufferedAmountHighThreshold uint64
+	detachCalled                bool
+	readLoopActive              chan struct{}
+	isGracefulClosed            bool
+
+	// Serializes the writes of Send and SendText with the reads of the BufferedAmount around
+	// them, so that a crossing of the BufferedAmountHighThreshold is seen by a single write
+	writeMu sync.Mutex
+
+	// Set while CloseGracefully waits for the BufferedAmount to drain, the threshold and
+	// handler of the application are only applied to the SCTP stream once it is done
+	draining bool
 
 	// The binaryType represents attribute MUST, on getting, return the value to
 	// which it was last set. On setting, if the new value is either the string
@@ -49,18 +59,22 @@ type DataChannel struct {
 	// "blob". This attribute controls how binary data is exposed to scripts.
 	// binaryType                 string
 
-	onMessageHandler    func(DataChannelMessage)
-	openHandlerOnce     sync.Once
-	onOpenHandler       func()
-	dialHandlerOnce     sync.Once
-	onDialHandler       func()
-	onCloseHandler      func()
-	onBufferedAmountLow func()
-	onErrorHandler      func(error)
+	onMessageHandler     func(DataChannelMessage)
+	openHandlerOnce      sync.Once
+	onOpenHandler        func()
+	dialHandlerOnce      sync.Once
+	onDialHandler        func()
+	onCloseHandler       func()
+	onBufferedAmountLow  func()
+	onBufferedAmountHigh func()
+	onErrorHandler       func(error)
 
 	sctpTransport *SCTPTransport
 	dataChannel   *datachannel.DataChannel
 
+	// set by AsConn
+	conn *dataChannelConn
+
 	// A reference to the associated api object used by this datachannel
 	api *API
 	log logging.LeveledLogger
@@ -294,11 +308,25 @@ func (d *DataChannel) onClose() {
 	handler := d.onCloseHandler
 	d.mu.RUnlock()
 
+	d.closeConn()
+
 	if handler != nil {
 		go handler()
 	}
 }
 
+// closeConn closes the net.Conn returned by AsConn, if any, which unblocks the read loop if it
+// is waiting for room in the queue of the net.Conn.
+func (d *DataChannel) closeConn() {
+	d.mu.RLock()
+	conn := d.conn
+	d.mu.RUnlock()
+
+	if conn != nil {
+		conn.close()
+	}
+}
+
 // OnMessage sets an event handler which is invoked on a binary
 // message arrival over the sctp transport from a remote peer.
 // OnMessage can currently receive messages up to 16384 bytes
@@ -314,6 +342,7 @@ func (d *DataChannel) OnMessage(f func(msg DataChannelMessage)) {
 func (d *DataChannel) onMessage(msg DataChannelMessage) {
 	d.mu.RLock()
 	handler := d.onMessageHandler
+	conn := d.conn
 	if d.isGracefulClosed {
 		d.mu.RUnlock()
 
@@ -321,6 +350,10 @@ func (d *DataChannel) onMessage(msg DataChannelMessage) {
 	}
 	d.mu.RUnlock()
 
+	if conn != nil {
+		conn.push(msg.Data)
+	}
+
 	if handler == nil {
 		return
 	}
@@ -395,6 +428,24 @@ func (d *DataChannel) onError(err error) {
 	}
 }
 
+// readLoopRunning returns true if the goroutine started by readLoop hasn't exited yet.
+func (d *DataChannel) readLoopRunning() bool {
+	d.mu.RLock()
+	readLoopActive := d.readLoopActive
+	d.mu.RUnlock()
+
+	if readLoopActive == nil {
+		return false
+	}
+
+	select {
+	case <-readLoopActive:
+		return false
+	default:
+		return true
+	}
+}
+
 func (d *DataChannel) readLoop() {
 	defer func() {
 		d.mu.Lock()
@@ -443,9 +494,7 @@ func (d *DataChannel) Send(data []byte) error {
 		return err
 	}
 
-	_, err = d.dataChannel.WriteDataChannel(data, false)
-
-	return err
+	return d.write(data, false)
 }
 
 // SendText sends the text message to the DataChannel peer.
@@ -455,9 +504,34 @@ func (d *DataChannel) SendText(s string) error {
 		return err
 	}
 
-	_, err = d.dataChannel.WriteDataChannel([]byte(s), true)
+	return d.write([]byte(s), true)
+}
+
+// write sends a message and fires OnBufferedAmountHigh if the BufferedAmount
+// crossed the BufferedAmountHighThreshold.
+func (d *DataChannel) write(data []byte, isString bool) error {
+	d.mu.RLock()
+	threshold := d.bufferedAmountHighThreshold
+	handler := d.onBufferedAmountHigh
+	d.mu.RUnlock()
+
+	// Acknowledgments only lower the BufferedAmount in between, so a crossing seen here was
+	// caused by this write
+	d.writeMu.Lock()
+	before := d.dataChannel.BufferedAmount()
+	if _, err := d.dataChannel.WriteDataChannel(data, isString); err != nil {
+		d.writeMu.Unlock()
+
+		return err
+	}
+	crossed := before <= threshold && d.dataChannel.BufferedAmount() > threshold
+	d.writeMu.Unlock()
 
-	return err
+	if threshold != 0 && handler != nil && crossed {
+		handler()
+	}
+
+	return nil
 }
 
 func (d *DataChannel) ensureOpen() error {
@@ -542,6 +616,78 @@ func (d *DataChannel) GracefulClose() error {
 	return d.close(true)
 }
 
+// CloseGracefully closes the DataChannel once the messages already sent are delivered, so a
+// transfer isn't truncated by Close dropping the data still buffered. The DataChannel moves to
+// the closing state, its Send methods then fail, and the SCTP stream is only reset once the
+// BufferedAmount reached zero, that is once the remote acknowledged every message. If ctx is
+// done before, the stream is reset anyway, dropping the data left, and the error of ctx is
+// returned.
+//
+// On an unreliable DataChannel, with MaxRetransmits or MaxPacketLifeTime, the messages whose
+// retransmissions are abandoned are also removed from the BufferedAmount, the wait then only
+// ensures that every message was sent as many times as the channel allows, not that it was
+// received. Unlike GracefulClose it doesn't wait for the goroutines of the DataChannel.
+//
+// The wait relies on the buffered amount low event of the SCTP stream, with a zero threshold:
+// while CloseGracefully waits, the handler set with OnBufferedAmountLow is only invoked once
+// the BufferedAmount is zero. The BufferedAmountLowThreshold and the handler of the
+// application are restored afterwards.
+func (d *DataChannel) CloseGracefully(ctx context.Context) error {
+	if d.ReadyState() == DataChannelStateOpen {
+		d.setReadyState(DataChannelStateClosing)
+	}
+
+	drained := make(chan struct{}, 1)
+	d.mu.Lock()
+	readLoopActive := d.readLoopActive
+	if d.dataChannel != nil && !d.draining {
+		d.draining = true
+		d.dataChannel.SetBufferedAmountLowThreshold(0)
+		d.dataChannel.OnBufferedAmountLow(func() {
+			d.mu.RLock()
+			handler := d.onBufferedAmountLow
+			d.mu.RUnlock()
+
+			if handler != nil {
+				handler()
+			}
+			notifyChannel(drained)
+		})
+		defer d.restoreBufferedAmountLow()
+	}
+	d.mu.Unlock()
+
+	// The read loop ends when the stream or the association is closed, nothing can drain then
+	var ctxErr error
+	if d.ReadyState() == DataChannelStateClosing && d.BufferedAmount() != 0 {
+		select {
+		case <-ctx.Done():
+			ctxErr = ctx.Err()
+		case <-drained:
+		case <-readLoopActive:
+		}
+	}
+
+	if err := d.close(false); err != nil {
+		return err
+	}
+
+	return ctxErr
+}
+
+// restoreBufferedAmountLow applies the BufferedAmountLowThreshold and the OnBufferedAmountLow
+// handler of the application to the SCTP stream again, once CloseGracefully is done.
+func (d *DataChannel) restoreBufferedAmountLow() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.draining = false
+	if d.dataChannel != nil {
+		d.dataChannel.SetBufferedAmountLowThreshold(d.bufferedAmountLowThreshold)
+		d.dataChannel.OnBufferedAmountLow(d.onBufferedAmountLow)
+	}
+}
+
 // Normally, close only stops writes from happening, so graceful=true
 // will wait for reads to be finished based on underlying SCTP association
 // closure or a SCTP reset stream from the other side. This is safe to call
@@ -560,6 +706,9 @@ func (d *DataChannel) close(shouldGracefullyClose bool) error {
 	haveSctpTransport := d.dataChannel != nil
 	d.mu.Unlock()
 
+	// The messages received from now on are discarded, see onMessage
+	d.closeConn()
+
 	if d.ReadyState() == DataChannelStateClosed {
 		return nil
 	}
@@ -680,7 +829,7 @@ func (d *DataChannel) BufferedAmountLowThreshold() uint64 {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
-	if d.dataChannel == nil {
+	if d.dataChannel == nil || d.draining {
 		return d.bufferedAmountLowThreshold
 	}
 
@@ -695,7 +844,7 @@ func (d *DataChannel) SetBufferedAmountLowThreshold(th uint64) {
 
 	d.bufferedAmountLowThreshold = th
 
-	if d.dataChannel != nil {
+	if d.dataChannel != nil && !d.draining {
 		d.dataChannel.SetBufferedAmountLowThreshold(th)
 	}
 }
@@ -708,11 +857,44 @@ func (d *DataChannel) OnBufferedAmountLow(f func()) {
 	defer d.mu.Unlock()
 
 	d.onBufferedAmountLow = f
-	if d.dataChannel != nil {
+	if d.dataChannel != nil && !d.draining {
 		d.dataChannel.OnBufferedAmountLow(f)
 	}
 }
 
+// BufferedAmountHighThreshold represents the threshold at which the
+// bufferedAmount is considered to be high. When the bufferedAmount increases
+// from equal or below this threshold to above it, OnBufferedAmountHigh fires.
+// Together with BufferedAmountLowThreshold this allows a producer to pause
+// at the high and resume at the low threshold. The threshold is set to 0 by
+// default, which disables the event. Only messages sent with Send and
+// SendText are checked, writes to a detached DataChannel are not.
+func (d *DataChannel) BufferedAmountHighThreshold() uint64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.bufferedAmountHighThreshold
+}
+
+// SetBufferedAmountHighThreshold is used to update the threshold.
+// See BufferedAmountHighThreshold().
+func (d *DataChannel) SetBufferedAmountHighThreshold(th uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.bufferedAmountHighThreshold = th
+}
+
+// OnBufferedAmountHigh sets an event handler which is invoked when
+// the number of bytes of outgoing data becomes higher than the
+// BufferedAmountHighThreshold. The handler is called from Send or SendText.
+func (d *DataChannel) OnBufferedAmountHigh(f func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.onBufferedAmountHigh = f
+}
+
 func (d *DataChannel) getStatsID() string {
 	d.mu.Lock()
 	defer d.mu.Unlock()
@@ -0,0 +1,201 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pion/transport/v3/deadline"
+)
+
+// AsConn returns a net.Conn that reads and writes the messages of the DataChannel. It
+// eases the use of a DataChannel by code written against the standard networking APIs.
+//
+// The net.Conn keeps the message boundaries, it is not a byte stream: each Write sends one
+// binary message and each Read returns exactly one message. If b is too small for the
+// message, Read returns the start of it with io.ErrShortBuffer and the rest is discarded.
+// Text messages are returned like binary ones. Messages are returned in arrival order, which
+// is the send order for ordered DataChannels only, unordered or partially reliable channels
+// may reorder or lose messages.
+//
+// Received messages are queued until they are read, handlers set with OnMessage are still
+// called. The queue holds up to 1 MB of messages: once it is full the DataChannel stops reading
+// until Read makes room, the messages then wait in the SCTP receive buffer, and the remote stops
+// sending once that is full too. An unread net.Conn thus stalls the OnMessage handlers of its
+// DataChannel, and the other DataChannels of the PeerConnection once the SCTP receive buffer is
+// full. A single message larger than the queue is still queued, alone. Read returns io.EOF once
+// the DataChannel is closed and the queue is drained, the messages received after a local close
+// are discarded. Close closes the DataChannel. AsConn must be called before the DataChannel is opened, from the
+// OnOpen handler at the latest, to not miss messages, and returns the same net.Conn on every
+// call. It returns an error for detached DataChannels, use the ReadWriteCloser returned by
+// Detach instead.
+func (d *DataChannel) AsConn() (net.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.detachCalled || d.api.settingEngine.detach.DataChannels {
+		return nil, errDataChannelAsConnDetached
+	}
+
+	if d.conn == nil {
+		d.conn = &dataChannelConn{
+			dataChannel:   d,
+			notify:        make(chan struct{}, 1),
+			popped:        make(chan struct{}, 1),
+			readDeadline:  deadline.New(),
+			writeDeadline: deadline.New(),
+		}
+		if d.ReadyState() == DataChannelStateClosed {
+			d.conn.close()
+		}
+	}
+
+	return d.conn, nil
+}
+
+// dataChannelAddr is the net.Addr of a DataChannel, the label identifies it.
+type dataChannelAddr struct {
+	label string
+}
+
+func (a *dataChannelAddr) Network() string { return "datachannel" }
+
+func (a *dataChannelAddr) String() string { return a.label }
+
+// dataChannelConn implements net.Conn on top of a DataChannel, see DataChannel.AsConn.
+type dataChannelConn struct {
+	dataChannel *DataChannel
+
+	mu       sync.Mutex
+	messages [][]byte
+	size     int
+	closed   bool
+	notify   chan struct{}
+	popped   chan struct{}
+
+	readDeadline, writeDeadline *deadline.Deadline
+}
+
+// push queues a received message, it blocks the read loop of the DataChannel while the queue
+// is full until Read makes room or the net.Conn is closed.
+func (c *dataChannelConn) push(data []byte) {
+	c.mu.Lock()
+	for !c.closed && len(c.messages) != 0 && c.size+len(data) > dataChannelConnQueueSize {
+		c.mu.Unlock()
+		<-c.popped
+		c.mu.Lock()
+	}
+	if !c.closed {
+		c.messages = append(c.messages, data)
+		c.size += len(data)
+	}
+	c.mu.Unlock()
+
+	notifyChannel(c.notify)
+}
+
+// close makes Read return io.EOF once the queued messages are read, and push drop the next
+// messages.
+func (c *dataChannelConn) close() {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	notifyChannel(c.notify)
+	notifyChannel(c.popped)
+}
+
+func (c *dataChannelConn) Read(b []byte) (int, error) {
+	for {
+		select {
+		case <-c.readDeadline.Done():
+			return 0, os.ErrDeadlineExceeded
+		default:
+		}
+
+		c.mu.Lock()
+		if len(c.messages) > 0 {
+			message := c.messages[0]
+			c.messages[0] = nil
+			c.messages = c.messages[1:]
+			c.size -= len(message)
+			c.mu.Unlock()
+
+			notifyChannel(c.popped)
+
+			n := copy(b, message)
+			if n < len(message) {
+				return n, io.ErrShortBuffer
+			}
+
+			return n, nil
+		}
+		closed := c.closed
+		c.mu.Unlock()
+
+		if closed {
+			return 0, io.EOF
+		}
+
+		select {
+		case <-c.readDeadline.Done():
+			return 0, os.ErrDeadlineExceeded
+		case <-c.notify:
+		}
+	}
+}
+
+func (c *dataChannelConn) Write(b []byte) (int, error) {
+	select {
+	case <-c.writeDeadline.Done():
+		return 0, os.ErrDeadlineExceeded
+	default:
+	}
+
+	if err := c.dataChannel.Send(b); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+func (c *dataChannelConn) Close() error {
+	return c.dataChannel.Close()
+}
+
+func (c *dataChannelConn) LocalAddr() net.Addr {
+	return &dataChannelAddr{label: c.dataChannel.Label()}
+}
+
+func (c *dataChannelConn) RemoteAddr() net.Addr {
+	return &dataChannelAddr{label: c.dataChannel.Label()}
+}
+
+func (c *dataChannelConn) SetDeadline(t time.Time) error {
+	c.readDeadline.Set(t)
+	c.writeDeadline.Set(t)
+
+	return nil
+}
+
+func (c *dataChannelConn) SetReadDeadline(t time.Time) error {
+	c.readDeadline.Set(t)
+
+	return nil
+}
+
+// SetWriteDeadline only fails writes started after the deadline, as messages are
+// queued by the SCTP association and Write never blocks.
+func (c *dataChannelConn) SetWriteDeadline(t time.Time) error {
+	c.writeDeadline.Set(t)
+
+	return nil
+}
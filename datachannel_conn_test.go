@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/deadline"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataChannel_AsConn(t *testing.T) {
+	lim := test.TimeOut(time.Second * 10)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	offerPC, answerPC, err := newPair()
+	assert.NoError(t, err)
+
+	answerConn := make(chan io.ReadWriteCloser, 1)
+	answerPC.OnDataChannel(func(d *DataChannel) {
+		if d.Label() != expectedLabel {
+			return
+		}
+
+		conn, connErr := d.AsConn()
+		assert.NoError(t, connErr)
+		answerConn <- conn
+	})
+
+	dc, err := offerPC.CreateDataChannel(expectedLabel, nil)
+	assert.NoError(t, err)
+
+	offerConn, err := dc.AsConn()
+	assert.NoError(t, err)
+
+	again, err := dc.AsConn()
+	assert.NoError(t, err)
+	assert.Equal(t, offerConn, again)
+	assert.Equal(t, expectedLabel, offerConn.LocalAddr().String())
+
+	opened := make(chan struct{})
+	dc.OnOpen(func() { close(opened) })
+
+	assert.NoError(t, signalPair(offerPC, answerPC))
+	<-opened
+
+	_, err = offerConn.Write([]byte("hello"))
+	assert.NoError(t, err)
+	_, err = offerConn.Write([]byte("world"))
+	assert.NoError(t, err)
+
+	remote := <-answerConn
+
+	buf := make([]byte, 16)
+	n, err := remote.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(buf[:n]))
+
+	n, err = remote.Read(buf[:3])
+	assert.ErrorIs(t, err, io.ErrShortBuffer)
+	assert.Equal(t, "wor", string(buf[:n]))
+
+	assert.NoError(t, offerConn.SetReadDeadline(time.Now().Add(50*time.Millisecond)))
+	_, err = offerConn.Read(buf)
+	assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+	assert.NoError(t, remote.Close())
+	assert.NoError(t, offerConn.SetReadDeadline(time.Time{}))
+	_, err = offerConn.Read(buf)
+	assert.ErrorIs(t, err, io.EOF)
+
+	closePairNow(t, offerPC, answerPC)
+}
+
+func TestDataChannel_AsConnDetached(t *testing.T) {
+	s := SettingEngine{}
+	s.DetachDataChannels()
+	offerPC, answerPC, err := NewAPI(WithSettingEngine(s)).newPair(Configuration{})
+	assert.NoError(t, err)
+
+	dc, err := offerPC.CreateDataChannel("data", nil)
+	assert.NoError(t, err)
+
+	_, err = dc.AsConn()
+	assert.ErrorIs(t, err, errDataChannelAsConnDetached)
+
+	closePairNow(t, offerPC, answerPC)
+}
+
+func TestDataChannelConn_QueueLimit(t *testing.T) {
+	lim := test.TimeOut(time.Second * 10)
+	defer lim.Stop()
+
+	conn := &dataChannelConn{
+		notify:       make(chan struct{}, 1),
+		popped:       make(chan struct{}, 1),
+		readDeadline: deadline.New(),
+	}
+
+	// A message larger than the queue is queued when the queue is empty
+	conn.push(make([]byte, dataChannelConnQueueSize+1))
+
+	pushed := make(chan struct{})
+	go func() {
+		conn.push([]byte{1})
+		close(pushed)
+	}()
+
+	select {
+	case <-pushed:
+		assert.Fail(t, "push didn't wait for room in the queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	buf := make([]byte, dataChannelConnQueueSize+1)
+	n, err := conn.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, dataChannelConnQueueSize+1, n)
+	<-pushed
+
+	// Closing unblocks push, the next messages are dropped
+	conn.push(make([]byte, dataChannelConnQueueSize-1))
+	dropped := make(chan struct{})
+	go func() {
+		conn.push([]byte{2})
+		close(dropped)
+	}()
+	conn.close()
+	<-dropped
+
+	n, err = conn.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1}, buf[:n])
+	n, err = conn.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, dataChannelConnQueueSize-1, n)
+	_, err = conn.Read(buf)
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -174,6 +174,8 @@ func TestDataChannelParamters_Go(t *testing.T) {
 		assert.Equal(t, uint64(0), dc.BufferedAmount(), "should match")
 		dc.SetBufferedAmountLowThreshold(1500)
 		assert.Equal(t, uint64(1500), dc.BufferedAmountLowThreshold(), "should match")
+		dc.SetBufferedAmountHighThreshold(3000)
+		assert.Equal(t, uint64(3000), dc.BufferedAmountHighThreshold(), "should match")
 	})
 }
 
@@ -334,6 +336,82 @@ func TestDataChannelBufferedAmount(t *testing.T) { //nolint:cyclop
 
 		assert.True(t, nCbs > 0, "callback should be made at least once")
 	})
+
+	t.Run("high threshold", func(t *testing.T) {
+		report := test.CheckRoutines(t)
+		defer report()
+
+		var nHighCbs uint32
+		buf := make([]byte, 1000)
+
+		offerPC, answerPC, err := newPair()
+		assert.NoError(t, err)
+
+		done := make(chan bool)
+
+		dc, err := offerPC.CreateDataChannel(expectedLabel, nil)
+		assert.NoError(t, err)
+
+		dc.SetBufferedAmountHighThreshold(2500)
+		dc.OnBufferedAmountHigh(func() {
+			atomic.AddUint32(&nHighCbs, 1)
+		})
+
+		dc.OnOpen(func() {
+			// Nothing is sent yet, so the first messages stay in the buffer
+			for i := 0; i < 10; i++ {
+				assert.NoError(t, dc.Send(buf), "Failed to send string on data channel")
+			}
+			assert.Greater(t, dc.BufferedAmount(), dc.BufferedAmountHighThreshold())
+			done <- true
+		})
+
+		assert.NoError(t, signalPair(offerPC, answerPC))
+
+		closePair(t, offerPC, answerPC, done)
+
+		assert.Equal(t, uint32(1), atomic.LoadUint32(&nHighCbs), "callback should be made once when crossing upward")
+	})
+
+	t.Run("high threshold concurrent sends", func(t *testing.T) {
+		report := test.CheckRoutines(t)
+		defer report()
+
+		var nHighCbs uint32
+		buf := make([]byte, 1000)
+
+		offerPC, answerPC, err := newPair()
+		assert.NoError(t, err)
+
+		done := make(chan bool)
+
+		dc, err := offerPC.CreateDataChannel(expectedLabel, nil)
+		assert.NoError(t, err)
+
+		dc.SetBufferedAmountHighThreshold(2500)
+		dc.OnBufferedAmountHigh(func() {
+			atomic.AddUint32(&nHighCbs, 1)
+		})
+
+		dc.OnOpen(func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					assert.NoError(t, dc.Send(buf), "Failed to send string on data channel")
+				}()
+			}
+			wg.Wait()
+			done <- true
+		})
+
+		assert.NoError(t, signalPair(offerPC, answerPC))
+
+		closePair(t, offerPC, answerPC, done)
+
+		assert.Equal(t, uint32(1), atomic.LoadUint32(&nHighCbs), "concurrent sends cross the threshold once")
+	})
 }
 
 func TestEOF(t *testing.T) { //nolint:cyclop
@@ -752,6 +830,91 @@ func TestDataChannelClose(t *testing.T) {
 	})
 }
 
+func TestDataChannel_CloseGracefully(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	const (
+		messageSize  = 32 * 1024
+		messageCount = 64
+	)
+
+	offerPC, answerPC, err := newPair()
+	assert.NoError(t, err)
+
+	var received atomic.Int64
+	closed := make(chan struct{})
+	answerPC.OnDataChannel(func(d *DataChannel) {
+		if d.Label() != "data" {
+			return
+		}
+		d.OnMessage(func(msg DataChannelMessage) {
+			received.Add(int64(len(msg.Data)))
+		})
+		d.OnClose(func() {
+			close(closed)
+		})
+	})
+
+	dc, err := offerPC.CreateDataChannel("data", nil)
+	assert.NoError(t, err)
+	opened := make(chan struct{})
+	dc.OnOpen(func() {
+		close(opened)
+	})
+
+	assert.NoError(t, signalPair(offerPC, answerPC))
+	<-opened
+
+	// The handler of the application is still invoked
+	var lowCalled atomic.Bool
+	dc.SetBufferedAmountLowThreshold(messageSize)
+	dc.OnBufferedAmountLow(func() {
+		lowCalled.Store(true)
+	})
+
+	for i := 0; i < messageCount; i++ {
+		assert.NoError(t, dc.Send(make([]byte, messageSize)))
+	}
+	assert.NotZero(t, dc.BufferedAmount())
+
+	assert.NoError(t, dc.CloseGracefully(context.Background()))
+	assert.Zero(t, dc.BufferedAmount())
+	assert.True(t, lowCalled.Load())
+
+	// The threshold and handler of the application are restored
+	assert.Equal(t, uint64(messageSize), dc.BufferedAmountLowThreshold())
+	dc.mu.RLock()
+	assert.Equal(t, uint64(messageSize), dc.dataChannel.BufferedAmountLowThreshold())
+	dc.mu.RUnlock()
+	assert.ErrorIs(t, dc.Send([]byte("late")), io.ErrClosedPipe)
+
+	<-closed
+	assert.Equal(t, int64(messageSize*messageCount), received.Load())
+
+	// The stream is reset when ctx is done, even with data left
+	dc, err = offerPC.CreateDataChannel("canceled", nil)
+	assert.NoError(t, err)
+	opened = make(chan struct{})
+	dc.OnOpen(func() {
+		close(opened)
+	})
+	<-opened
+
+	for i := 0; i < messageCount; i++ {
+		assert.NoError(t, dc.Send(make([]byte, messageSize)))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, dc.CloseGracefully(ctx), context.Canceled)
+	assert.NotEqual(t, DataChannelStateOpen, dc.ReadyState())
+
+	closePairNow(t, offerPC, answerPC)
+}
+
 func TestDataChannel_DetachErrors(t *testing.T) {
 	t.Run("error errDetachNotEnabled", func(t *testing.T) {
 		s := SettingEngine{}
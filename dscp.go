@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"net"
+
+	"github.com/pion/logging"
+	"github.com/pion/transport/v3"
+	"github.com/pion/transport/v3/stdnet"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// DSCP is a Differentiated Services Code Point as defined in RFC 2474. It is
+// carried in the upper six bits of the IPv4 ToS and IPv6 Traffic Class fields.
+type DSCP uint8
+
+// DSCP values recommended for WebRTC traffic by RFC 8837: EF for high priority audio, AF41
+// and AF42 for interactive video, AF11 and AF21 for data.
+const (
+	DSCPDefault             DSCP = 0
+	DSCPAF11                DSCP = 10
+	DSCPAF21                DSCP = 18
+	DSCPAF41                DSCP = 34
+	DSCPAF42                DSCP = 36
+	DSCPExpeditedForwarding DSCP = 46
+)
+
+// dscpNet is a transport.Net that marks every UDP socket it creates with a DSCP value.
+type dscpNet struct {
+	transport.Net
+
+	dscp DSCP
+	log  logging.LeveledLogger
+}
+
+func newDSCPNet(base transport.Net, dscp DSCP, log logging.LeveledLogger) (*dscpNet, error) {
+	if base == nil {
+		var err error
+		if base, err = stdnet.NewNet(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &dscpNet{Net: base, dscp: dscp, log: log}, nil
+}
+
+func (n *dscpNet) ListenPacket(network string, address string) (net.PacketConn, error) {
+	conn, err := n.Net.ListenPacket(network, address)
+	if err == nil {
+		n.track(conn)
+	}
+
+	return conn, err
+}
+
+func (n *dscpNet) ListenUDP(network string, locAddr *net.UDPAddr) (transport.UDPConn, error) {
+	conn, err := n.Net.ListenUDP(network, locAddr)
+	if err == nil {
+		n.track(conn)
+	}
+
+	return conn, err
+}
+
+func (n *dscpNet) track(conn interface{}) {
+	netConn, ok := conn.(net.Conn)
+	if !ok {
+		n.log.Warnf("Unable to set DSCP on %T", conn)
+
+		return
+	}
+
+	if err := setConnDSCP(netConn, n.dscp); err != nil {
+		n.log.Warnf("Failed to set DSCP %d: %v", n.dscp, err)
+	}
+}
+
+func setConnDSCP(conn net.Conn, dscp DSCP) error {
+	tos := int(dscp) << 2
+	if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok && addr.IP.To4() == nil && addr.IP != nil {
+		return ipv6.NewConn(conn).SetTrafficClass(tos)
+	}
+
+	return ipv4.NewConn(conn).SetTOS(tos)
+}
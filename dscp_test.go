@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"net"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/pion/ice/v4"
+	"github.com/pion/logging"
+	"github.com/pion/transport/v3"
+	"github.com/pion/transport/v3/stdnet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/ipv4"
+)
+
+func TestDSCPNet(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("setting the IP ToS is not supported on Windows")
+	}
+
+	dscpNet, err := newDSCPNet(nil, DSCPAF41, logging.NewDefaultLoggerFactory().NewLogger("test"))
+	require.NoError(t, err)
+
+	conn, err := dscpNet.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+
+	tos, err := ipv4.NewConn(conn.(net.Conn)).TOS() //nolint:forcetypeassert
+	require.NoError(t, err)
+	assert.Equal(t, int(DSCPAF41)<<2, tos)
+
+	assert.NoError(t, conn.Close())
+}
+
+// connRecordingNet records the UDP sockets it creates.
+type connRecordingNet struct {
+	transport.Net
+
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func (n *connRecordingNet) ListenUDP(network string, locAddr *net.UDPAddr) (transport.UDPConn, error) {
+	conn, err := n.Net.ListenUDP(network, locAddr)
+	if err == nil {
+		n.mu.Lock()
+		n.conns = append(n.conns, conn)
+		n.mu.Unlock()
+	}
+
+	return conn, err
+}
+
+func TestSettingEngine_SetDSCP(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("setting the IP ToS is not supported on Windows")
+	}
+
+	base, err := stdnet.NewNet()
+	require.NoError(t, err)
+	recordingNet := &connRecordingNet{Net: base}
+
+	settingEngine := SettingEngine{}
+	settingEngine.SetNet(recordingNet)
+	settingEngine.SetDSCP(DSCPExpeditedForwarding)
+	settingEngine.SetNetworkTypes([]NetworkType{NetworkTypeUDP4})
+	settingEngine.SetICEMulticastDNSMode(ice.MulticastDNSModeDisabled)
+
+	pc, err := NewAPI(WithSettingEngine(settingEngine)).NewPeerConnection(Configuration{})
+	require.NoError(t, err)
+
+	_, err = pc.AddTransceiverFromKind(RTPCodecTypeAudio)
+	require.NoError(t, err)
+
+	offer, err := pc.CreateOffer(nil)
+	require.NoError(t, err)
+	gatherComplete := GatheringCompletePromise(pc)
+	require.NoError(t, pc.SetLocalDescription(offer))
+	<-gatherComplete
+
+	recordingNet.mu.Lock()
+	conns := append([]net.Conn{}, recordingNet.conns...)
+	recordingNet.mu.Unlock()
+	require.NotEmpty(t, conns)
+
+	for _, conn := range conns {
+		tos, err := ipv4.NewConn(conn).TOS()
+		require.NoError(t, err)
+		assert.Equal(t, int(DSCPExpeditedForwarding)<<2, tos)
+	}
+
+	assert.NoError(t, pc.Close())
+}
@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/pion/dtls/v3/pkg/protocol"
+	"github.com/pion/dtls/v3/pkg/protocol/handshake"
+	"github.com/pion/webrtc/v4/internal/mux"
+)
+
+// DTLSHandshakeEventType is the kind of a DTLSHandshakeEvent.
+type DTLSHandshakeEventType int
+
+const (
+	// DTLSHandshakeEventTypeUnknown is the enum's zero-value.
+	DTLSHandshakeEventTypeUnknown DTLSHandshakeEventType = iota
+
+	// DTLSHandshakeEventTypeStarted is fired when the DTLSTransport starts the
+	// handshake, once ICE is connected.
+	DTLSHandshakeEventTypeStarted
+
+	// DTLSHandshakeEventTypeFlightSent is fired for every datagram of a handshake
+	// flight that is sent, retransmissions included.
+	DTLSHandshakeEventTypeFlightSent
+
+	// DTLSHandshakeEventTypeFlightReceived is fired for every datagram of a
+	// handshake flight that is received, retransmissions included.
+	DTLSHandshakeEventTypeFlightReceived
+
+	// DTLSHandshakeEventTypeCompleted is fired when the handshake succeeded and
+	// the remote certificate was verified.
+	DTLSHandshakeEventTypeCompleted
+
+	// DTLSHandshakeEventTypeFailed is fired when the handshake or the verification
+	// of its result failed.
+	DTLSHandshakeEventTypeFailed
+)
+
+// This is done this way because of a linter.
+const (
+	dtlsHandshakeEventTypeStartedStr        = "started"
+	dtlsHandshakeEventTypeFlightSentStr     = "flight-sent"
+	dtlsHandshakeEventTypeFlightReceivedStr = "flight-received"
+	dtlsHandshakeEventTypeCompletedStr      = "completed"
+	dtlsHandshakeEventTypeFailedStr         = "failed"
+)
+
+func (t DTLSHandshakeEventType) String() string {
+	switch t {
+	case DTLSHandshakeEventTypeStarted:
+		return dtlsHandshakeEventTypeStartedStr
+	case DTLSHandshakeEventTypeFlightSent:
+		return dtlsHandshakeEventTypeFlightSentStr
+	case DTLSHandshakeEventTypeFlightReceived:
+		return dtlsHandshakeEventTypeFlightReceivedStr
+	case DTLSHandshakeEventTypeCompleted:
+		return dtlsHandshakeEventTypeCompletedStr
+	case DTLSHandshakeEventTypeFailed:
+		return dtlsHandshakeEventTypeFailedStr
+	default:
+		return ErrUnknownType.Error()
+	}
+}
+
+// DTLSHandshakeEvent describes the progress of the DTLS handshake, see
+// DTLSTransport.OnHandshakeEvent.
+type DTLSHandshakeEvent struct {
+	Type      DTLSHandshakeEventType
+	Timestamp time.Time
+
+	// Role is the local DTLS role of the handshake.
+	Role DTLSRole
+
+	// Messages are the handshake messages of the datagram, for the flight events. The
+	// encrypted Finished message is reported without being decrypted.
+	Messages []handshake.Type
+	// ChangeCipherSpec is true if the datagram of a flight event carries a
+	// ChangeCipherSpec message.
+	ChangeCipherSpec bool
+
+	// Err is the reason of a DTLSHandshakeEventTypeFailed event.
+	Err error
+}
+
+// OnHandshakeEvent sets a handler that is fired as the DTLS handshake progresses: when it
+// starts, for every flight sent and received, and when it completes or fails. A connection
+// that stays in the connecting state without flights received is usually blocked by the
+// network or the remote peer, while ICE already connected. The handler is invoked
+// synchronously by the goroutine running the handshake and must not block.
+func (t *DTLSTransport) OnHandshakeEvent(f func(DTLSHandshakeEvent)) {
+	t.onHandshakeEventHandler.Store(f)
+}
+
+func (t *DTLSTransport) onHandshakeEvent(event DTLSHandshakeEvent) {
+	handler, ok := t.onHandshakeEventHandler.Load().(func(DTLSHandshakeEvent))
+	if !ok || handler == nil {
+		return
+	}
+
+	event.Timestamp = time.Now()
+	handler(event)
+}
+
+func (t *DTLSTransport) hasHandshakeEventHandler() bool {
+	handler, ok := t.onHandshakeEventHandler.Load().(func(DTLSHandshakeEvent))
+
+	return ok && handler != nil
+}
+
+// dtlsHandshakeObserver is the net.PacketConn given to the DTLS connection, it reports the
+// flights of the handshake until done.
+type dtlsHandshakeObserver struct {
+	*mux.Endpoint
+
+	transport *DTLSTransport
+	role      DTLSRole
+	done      atomic.Bool
+}
+
+func (o *dtlsHandshakeObserver) ReadFrom(p []byte) (int, net.Addr, error) {
+	n, addr, err := o.Endpoint.ReadFrom(p)
+	if err == nil {
+		o.observe(DTLSHandshakeEventTypeFlightReceived, p[:n])
+	}
+
+	return n, addr, err
+}
+
+func (o *dtlsHandshakeObserver) WriteTo(p []byte, addr net.Addr) (int, error) {
+	o.observe(DTLSHandshakeEventTypeFlightSent, p)
+
+	return o.Endpoint.WriteTo(p, addr)
+}
+
+func (o *dtlsHandshakeObserver) observe(eventType DTLSHandshakeEventType, datagram []byte) {
+	if o.done.Load() || !o.transport.hasHandshakeEventHandler() {
+		return
+	}
+
+	messages, changeCipherSpec := parseDTLSFlight(datagram)
+	if len(messages) == 0 && !changeCipherSpec {
+		return
+	}
+
+	o.transport.onHandshakeEvent(DTLSHandshakeEvent{
+		Type:             eventType,
+		Role:             o.role,
+		Messages:         messages,
+		ChangeCipherSpec: changeCipherSpec,
+	})
+}
+
+const (
+	dtlsRecordHeaderLength    = 13
+	dtlsHandshakeHeaderLength = 12
+)
+
+// parseDTLSFlight returns the handshake messages of the DTLS records of datagram. Fragments
+// are reported once, by the one at offset 0.
+func parseDTLSFlight(datagram []byte) (messages []handshake.Type, changeCipherSpec bool) {
+	for len(datagram) >= dtlsRecordHeaderLength {
+		contentType := protocol.ContentType(datagram[0])
+		epoch := binary.BigEndian.Uint16(datagram[3:5])
+		length := int(binary.BigEndian.Uint16(datagram[11:13]))
+		if len(datagram) < dtlsRecordHeaderLength+length {
+			return messages, changeCipherSpec
+		}
+		body := datagram[dtlsRecordHeaderLength : dtlsRecordHeaderLength+length]
+		datagram = datagram[dtlsRecordHeaderLength+length:]
+
+		switch {
+		case contentType == protocol.ContentTypeChangeCipherSpec:
+			changeCipherSpec = true
+		case contentType == protocol.ContentTypeHandshake && epoch != 0:
+			// Handshake messages after ChangeCipherSpec are encrypted, Finished is the only one
+			messages = append(messages, handshake.TypeFinished)
+		case contentType == protocol.ContentTypeHandshake:
+			for len(body) >= dtlsHandshakeHeaderLength {
+				fragmentOffset := uint32(body[6])<<16 | uint32(body[7])<<8 | uint32(body[8])
+				fragmentLength := int(uint32(body[9])<<16 | uint32(body[10])<<8 | uint32(body[11]))
+				if fragmentOffset == 0 {
+					messages = append(messages, handshake.Type(body[0]))
+				}
+				if len(body) < dtlsHandshakeHeaderLength+fragmentLength {
+					break
+				}
+				body = body[dtlsHandshakeHeaderLength+fragmentLength:]
+			}
+		}
+	}
+
+	return messages, changeCipherSpec
+}
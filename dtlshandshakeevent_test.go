@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/dtls/v3/pkg/protocol/handshake"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDTLSHandshakeEventType_String(t *testing.T) {
+	testCases := []struct {
+		eventType      DTLSHandshakeEventType
+		expectedString string
+	}{
+		{DTLSHandshakeEventTypeUnknown, ErrUnknownType.Error()},
+		{DTLSHandshakeEventTypeStarted, "started"},
+		{DTLSHandshakeEventTypeFlightSent, "flight-sent"},
+		{DTLSHandshakeEventTypeFlightReceived, "flight-received"},
+		{DTLSHandshakeEventTypeCompleted, "completed"},
+		{DTLSHandshakeEventTypeFailed, "failed"},
+	}
+
+	for i, testCase := range testCases {
+		assert.Equal(t, testCase.expectedString, testCase.eventType.String(), "testCase: %d %v", i, testCase)
+	}
+}
+
+func TestParseDTLSFlight(t *testing.T) {
+	record := func(contentType byte, epoch byte, body ...byte) []byte {
+		return append([]byte{contentType, 0xfe, 0xfd, 0x00, epoch, 0, 0, 0, 0, 0, 0, 0, byte(len(body))}, body...)
+	}
+	message := func(messageType handshake.Type, fragmentOffset byte, fragment ...byte) []byte {
+		return append([]byte{
+			byte(messageType), 0, 0, 8, 0, 0, 0, 0, fragmentOffset, 0, 0, byte(len(fragment)),
+		}, fragment...)
+	}
+
+	for _, testCase := range []struct {
+		name             string
+		datagram         []byte
+		messages         []handshake.Type
+		changeCipherSpec bool
+	}{
+		{"empty", nil, nil, false},
+		{"truncated record", record(22, 0, message(handshake.TypeClientHello, 0, 1, 2)...)[:20], nil, false},
+		{
+			"one record with several messages",
+			record(22, 0, append(
+				message(handshake.TypeServerHello, 0, 1, 2),
+				message(handshake.TypeServerHelloDone, 0)...,
+			)...),
+			[]handshake.Type{handshake.TypeServerHello, handshake.TypeServerHelloDone},
+			false,
+		},
+		{"fragment", record(22, 0, message(handshake.TypeCertificate, 4, 1, 2, 3, 4)...), nil, false},
+		{
+			"change cipher spec and finished",
+			append(append(
+				record(22, 0, message(handshake.TypeClientKeyExchange, 0, 1)...),
+				record(20, 0, 1)...),
+				record(22, 1, 0xde, 0xad, 0xbe, 0xef)...,
+			),
+			[]handshake.Type{handshake.TypeClientKeyExchange, handshake.TypeFinished},
+			true,
+		},
+		{"application data", record(23, 1, 1, 2, 3), nil, false},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			messages, changeCipherSpec := parseDTLSFlight(testCase.datagram)
+			assert.Equal(t, testCase.messages, messages)
+			assert.Equal(t, testCase.changeCipherSpec, changeCipherSpec)
+		})
+	}
+}
+
+func TestDTLSTransport_OnHandshakeEvent(t *testing.T) {
+	lim := test.TimeOut(time.Second * 10)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	var (
+		mu     sync.Mutex
+		events = map[*PeerConnection][]DTLSHandshakeEvent{}
+	)
+	for _, pc := range []*PeerConnection{pcOffer, pcAnswer} {
+		pc := pc
+		transport := pc.SCTP().Transport()
+		transport.OnHandshakeEvent(func(event DTLSHandshakeEvent) {
+			// The getters can be used from the handler
+			if event.Type == DTLSHandshakeEventTypeCompleted {
+				assert.Equal(t, DTLSTransportStateConnected, transport.State())
+				assert.NotEmpty(t, transport.GetRemoteCertificate())
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			events[pc] = append(events[pc], event)
+		})
+	}
+
+	connected := untilConnectionState(PeerConnectionStateConnected, pcOffer, pcAnswer)
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	connected.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for pc, pcEvents := range events {
+		require.GreaterOrEqual(t, len(pcEvents), 4)
+		assert.Equal(t, DTLSHandshakeEventTypeStarted, pcEvents[0].Type)
+		assert.Equal(t, DTLSHandshakeEventTypeCompleted, pcEvents[len(pcEvents)-1].Type)
+
+		var sent []handshake.Type
+		for _, event := range pcEvents {
+			assert.Equal(t, pc.SCTP().Transport().role(), event.Role)
+			assert.False(t, event.Timestamp.IsZero())
+			if event.Type == DTLSHandshakeEventTypeFlightSent {
+				sent = append(sent, event.Messages...)
+			}
+		}
+
+		if pcEvents[0].Role == DTLSRoleClient {
+			assert.Contains(t, sent, handshake.TypeClientHello)
+		} else {
+			assert.Contains(t, sent, handshake.TypeServerHello)
+		}
+		assert.Contains(t, sent, handshake.TypeFinished)
+	}
+	assert.Len(t, events, 2)
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
+
+func TestDTLSTransport_OnHandshakeEventFailed(t *testing.T) {
+	lim := test.TimeOut(time.Second * 10)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	failed := make(chan DTLSHandshakeEvent, 1)
+	transport := pcAnswer.SCTP().Transport()
+	transport.OnHandshakeEvent(func(event DTLSHandshakeEvent) {
+		if event.Type != DTLSHandshakeEventTypeFailed {
+			return
+		}
+
+		// The getters can be used from the handler
+		assert.Equal(t, DTLSTransportStateFailed, transport.State())
+		failed <- event
+	})
+
+	invalidFingerprint := regexp.MustCompile(`sha-256 (.*?)\r`)
+	require.NoError(t, signalPairWithModification(pcOffer, pcAnswer, func(sdp string) string {
+		return invalidFingerprint.ReplaceAllString(sdp, "sha-256 "+strings.Repeat("AA:", 31)+"AA\r")
+	}))
+
+	event := <-failed
+	assert.ErrorIs(t, event.Err, errNoMatchingCertificateFingerprint)
+	assert.False(t, event.Timestamp.IsZero())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
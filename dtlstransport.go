@@ -14,6 +14,7 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -24,7 +25,9 @@ import (
 	"github.com/pion/interceptor"
 	"github.com/pion/logging"
 	"github.com/pion/rtcp"
+	"github.com/pion/sdp/v3"
 	"github.com/pion/srtp/v3"
+	"github.com/pion/transport/v3/packetio"
 	"github.com/pion/webrtc/v4/internal/mux"
 	"github.com/pion/webrtc/v4/internal/util"
 	"github.com/pion/webrtc/v4/pkg/rtcerr"
@@ -43,9 +46,11 @@ type DTLSTransport struct {
 	remoteCertificate     []byte
 	state                 DTLSTransportState
 	srtpProtectionProfile srtp.ProtectionProfile
+	dtlsSRTPProfile       dtls.SRTPProtectionProfile
 
-	onStateChangeHandler   func(DTLSTransportState)
-	internalOnCloseHandler func()
+	onStateChangeHandler    func(DTLSTransportState)
+	internalOnCloseHandler  func()
+	onHandshakeEventHandler atomic.Value // func(DTLSHandshakeEvent)
 
 	conn *dtls.Conn
 
@@ -54,12 +59,53 @@ type DTLSTransport struct {
 	simulcastStreams            []simulcastStreamPair
 	srtpReady                   chan struct{}
 
+	streamBuffers     map[streamBufferKey]io.ReadWriteCloser
+	streamBuffersLock sync.Mutex
+
 	dtlsMatcher mux.MatchFunc
 
+	keyFrameRequests keyFrameRequestLimiter
+	rtcpPaused       atomicBool
+
+	// Transceivers whose RTCP is disabled, see RTPTransceiver.SetRTCPDisabled
+	rtcpDisabled     map[*RTPTransceiver]struct{}
+	rtcpDisabledLock sync.RWMutex
+
 	api *API
 	log logging.LeveledLogger
 }
 
+type streamBufferKey struct {
+	packetType packetio.BufferPacketType
+	ssrc       uint32
+}
+
+// streamBuffer is the buffer handed to the SRTP session, it forgets the buffer it wraps when
+// the stream closes it so that the streamBuffers of the DTLSTransport don't grow forever.
+type streamBuffer struct {
+	io.ReadWriteCloser
+
+	onClose func()
+}
+
+func (b *streamBuffer) Close() error {
+	b.onClose()
+
+	return b.ReadWriteCloser.Close()
+}
+
+// SetReadDeadline forwards the deadline of srtp.ReadStreamSRTP.SetReadDeadline, if the wrapped
+// buffer supports one.
+func (b *streamBuffer) SetReadDeadline(deadline time.Time) error {
+	if buffer, ok := b.ReadWriteCloser.(interface {
+		SetReadDeadline(time.Time) error
+	}); ok {
+		return buffer.SetReadDeadline(deadline)
+	}
+
+	return nil
+}
+
 type simulcastStreamPair struct {
 	srtp  *srtp.ReadStreamSRTP
 	srtcp *srtp.ReadStreamSRTCP
@@ -70,12 +116,13 @@ type simulcastStreamPair struct {
 // meant to be used together with the basic WebRTC API.
 func (api *API) NewDTLSTransport(transport *ICETransport, certificates []Certificate) (*DTLSTransport, error) {
 	trans := &DTLSTransport{
-		iceTransport: transport,
-		api:          api,
-		state:        DTLSTransportStateNew,
-		dtlsMatcher:  mux.MatchDTLS,
-		srtpReady:    make(chan struct{}),
-		log:          api.settingEngine.LoggerFactory.NewLogger("DTLSTransport"),
+		iceTransport:  transport,
+		api:           api,
+		state:         DTLSTransportStateNew,
+		dtlsMatcher:   mux.MatchDTLS,
+		srtpReady:     make(chan struct{}),
+		streamBuffers: map[streamBufferKey]io.ReadWriteCloser{},
+		log:           api.settingEngine.LoggerFactory.NewLogger("DTLSTransport"),
 	}
 
 	if len(certificates) > 0 {
@@ -136,8 +183,25 @@ func (t *DTLSTransport) State() DTLSTransportState {
 }
 
 // WriteRTCP sends a user provided RTCP packet to the connected peer. If no peer is connected the
-// packet is discarded.
+// packet is discarded. With SettingEngine.SetKeyFrameRequestInterval, PLI and FIR packets that
+// come too early are dropped and ErrKeyFrameRequestSuppressed is returned. Every packet is
+// discarded while RTCP is paused, see PeerConnection.PauseRTCP, and the ones about transceivers
+// whose RTCP is disabled, see RTPTransceiver.SetRTCPDisabled.
 func (t *DTLSTransport) WriteRTCP(pkts []rtcp.Packet) (int, error) {
+	if t.rtcpPaused.get() {
+		return 0, nil
+	}
+
+	pkts = t.filterDisabledRTCP(pkts)
+	pkts, suppressed := t.keyFrameRequests.filter(pkts, t.api.settingEngine.keyFrameRequestInterval)
+	if len(pkts) == 0 {
+		if suppressed {
+			return 0, ErrKeyFrameRequestSuppressed
+		}
+
+		return 0, nil
+	}
+
 	raw, err := rtcp.Marshal(pkts)
 	if err != nil {
 		return 0, err
@@ -154,7 +218,12 @@ func (t *DTLSTransport) WriteRTCP(pkts []rtcp.Packet) (int, error) {
 		return 0, fmt.Errorf("%w: %v", errPeerConnWriteRTCPOpenWriteStream, err)
 	}
 
-	return writeStream.Write(raw)
+	n, err := writeStream.Write(raw)
+	if err == nil && suppressed {
+		err = ErrKeyFrameRequestSuppressed
+	}
+
+	return n, err
 }
 
 // GetLocalParameters returns the DTLS parameters of the local DTLSTransport upon construction.
@@ -185,10 +254,104 @@ func (t *DTLSTransport) GetRemoteCertificate() []byte {
 	return t.remoteCertificate
 }
 
+// SRTPProtectionProfile returns the SRTP protection profile negotiated by the DTLS
+// handshake, ok is false until the handshake completes. The profiles that can be negotiated
+// are set with SettingEngine.SetSRTPProtectionProfiles.
+func (t *DTLSTransport) SRTPProtectionProfile() (profile dtls.SRTPProtectionProfile, ok bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return t.dtlsSRTPProfile, t.dtlsSRTPProfile != 0
+}
+
+// srtpCipher returns the name of the negotiated SRTP protection profile for the
+// TransportStats, or an empty string until the handshake completes.
+func (t *DTLSTransport) srtpCipher() string {
+	profile, ok := t.SRTPProtectionProfile()
+	if !ok {
+		return ""
+	}
+
+	return srtpProtectionProfileName(profile)
+}
+
+// bufferFactory creates the receive buffer of a SRTP or SRTCP stream and
+// remembers it until the stream closes it, so that its size can be reported
+// by bufferedBytes. SRTP
+// buffers also record the arrival time of the packets, the default ones
+// switch to a trackBuffer when their RTPReceiver has a TrackBufferPolicy.
+func (t *DTLSTransport) bufferFactory(packetType packetio.BufferPacketType, ssrc uint32) io.ReadWriteCloser {
+	var buffer io.ReadWriteCloser
+	switch {
+	case t.api.settingEngine.BufferFactory != nil:
+		buffer = t.api.settingEngine.BufferFactory(packetType, ssrc)
+		if packetType == packetio.RTPBufferPacket {
+			buffer = newArrivalTimeBuffer(buffer)
+		}
+	case packetType == packetio.RTCPBufferPacket:
+		packetBuffer := packetio.NewBuffer()
+		packetBuffer.SetLimitSize(srtcpBufferSize)
+		buffer = packetBuffer
+	default:
+		buffer = newRTPStreamBuffer(srtpBufferSize)
+	}
+
+	key := streamBufferKey{packetType: packetType, ssrc: ssrc}
+	t.streamBuffersLock.Lock()
+	t.streamBuffers[key] = buffer
+	t.streamBuffersLock.Unlock()
+
+	return &streamBuffer{
+		ReadWriteCloser: buffer,
+		onClose: func() {
+			t.streamBuffersLock.Lock()
+			defer t.streamBuffersLock.Unlock()
+
+			// A new stream of the same SSRC may have replaced it
+			if t.streamBuffers[key] == buffer {
+				delete(t.streamBuffers, key)
+			}
+		},
+	}
+}
+
+// arrivalTimes returns the receive buffer of the SRTP stream of ssrc, nil if
+// it wasn't created yet.
+func (t *DTLSTransport) arrivalTimes(ssrc SSRC) arrivalTimeRecorder {
+	t.streamBuffersLock.Lock()
+	defer t.streamBuffersLock.Unlock()
+
+	switch buffer := t.streamBuffers[streamBufferKey{packetType: packetio.RTPBufferPacket, ssrc: uint32(ssrc)}].(type) {
+	case *arrivalTimeBuffer:
+		return buffer
+	case *rtpStreamBuffer:
+		return buffer
+	default:
+		return nil
+	}
+}
+
+// bufferedBytes returns the number of bytes waiting in the stream buffers.
+// Buffers returned by a custom BufferFactory are only included if they
+// report their size like packetio.Buffer does.
+func (t *DTLSTransport) bufferedBytes() int {
+	t.streamBuffersLock.Lock()
+	defer t.streamBuffersLock.Unlock()
+
+	total := 0
+	for _, buffer := range t.streamBuffers {
+		if sizer, ok := buffer.(interface{ Size() int }); ok {
+			total += sizer.Size()
+		}
+	}
+
+	return total
+}
+
 func (t *DTLSTransport) startSRTP() error {
 	srtpConfig := &srtp.Config{
 		Profile:       t.srtpProtectionProfile,
-		BufferFactory: t.api.settingEngine.BufferFactory,
+		BufferFactory: t.bufferFactory,
 		LoggerFactory: t.api.settingEngine.LoggerFactory,
 	}
 	if t.api.settingEngine.replayProtection.SRTP != nil {
@@ -231,13 +394,17 @@ func (t *DTLSTransport) startSRTP() error {
 		return fmt.Errorf("%w: %v", errDtlsKeyExtractionFailed, err)
 	}
 
-	srtpSession, err := srtp.NewSessionSRTP(t.srtpEndpoint, srtpConfig)
+	srtpSession, err := srtp.NewSessionSRTP(t.newPacketSizeLimitConn(
+		t.srtpEndpoint, "SRTP", t.api.settingEngine.maxSRTPPacketSize,
+	), srtpConfig)
 	if err != nil {
 		// nolint
 		return fmt.Errorf("%w: %v", errFailedToStartSRTP, err)
 	}
 
-	srtcpSession, err := srtp.NewSessionSRTCP(t.srtcpEndpoint, srtpConfig)
+	srtcpSession, err := srtp.NewSessionSRTCP(t.newPacketSizeLimitConn(
+		t.srtcpEndpoint, "SRTCP", t.api.settingEngine.maxSRTCPPacketSize,
+	), srtpConfig)
 	if err != nil {
 		// nolint
 		return fmt.Errorf("%w: %v", errFailedToStartSRTCP, err)
@@ -286,7 +453,7 @@ func (t *DTLSTransport) role() DTLSRole {
 	}
 
 	// Remote was auto and no explicit role was configured via SettingEngine
-	if t.iceTransport.Role() == ICERoleControlling {
+	if t.iceTransport.getDescriptionRole() == ICERoleControlling {
 		return DTLSRoleServer
 	}
 
@@ -324,11 +491,15 @@ func (t *DTLSTransport) Start(remoteParameters DTLSParameters) error { //nolint:
 				},
 			},
 			SRTPProtectionProfiles: func() []dtls.SRTPProtectionProfile {
+				profiles := defaultSrtpProtectionProfiles()
 				if len(t.api.settingEngine.srtpProtectionProfiles) > 0 {
-					return t.api.settingEngine.srtpProtectionProfiles
+					profiles = t.api.settingEngine.srtpProtectionProfiles
+				}
+				if bits := t.api.settingEngine.srtpAuthTagLength; bits != 0 {
+					profiles = srtpProfilesWithAuthTagLength(profiles, bits)
 				}
 
-				return defaultSrtpProtectionProfiles()
+				return profiles
 			}(),
 			ClientAuth:         dtls.RequireAnyClientCert,
 			LoggerFactory:      t.api.settingEngine.LoggerFactory,
@@ -345,6 +516,18 @@ func (t *DTLSTransport) Start(remoteParameters DTLSParameters) error { //nolint:
 		return err
 	}
 
+	// The Completed or Failed event of the handshake, it is fired once the lock is released
+	// as its handler may call the getters of the DTLSTransport.
+	var handshakeEvent DTLSHandshakeEvent
+
+	// failed requires the caller holds the lock.
+	failed := func(reason error) error {
+		handshakeEvent = DTLSHandshakeEvent{Type: DTLSHandshakeEventTypeFailed, Role: role, Err: reason}
+		t.onStateChange(DTLSTransportStateFailed)
+
+		return reason
+	}
+
 	if t.api.settingEngine.replayProtection.DTLS != nil {
 		dtlsConfig.ReplayProtectionWindow = int(*t.api.settingEngine.replayProtection.DTLS) //nolint:gosec // G115
 	}
@@ -364,12 +547,15 @@ func (t *DTLSTransport) Start(remoteParameters DTLSParameters) error { //nolint:
 	dtlsConfig.ServerHelloMessageHook = t.api.settingEngine.dtls.serverHelloMessageHook
 	dtlsConfig.CertificateRequestMessageHook = t.api.settingEngine.dtls.certificateRequestMessageHook
 
+	observer := &dtlsHandshakeObserver{Endpoint: dtlsEndpoint, transport: t, role: role}
+	t.onHandshakeEvent(DTLSHandshakeEvent{Type: DTLSHandshakeEventTypeStarted, Role: role})
+
 	// Connect as DTLS Client/Server, function is blocking and we
 	// must not hold the DTLSTransport lock
 	if role == DTLSRoleClient {
-		dtlsConn, err = dtls.Client(dtlsEndpoint, dtlsEndpoint.RemoteAddr(), dtlsConfig)
+		dtlsConn, err = dtls.Client(observer, dtlsEndpoint.RemoteAddr(), dtlsConfig)
 	} else {
-		dtlsConn, err = dtls.Server(dtlsEndpoint, dtlsEndpoint.RemoteAddr(), dtlsConfig)
+		dtlsConn, err = dtls.Server(observer, dtlsEndpoint.RemoteAddr(), dtlsConfig)
 	}
 
 	if err == nil {
@@ -381,21 +567,25 @@ func (t *DTLSTransport) Start(remoteParameters DTLSParameters) error { //nolint:
 		}
 	}
 
+	observer.done.Store(true)
+
+	defer func() {
+		if handshakeEvent.Type != DTLSHandshakeEventTypeUnknown {
+			t.onHandshakeEvent(handshakeEvent)
+		}
+	}()
+
 	// Re-take the lock, nothing beyond here is blocking
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
 	if err != nil {
-		t.onStateChange(DTLSTransportStateFailed)
-
-		return err
+		return failed(err)
 	}
 
 	srtpProfile, ok := dtlsConn.SelectedSRTPProtectionProfile()
 	if !ok {
-		t.onStateChange(DTLSTransportStateFailed)
-
-		return ErrNoSRTPProtectionProfile
+		return failed(ErrNoSRTPProtectionProfile)
 	}
 
 	switch srtpProfile {
@@ -405,26 +595,34 @@ func (t *DTLSTransport) Start(remoteParameters DTLSParameters) error { //nolint:
 		t.srtpProtectionProfile = srtp.ProtectionProfileAeadAes256Gcm
 	case dtls.SRTP_AES128_CM_HMAC_SHA1_80:
 		t.srtpProtectionProfile = srtp.ProtectionProfileAes128CmHmacSha1_80
+	case dtls.SRTP_AES128_CM_HMAC_SHA1_32:
+		t.srtpProtectionProfile = srtp.ProtectionProfileAes128CmHmacSha1_32
+	case dtls.SRTP_AES256_CM_SHA1_80:
+		t.srtpProtectionProfile = srtp.ProtectionProfileAes256CmHmacSha1_80
+	case dtls.SRTP_AES256_CM_SHA1_32:
+		t.srtpProtectionProfile = srtp.ProtectionProfileAes256CmHmacSha1_32
 	case dtls.SRTP_NULL_HMAC_SHA1_80:
 		t.srtpProtectionProfile = srtp.ProtectionProfileNullHmacSha1_80
+	case dtls.SRTP_NULL_HMAC_SHA1_32:
+		t.srtpProtectionProfile = srtp.ProtectionProfileNullHmacSha1_32
 	default:
-		t.onStateChange(DTLSTransportStateFailed)
+		return failed(ErrNoSRTPProtectionProfile)
+	}
 
-		return ErrNoSRTPProtectionProfile
+	// The remote must have picked one of the offered profiles
+	bits := t.api.settingEngine.srtpAuthTagLength
+	if negotiated := srtpProfileAuthTagLength(srtpProfile); bits != 0 && negotiated != 0 && negotiated != bits {
+		return failed(errSRTPAuthTagLengthMismatch)
 	}
 
 	// Check the fingerprint if a certificate was exchanged
 	connectionState, ok := dtlsConn.ConnectionState()
 	if !ok {
-		t.onStateChange(DTLSTransportStateFailed)
-
-		return errNoRemoteCertificate
+		return failed(errNoRemoteCertificate)
 	}
 
 	if len(connectionState.PeerCertificates) == 0 {
-		t.onStateChange(DTLSTransportStateFailed)
-
-		return errNoRemoteCertificate
+		return failed(errNoRemoteCertificate)
 	}
 	t.remoteCertificate = connectionState.PeerCertificates[0]
 
@@ -435,9 +633,7 @@ func (t *DTLSTransport) Start(remoteParameters DTLSParameters) error { //nolint:
 				t.log.Error(err.Error())
 			}
 
-			t.onStateChange(DTLSTransportStateFailed)
-
-			return err
+			return failed(err)
 		}
 
 		if err = t.validateFingerPrint(parsedRemoteCert); err != nil {
@@ -445,13 +641,13 @@ func (t *DTLSTransport) Start(remoteParameters DTLSParameters) error { //nolint:
 				t.log.Error(err.Error())
 			}
 
-			t.onStateChange(DTLSTransportStateFailed)
-
-			return err
+			return failed(err)
 		}
 	}
 
+	t.dtlsSRTPProfile = srtpProfile
 	t.conn = dtlsConn
+	handshakeEvent = DTLSHandshakeEvent{Type: DTLSHandshakeEventTypeCompleted, Role: role}
 	t.onStateChange(DTLSTransportStateConnected)
 
 	return t.startSRTP()
@@ -486,6 +682,10 @@ func (t *DTLSTransport) Stop() error {
 	}
 	t.onStateChange(DTLSTransportStateClosed)
 
+	t.rtcpDisabledLock.Lock()
+	t.rtcpDisabled = nil
+	t.rtcpDisabledLock.Unlock()
+
 	return util.FlattenErrs(closeErrs)
 }
 
@@ -541,11 +741,33 @@ func (t *DTLSTransport) streamsForSSRC(
 		return nil, nil, nil, nil, err
 	}
 
+	arrivals := t.arrivalTimes(ssrc)
+	absSendTimeID := streamHeaderExtensionID(streamInfo, sdp.ABSSendTimeURI)
 	rtpInterceptor := t.api.interceptor.BindRemoteStream(
 		&streamInfo,
 		interceptor.RTPReaderFunc(
 			func(in []byte, a interceptor.Attributes) (n int, attributes interceptor.Attributes, err error) {
 				n, err = rtpReadStream.Read(in)
+				if err != nil {
+					return n, a, err
+				}
+
+				arrival := time.Now()
+				if arrivals != nil {
+					arrival = arrivals.lastArrival()
+					if a == nil {
+						a = make(interceptor.Attributes)
+					}
+					a.Set(AttributeArrivalTime, arrival)
+				}
+				if absSendTimeID != 0 {
+					if sendTime, ok := absSendTime(in[:n], absSendTimeID, arrival); ok {
+						if a == nil {
+							a = make(interceptor.Attributes)
+						}
+						a.Set(AttributeAbsSendTime, sendTime)
+					}
+				}
 
 				return n, a, err
 			},
@@ -564,7 +786,7 @@ func (t *DTLSTransport) streamsForSSRC(
 
 	rtcpInterceptor := t.api.interceptor.BindRTCPReader(interceptor.RTCPReaderFunc(
 		func(in []byte, a interceptor.Attributes) (n int, attributes interceptor.Attributes, err error) {
-			n, err = rtcpReadStream.Read(in)
+			n, err = t.readRTCP(ssrc, in, rtcpReadStream.Read)
 
 			return n, a, err
 		}),
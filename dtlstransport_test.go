@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pion/dtls/v3"
 	"github.com/pion/transport/v3/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -85,12 +86,16 @@ func TestInvalidFingerprintCausesFailed(t *testing.T) { //nolint:cyclop
 		"DTLS Transport should be closed or failed",
 	)
 	assert.Nil(t, pcOffer.SCTP().Transport().conn)
+	_, ok := pcOffer.SCTP().Transport().SRTPProtectionProfile()
+	assert.False(t, ok, "the SRTP protection profile of a failed handshake must not be reported")
 
 	assert.Contains(
 		t, []DTLSTransportState{DTLSTransportStateClosed, DTLSTransportStateFailed}, pcAnswer.SCTP().Transport().State(),
 		"DTLS Transport should be closed or failed",
 	)
 	assert.Nil(t, pcAnswer.SCTP().Transport().conn)
+	_, ok = pcAnswer.SCTP().Transport().SRTPProtectionProfile()
+	assert.False(t, ok, "the SRTP protection profile of a failed handshake must not be reported")
 }
 
 func TestPeerConnection_DTLSRoleSettingEngine(t *testing.T) {
@@ -121,3 +126,35 @@ func TestPeerConnection_DTLSRoleSettingEngine(t *testing.T) {
 		runTest(DTLSRoleClient)
 	})
 }
+
+func TestDTLSTransport_SRTPProtectionProfile(t *testing.T) {
+	lim := test.TimeOut(time.Second * 10)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	settingEngine := SettingEngine{}
+	settingEngine.SetSRTPProtectionProfiles(dtls.SRTP_AEAD_AES_256_GCM)
+	pcOffer, pcAnswer, err := NewAPI(WithSettingEngine(settingEngine)).newPair(Configuration{})
+	assert.NoError(t, err)
+	_, err = pcOffer.CreateDataChannel("data", nil)
+	assert.NoError(t, err)
+
+	_, ok := pcOffer.SCTP().Transport().SRTPProtectionProfile()
+	assert.False(t, ok)
+	assert.Empty(t, getTransportStats(t, pcOffer.GetStats(), "iceTransport").SRTPCipher)
+
+	connected := untilConnectionState(PeerConnectionStateConnected, pcOffer, pcAnswer)
+	assert.NoError(t, signalPair(pcOffer, pcAnswer))
+	connected.Wait()
+
+	for _, pc := range []*PeerConnection{pcOffer, pcAnswer} {
+		profile, ok := pc.SCTP().Transport().SRTPProtectionProfile()
+		assert.True(t, ok)
+		assert.Equal(t, dtls.SRTP_AEAD_AES_256_GCM, profile)
+		assert.Equal(t, "SRTP_AEAD_AES_256_GCM", getTransportStats(t, pc.GetStats(), "iceTransport").SRTPCipher)
+	}
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	dtmfPayloadLength = 4
+	dtmfEndBit        = 0x80
+	dtmfVolumeMask    = 0x3F
+)
+
+// dtmfDigits are the characters of the DTMF events 0 to 15.
+const dtmfDigits = "0123456789*#ABCD"
+
+// DTMFEvent is a telephone-event, such as a DTMF digit, received on the same SSRC as the
+// audio of a TrackRemote, see RFC 4733.
+type DTMFEvent struct {
+	// Event is the code of the event: 0-9 for the digits, 10 for *, 11 for # and 12-15
+	// for A-D.
+	Event uint8
+
+	// End is set when the event ended, Duration is then the total duration of the event.
+	End bool
+
+	// Volume is the power level of the tone in -dBm0, from 0 to 63.
+	Volume uint8
+
+	// Duration is the duration of the event so far.
+	Duration time.Duration
+
+	// Timestamp is the RTP timestamp of the start of the event, shared by all its packets.
+	Timestamp uint32
+}
+
+// Digit returns the DTMF character of the event, '0'-'9', '*', '#' or 'A'-'D', or 0 for
+// the other telephone-events.
+func (e DTMFEvent) Digit() rune {
+	if int(e.Event) >= len(dtmfDigits) {
+		return 0
+	}
+
+	return rune(dtmfDigits[e.Event])
+}
+
+func isTelephoneEvent(codec RTPCodecParameters) bool {
+	return strings.EqualFold(codec.MimeType, MimeTypeTelephoneEvent)
+}
+
+// dtmfEventTracker reports each telephone-event once when it starts and once when it ends,
+// events are sent several times for reliability.
+type dtmfEventTracker struct {
+	mu sync.Mutex
+
+	started   bool
+	timestamp uint32
+	ended     bool
+}
+
+func (d *dtmfEventTracker) shouldReport(event DTMFEvent) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.started || event.Timestamp != d.timestamp {
+		d.started = true
+		d.timestamp = event.Timestamp
+		d.ended = event.End
+
+		return true
+	}
+
+	if event.End && !d.ended {
+		d.ended = true
+
+		return true
+	}
+
+	return false
+}
+
+// OnDTMF sets an event handler which is invoked when a telephone-event, such as a DTMF
+// digit, starts and when it ends. Events are only received if a codec with the
+// MimeTypeTelephoneEvent was registered with the MediaEngine and negotiated. When the
+// packet carrying the start of an event is lost, only its end is reported.
+//
+// Telephone-events share the SSRC of the audio, they never change the Codec of the track.
+// While a handler is set, their packets are not returned by Read and ReadRTP. The handler
+// is called from the goroutine reading the track, so events are reported in order, it must
+// not block.
+func (t *TrackRemote) OnDTMF(f func(DTMFEvent)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onDTMFHandler = f
+}
+
+// receiveDTMF reports whether the marshaled RTP packet b is a telephone-event, and whether
+// it was handled by the OnDTMF handler and must not be returned to the reader.
+func (t *TrackRemote) receiveDTMF(b []byte) (isEvent, handled bool) {
+	if len(b) < rtpHeaderMinLength {
+		return false, false
+	}
+
+	payloadType := PayloadType(b[1] & rtpPayloadTypeBitmask)
+	t.mu.RLock()
+	sameCodec := payloadType == t.payloadType && len(t.params.Codecs) != 0 && !isTelephoneEvent(t.codec)
+	handler := t.onDTMFHandler
+	t.mu.RUnlock()
+	if sameCodec {
+		return false, false
+	}
+
+	// Payload types of audio and video can collide, telephone-events are audio
+	var codec *RTPCodecParameters
+	for _, audioCodec := range t.receiver.api.mediaEngine.getCodecsByKind(RTPCodecTypeAudio) {
+		if audioCodec.PayloadType == payloadType && isTelephoneEvent(audioCodec) {
+			audioCodec := audioCodec
+			codec = &audioCodec
+
+			break
+		}
+	}
+	if codec == nil {
+		return false, false
+	}
+	if handler == nil {
+		return true, false
+	}
+
+	packet := &rtp.Packet{}
+	if err := packet.Unmarshal(b); err != nil || len(packet.Payload) < dtmfPayloadLength {
+		// A malformed event is dropped like the others
+		return true, true
+	}
+
+	event := DTMFEvent{
+		Event:     packet.Payload[0],
+		End:       packet.Payload[1]&dtmfEndBit != 0,
+		Volume:    packet.Payload[1] & dtmfVolumeMask,
+		Timestamp: packet.Timestamp,
+	}
+	if clockRate := codec.ClockRate; clockRate != 0 {
+		duration := binary.BigEndian.Uint16(packet.Payload[2:4])
+		event.Duration = time.Duration(duration) * time.Second / time.Duration(clockRate)
+	}
+
+	if t.dtmfEvents.shouldReport(event) {
+		handler(event)
+	}
+
+	return true, true
+}
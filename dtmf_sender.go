@@ -0,0 +1,409 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	defaultDTMFDuration = 100 * time.Millisecond
+	defaultDTMFGap      = 70 * time.Millisecond
+	minDTMFDuration     = 40 * time.Millisecond
+	maxDTMFDuration     = 6000 * time.Millisecond
+	minDTMFGap          = 30 * time.Millisecond
+
+	// dtmfPause is the duration of a "," in the tones.
+	dtmfPause = 2 * time.Second
+
+	// dtmfPacketInterval is the time between two packets of an event.
+	dtmfPacketInterval = 50 * time.Millisecond
+
+	// dtmfEndPackets is the number of times the end of an event is sent, RFC 4733 section 2.5.1.4.
+	dtmfEndPackets = 3
+
+	// dtmfVolume is the power level of the tones, in -dBm0.
+	dtmfVolume = 10
+
+	// dtmfMaxSegmentDuration is the largest duration an event packet can carry, in
+	// timestamp units. Longer events are split in segments, RFC 4733 section 2.5.1.3.
+	dtmfMaxSegmentDuration = 0xFFFF
+)
+
+// DTMFSender sends DTMF tones as telephone-events of RFC 4733 on the SSRC of an audio
+// RTPSender, see RTPSender.DTMF. Sending tones requires a codec with the
+// MimeTypeTelephoneEvent registered with the MediaEngine and negotiated.
+//
+// Telephone-events share the sequence numbers of the audio, the sequence numbers of the
+// packets written to the track are shifted by the number of event packets sent so far. The
+// audio keeps flowing while a tone is sent. The packets of the audio only go through the
+// DTMFSender when a telephone-event was negotiated before the sender started sending.
+type DTMFSender struct {
+	sender *RTPSender
+
+	mu                  sync.Mutex
+	toneBuffer          string
+	duration, gap       time.Duration
+	playing             bool
+	onToneChangeHandler func(tone string)
+
+	// Sequence numbers and timestamps of the audio stream
+	streamMu        sync.Mutex
+	writer          TrackLocalWriter
+	sequencerOffset uint16
+	lastSequence    uint16
+	hasSequence     bool
+	lastTimestamp   uint32
+	lastWrite       time.Time
+}
+
+func newDTMFSender(sender *RTPSender) *DTMFSender {
+	return &DTMFSender{sender: sender}
+}
+
+// DTMF returns the DTMFSender of the sender, or nil if the sender doesn't send audio.
+func (r *RTPSender) DTMF() *DTMFSender {
+	return r.dtmf
+}
+
+// OnToneChange sets an event handler which is invoked when a tone starts being sent, with
+// the tone, and with an empty string once all the tones were sent.
+func (d *DTMFSender) OnToneChange(f func(tone string)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onToneChangeHandler = f
+}
+
+// ToneBuffer returns the tones that remain to be sent.
+func (d *DTMFSender) ToneBuffer() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.toneBuffer
+}
+
+// CanInsertDTMF reports whether tones can be sent: the sender is sending and a
+// telephone-event payload type was negotiated when it started.
+func (d *DTMFSender) CanInsertDTMF() bool {
+	if !d.sender.hasSent() || d.sender.hasStopped() || !d.bound() {
+		return false
+	}
+	_, _, ok := d.sender.telephoneEventCodec()
+
+	return ok
+}
+
+// InsertDTMF sends tones, made of the characters 0-9, A-D, # and *, and "," for a pause
+// of 2 seconds. Each tone lasts duration, between 40ms and 6s and 100ms if 0, and tones
+// are separated by gap, at least 30ms and 70ms if 0. The tones replace the ones that were
+// not sent yet, an empty string cancels them.
+func (d *DTMFSender) InsertDTMF(tones string, duration, gap time.Duration) error {
+	if !d.CanInsertDTMF() {
+		return errDTMFSenderCannotInsert
+	}
+
+	tones = strings.ToUpper(tones)
+	for _, tone := range tones {
+		if tone != ',' && !strings.ContainsRune(dtmfDigits, tone) {
+			return errDTMFSenderInvalidTone
+		}
+	}
+
+	switch {
+	case duration == 0:
+		duration = defaultDTMFDuration
+	case duration < minDTMFDuration:
+		duration = minDTMFDuration
+	case duration > maxDTMFDuration:
+		duration = maxDTMFDuration
+	}
+	switch {
+	case gap == 0:
+		gap = defaultDTMFGap
+	case gap < minDTMFGap:
+		gap = minDTMFGap
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.toneBuffer = tones
+	d.duration = duration
+	d.gap = gap
+	if !d.playing && tones != "" {
+		d.playing = true
+		go d.play()
+	}
+
+	return nil
+}
+
+// play sends the tones of the buffer until it is empty or the sender is stopped.
+func (d *DTMFSender) play() {
+	for {
+		d.mu.Lock()
+		if d.toneBuffer == "" {
+			d.playing = false
+			handler := d.onToneChangeHandler
+			d.mu.Unlock()
+			if handler != nil {
+				handler("")
+			}
+
+			return
+		}
+		tone := d.toneBuffer[0]
+		d.toneBuffer = d.toneBuffer[1:]
+		duration, gap := d.duration, d.gap
+		handler := d.onToneChangeHandler
+		d.mu.Unlock()
+
+		if handler != nil {
+			handler(string(tone))
+		}
+
+		wait := dtmfPause
+		if tone != ',' {
+			if !d.sendEvent(uint8(strings.IndexByte(dtmfDigits, tone)), duration) { //nolint:gosec // G115
+				d.stop()
+
+				return
+			}
+			wait = gap
+		}
+
+		if !d.sleep(wait) {
+			d.stop()
+
+			return
+		}
+	}
+}
+
+// stop drops the remaining tones once the sender is stopped.
+func (d *DTMFSender) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.toneBuffer = ""
+	d.playing = false
+}
+
+// sleep waits for duration, it returns false if the sender was stopped meanwhile.
+func (d *DTMFSender) sleep(duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-d.sender.stopCalled:
+		return false
+	}
+}
+
+// sendEvent sends the packets of an event lasting duration, it returns false if the
+// sender was stopped or the telephone-event codec is no longer negotiated.
+func (d *DTMFSender) sendEvent(event uint8, duration time.Duration) bool {
+	payloadType, clockRate, ok := d.sender.telephoneEventCodec()
+	if !ok || clockRate == 0 {
+		return false
+	}
+
+	toUnits := func(elapsed time.Duration) uint32 {
+		return uint32(elapsed * time.Duration(clockRate) / time.Second) //nolint:gosec // G115
+	}
+
+	start := time.Now()
+	eventTimestamp := d.eventTimestamp(clockRate, start)
+	marker := true
+	for {
+		elapsed := time.Since(start)
+		end := elapsed >= duration
+		if end {
+			elapsed = duration
+		}
+
+		// Long events are split in segments that start where the previous one ends
+		units := toUnits(elapsed)
+		segments := units / dtmfMaxSegmentDuration
+		timestamp := eventTimestamp + segments*dtmfMaxSegmentDuration
+		units -= segments * dtmfMaxSegmentDuration
+
+		packets := 1
+		if end {
+			packets = dtmfEndPackets
+		}
+		for i := 0; i < packets; i++ {
+			if err := d.writeEvent(payloadType, timestamp, marker, event, end, uint16(units)); err != nil {
+				return false
+			}
+			marker = false
+		}
+		if end {
+			return true
+		}
+
+		wait := dtmfPacketInterval
+		if remaining := duration - elapsed; remaining < wait {
+			wait = remaining
+		}
+		if !d.sleep(wait) {
+			return false
+		}
+	}
+}
+
+func (d *DTMFSender) writeEvent(
+	payloadType PayloadType,
+	timestamp uint32,
+	marker bool,
+	event uint8,
+	end bool,
+	duration uint16,
+) error {
+	payload := make([]byte, dtmfPayloadLength)
+	payload[0] = event
+	payload[1] = dtmfVolume
+	if end {
+		payload[1] |= dtmfEndBit
+	}
+	binary.BigEndian.PutUint16(payload[2:], duration)
+
+	d.streamMu.Lock()
+	defer d.streamMu.Unlock()
+
+	if d.writer == nil {
+		return errDTMFSenderCannotInsert
+	}
+
+	d.lastSequence++
+	d.sequencerOffset++
+	d.hasSequence = true
+	_, err := d.writer.WriteRTP(&rtp.Header{
+		Version:        2,
+		Marker:         marker,
+		PayloadType:    uint8(payloadType),
+		SequenceNumber: d.lastSequence,
+		Timestamp:      timestamp,
+		SSRC:           uint32(d.sender.trackEncodings[0].ssrc),
+	}, payload)
+
+	return err
+}
+
+// eventTimestamp returns the RTP timestamp of the audio at now, extrapolated from the last
+// audio packet.
+func (d *DTMFSender) eventTimestamp(clockRate uint32, now time.Time) uint32 {
+	d.streamMu.Lock()
+	defer d.streamMu.Unlock()
+
+	if d.lastWrite.IsZero() {
+		d.lastTimestamp = d.sender.api.settingEngine.random.uint32()
+		d.lastWrite = now
+	}
+
+	return d.lastTimestamp + uint32(now.Sub(d.lastWrite)*time.Duration(clockRate)/time.Second) //nolint:gosec // G115
+}
+
+// hasTelephoneEvent reports whether codecs has a telephone-event, the writer of the track is
+// only bound to the DTMFSender then.
+func hasTelephoneEvent(codecs []RTPCodecParameters) bool {
+	for _, codec := range codecs {
+		if isTelephoneEvent(codec) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bound reports whether the writer of the track of the sender was bound.
+func (d *DTMFSender) bound() bool {
+	d.streamMu.Lock()
+	defer d.streamMu.Unlock()
+
+	return d.writer != nil
+}
+
+// bindWriter returns the writer the track of the sender writes to, it shifts the sequence
+// numbers of the audio after the events sent in between.
+func (d *DTMFSender) bindWriter(writer TrackLocalWriter) TrackLocalWriter {
+	d.streamMu.Lock()
+	defer d.streamMu.Unlock()
+	d.writer = writer
+
+	return &dtmfTrackLocalWriter{dtmf: d, writer: writer}
+}
+
+type dtmfTrackLocalWriter struct {
+	dtmf   *DTMFSender
+	writer TrackLocalWriter
+}
+
+func (w *dtmfTrackLocalWriter) WriteRTP(header *rtp.Header, payload []byte) (int, error) {
+	d := w.dtmf
+	d.streamMu.Lock()
+	defer d.streamMu.Unlock()
+
+	if d.sequencerOffset != 0 {
+		shifted := *header
+		shifted.SequenceNumber += d.sequencerOffset
+		header = &shifted
+	}
+	if !d.hasSequence || int16(header.SequenceNumber-d.lastSequence) > 0 { //nolint:gosec // G115
+		d.lastSequence = header.SequenceNumber
+		d.hasSequence = true
+	}
+	d.lastTimestamp = header.Timestamp
+	d.lastWrite = time.Now()
+
+	return w.writer.WriteRTP(header, payload)
+}
+
+func (w *dtmfTrackLocalWriter) Write(b []byte) (int, error) {
+	packet := &rtp.Packet{}
+	if err := packet.Unmarshal(b); err != nil {
+		return 0, err
+	}
+
+	return w.WriteRTP(&packet.Header, packet.Payload)
+}
+
+// telephoneEventCodec returns the negotiated telephone-event payload type, preferably with
+// the clock rate of the audio codec.
+func (r *RTPSender) telephoneEventCodec() (PayloadType, uint32, bool) {
+	r.mu.RLock()
+	var clockRate uint32
+	if len(r.trackEncodings) != 0 && r.trackEncodings[0].context != nil {
+		if codecs := r.trackEncodings[0].context.params.Codecs; len(codecs) != 0 {
+			clockRate = codecs[0].ClockRate
+		}
+	}
+	r.mu.RUnlock()
+
+	var found *RTPCodecParameters
+	for _, codec := range r.GetParameters().Codecs {
+		if !isTelephoneEvent(codec) {
+			continue
+		}
+		if found == nil || codec.ClockRate == clockRate {
+			codec := codec
+			found = &codec
+		}
+	}
+	if found == nil {
+		return 0, 0, false
+	}
+
+	return found.PayloadType, found.ClockRate, true
+}
@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDTMFSender(t *testing.T) { //nolint:cyclop
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	newAPI := func() *API {
+		mediaEngine := &MediaEngine{}
+		require.NoError(t, mediaEngine.RegisterDefaultCodecs())
+		require.NoError(t, mediaEngine.RegisterCodec(RTPCodecParameters{
+			RTPCodecCapability: RTPCodecCapability{MimeType: MimeTypeTelephoneEvent, ClockRate: 48000, SDPFmtpLine: "0-15"},
+			PayloadType:        101,
+		}, RTPCodecTypeAudio))
+
+		return NewAPI(WithMediaEngine(mediaEngine))
+	}
+	pcOffer, err := newAPI().NewPeerConnection(Configuration{})
+	require.NoError(t, err)
+	pcAnswer, err := newAPI().NewPeerConnection(Configuration{})
+	require.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeOpus}, "audio", "pion")
+	require.NoError(t, err)
+	sender, err := pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	videoTrack, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	require.NoError(t, err)
+	videoSender, err := pcOffer.AddTrack(videoTrack)
+	require.NoError(t, err)
+	assert.Nil(t, videoSender.DTMF())
+
+	dtmf := sender.DTMF()
+	require.NotNil(t, dtmf)
+	assert.False(t, dtmf.CanInsertDTMF())
+	assert.ErrorIs(t, dtmf.InsertDTMF("1", 0, 0), errDTMFSenderCannotInsert)
+
+	events := make(chan DTMFEvent, 10)
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	var readerDone sync.WaitGroup
+	pcAnswer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		if trackRemote.Kind() != RTPCodecTypeAudio {
+			return
+		}
+		trackRemote.OnDTMF(func(event DTMFEvent) {
+			events <- event
+		})
+		onTrackFiredFunc()
+
+		readerDone.Add(1)
+		go func() {
+			defer readerDone.Done()
+			for {
+				pkt, _, readErr := trackRemote.ReadRTP()
+				if readErr != nil {
+					return
+				}
+				assert.NotEqual(t, uint8(101), pkt.PayloadType)
+			}
+		}()
+	})
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	// The audio keeps flowing while the tones are sent
+	done := make(chan struct{})
+	var writerDone sync.WaitGroup
+	writerDone.Add(1)
+	go func() {
+		defer writerDone.Done()
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				assert.NoError(t, track.WriteSample(media.Sample{Data: []byte{0x00}, Duration: 20 * time.Millisecond}))
+			}
+		}
+	}()
+	<-onTrackFired.Done()
+
+	assert.True(t, dtmf.CanInsertDTMF())
+	assert.ErrorIs(t, dtmf.InsertDTMF("1x", 0, 0), errDTMFSenderInvalidTone)
+	_, isDTMFWriter := sender.trackEncodings[0].context.writeStream.(*dtmfTrackLocalWriter)
+	assert.True(t, isDTMFWriter)
+
+	tones := make(chan string, 10)
+	dtmf.OnToneChange(func(tone string) {
+		tones <- tone
+	})
+	require.NoError(t, dtmf.InsertDTMF("1#", 100*time.Millisecond, 50*time.Millisecond))
+
+	for _, tone := range []string{"1", "#", ""} {
+		assert.Equal(t, tone, <-tones)
+	}
+	assert.Empty(t, dtmf.ToneBuffer())
+
+	for _, event := range []uint8{1, 11} {
+		start := <-events
+		assert.Equal(t, event, start.Event)
+		assert.False(t, start.End)
+
+		end := <-events
+		assert.Equal(t, event, end.Event)
+		assert.True(t, end.End)
+		assert.Equal(t, start.Timestamp, end.Timestamp)
+		assert.Equal(t, 100*time.Millisecond, end.Duration)
+	}
+
+	close(done)
+	writerDone.Wait()
+	closePairNow(t, pcOffer, pcAnswer)
+	readerDone.Wait()
+}
+
+func TestDTMFSender_NotNegotiated(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeOpus}, "audio", "pion")
+	require.NoError(t, err)
+	sender, err := pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	connected := untilConnectionState(PeerConnectionStateConnected, pcOffer, pcAnswer)
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	connected.Wait()
+
+	// Without a telephone-event the audio isn't written through the DTMFSender
+	require.True(t, sender.hasSent())
+	_, isDTMFWriter := sender.trackEncodings[0].context.writeStream.(*dtmfTrackLocalWriter)
+	assert.False(t, isDTMFWriter)
+	assert.False(t, sender.DTMF().CanInsertDTMF())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
+
+func TestDTMFTrackLocalWriter(t *testing.T) {
+	dtmf := newDTMFSender(&RTPSender{trackEncodings: []*trackEncoding{{ssrc: 5}}})
+	recorder := &sequenceRecorder{}
+	writer := dtmf.bindWriter(recorder)
+
+	for _, sequenceNumber := range []uint16{65534, 65535} {
+		_, err := writer.WriteRTP(&rtp.Header{SequenceNumber: sequenceNumber}, nil)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, dtmf.writeEvent(101, 0, true, 1, false, 0))
+	assert.NoError(t, dtmf.writeEvent(101, 0, false, 1, true, 0))
+
+	// The audio written after the events is shifted, a late packet as well
+	header := &rtp.Header{SequenceNumber: 0}
+	_, err := writer.WriteRTP(header, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0), header.SequenceNumber)
+	_, err = writer.WriteRTP(&rtp.Header{SequenceNumber: 65533}, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []uint16{65534, 65535, 0, 1, 2, 65535}, recorder.sequenceNumbers)
+	assert.Equal(t, []uint32{0, 0, 5, 5, 0, 0}, recorder.ssrcs)
+}
+
+type sequenceRecorder struct {
+	sequenceNumbers []uint16
+	ssrcs           []uint32
+}
+
+func (s *sequenceRecorder) WriteRTP(header *rtp.Header, _ []byte) (int, error) {
+	s.sequenceNumbers = append(s.sequenceNumbers, header.SequenceNumber)
+	s.ssrcs = append(s.ssrcs, header.SSRC)
+
+	return 0, nil
+}
+
+func (s *sequenceRecorder) Write([]byte) (int, error) {
+	return 0, nil
+}
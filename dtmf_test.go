@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDTMFEvent_Digit(t *testing.T) {
+	assert.Equal(t, '0', DTMFEvent{Event: 0}.Digit())
+	assert.Equal(t, '9', DTMFEvent{Event: 9}.Digit())
+	assert.Equal(t, '*', DTMFEvent{Event: 10}.Digit())
+	assert.Equal(t, '#', DTMFEvent{Event: 11}.Digit())
+	assert.Equal(t, 'D', DTMFEvent{Event: 15}.Digit())
+	assert.Equal(t, rune(0), DTMFEvent{Event: 16}.Digit())
+}
+
+func TestDTMFEventTracker(t *testing.T) {
+	tracker := dtmfEventTracker{}
+	assert.True(t, tracker.shouldReport(DTMFEvent{Timestamp: 1}))
+	assert.False(t, tracker.shouldReport(DTMFEvent{Timestamp: 1}))
+	assert.True(t, tracker.shouldReport(DTMFEvent{Timestamp: 1, End: true}))
+	assert.False(t, tracker.shouldReport(DTMFEvent{Timestamp: 1, End: true}))
+
+	// Only the end of the next event was received
+	assert.True(t, tracker.shouldReport(DTMFEvent{Timestamp: 2, End: true}))
+	assert.False(t, tracker.shouldReport(DTMFEvent{Timestamp: 2, End: true}))
+}
+
+func TestTrackRemote_OnDTMF(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	const telephoneEventPayloadType = 101
+
+	mediaEngine := &MediaEngine{}
+	require.NoError(t, mediaEngine.RegisterDefaultCodecs())
+	require.NoError(t, mediaEngine.RegisterCodec(RTPCodecParameters{
+		RTPCodecCapability: RTPCodecCapability{MimeType: MimeTypeTelephoneEvent, ClockRate: 48000, SDPFmtpLine: "0-15"},
+		PayloadType:        telephoneEventPayloadType,
+	}, RTPCodecTypeAudio))
+	pcOffer, pcAnswer, err := NewAPI(WithMediaEngine(mediaEngine)).newPair(Configuration{})
+	require.NoError(t, err)
+
+	track, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeOpus}, "audio", "pion")
+	require.NoError(t, err)
+	sender, err := pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	remoteTracks := make(chan *TrackRemote, 1)
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	pcAnswer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		remoteTracks <- trackRemote
+		onTrackFiredFunc()
+	})
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	sequenceNumber := uint16(0)
+	for ; onTrackFired.Err() == nil; sequenceNumber++ {
+		time.Sleep(20 * time.Millisecond)
+		assert.NoError(t, track.WriteRTP(&rtp.Packet{
+			Header:  rtp.Header{Version: 2, SequenceNumber: sequenceNumber, Timestamp: uint32(sequenceNumber) * 960},
+			Payload: []byte{0x00},
+		}))
+	}
+	remoteTrack := <-remoteTracks
+
+	events := make(chan DTMFEvent, 2)
+	remoteTrack.OnDTMF(func(event DTMFEvent) {
+		events <- event
+	})
+
+	headers := make(chan rtp.Header, 100)
+	go func() {
+		defer close(headers)
+		for {
+			pkt, _, readErr := remoteTrack.ReadRTP()
+			if readErr != nil {
+				return
+			}
+			headers <- pkt.Header
+		}
+	}()
+
+	// The digit 5 pressed for 100ms, with its end sent three times
+	srtpSession, err := pcOffer.dtlsTransport.getSRTPSession()
+	require.NoError(t, err)
+	writeStream, err := srtpSession.OpenWriteStream()
+	require.NoError(t, err)
+	eventTimestamp := uint32(sequenceNumber) * 960
+	for _, payload := range [][]byte{
+		{5, 10, 0x03, 0xC0},
+		{5, 10, 0x09, 0x60},
+		{5, 10 | 0x80, 0x12, 0xC0},
+		{5, 10 | 0x80, 0x12, 0xC0},
+		{5, 10 | 0x80, 0x12, 0xC0},
+	} {
+		_, err = writeStream.WriteRTP(&rtp.Header{
+			Version:        2,
+			PayloadType:    telephoneEventPayloadType,
+			SequenceNumber: sequenceNumber,
+			Timestamp:      eventTimestamp,
+			SSRC:           uint32(sender.GetParameters().Encodings[0].SSRC),
+		}, payload)
+		require.NoError(t, err)
+		sequenceNumber++
+	}
+	assert.NoError(t, track.WriteRTP(&rtp.Packet{
+		Header:  rtp.Header{Version: 2, SequenceNumber: sequenceNumber, Timestamp: eventTimestamp + 4800},
+		Payload: []byte{0x00},
+	}))
+
+	assert.Equal(t, DTMFEvent{Event: 5, Volume: 10, Duration: 20 * time.Millisecond, Timestamp: eventTimestamp}, <-events)
+	assert.Equal(t, DTMFEvent{
+		Event: 5, End: true, Volume: 10, Duration: 100 * time.Millisecond, Timestamp: eventTimestamp,
+	}, <-events)
+
+	// The events are not read, the audio packet written after them is
+	for header := range headers {
+		assert.NotEqual(t, uint8(telephoneEventPayloadType), header.PayloadType)
+		if header.SequenceNumber == sequenceNumber {
+			break
+		}
+	}
+	assert.Equal(t, MimeTypeOpus, remoteTrack.Codec().MimeType)
+
+	closePairNow(t, pcOffer, pcAnswer)
+	for header := range headers {
+		assert.NotEqual(t, uint8(telephoneEventPayloadType), header.PayloadType)
+	}
+}
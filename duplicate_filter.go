@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"sync"
+)
+
+const maxDuplicatePacketWindow = 1 << 15
+
+// duplicatePacketFilter finds the packets of a track whose sequence number was already
+// read among the last window sequence numbers. It runs after RTX packets are unwrapped,
+// so a retransmission of a packet that was already received is found as well.
+type duplicatePacketFilter struct {
+	mu sync.Mutex
+
+	// received holds a bit per sequence number of the window, the window size is a
+	// power of two so that it stays aligned when sequence numbers wrap.
+	received []uint64
+	mask     uint16
+
+	started bool
+	ssrc    SSRC
+	highest uint16
+
+	duplicates uint32
+}
+
+// newDuplicatePacketFilter returns a filter for a window of size sequence numbers, rounded
+// up to a power of two, or nil if size is 0.
+func newDuplicatePacketFilter(size uint16) *duplicatePacketFilter {
+	if size == 0 {
+		return nil
+	}
+
+	window := 64
+	for window < int(size) && window < maxDuplicatePacketWindow {
+		window <<= 1
+	}
+
+	return &duplicatePacketFilter{
+		received: make([]uint64, window/64),
+		mask:     uint16(window - 1), //nolint:gosec // G115
+	}
+}
+
+// isDuplicate inspects the header of the marshaled RTP packet b and reports whether it
+// was already received. A nil filter reports no duplicates.
+func (f *duplicatePacketFilter) isDuplicate(b []byte) bool {
+	if f == nil || len(b) < rtpHeaderMinLength {
+		return false
+	}
+
+	sequenceNumber := binary.BigEndian.Uint16(b[2:4])
+	ssrc := SSRC(binary.BigEndian.Uint32(b[8:12]))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// A new SSRC restarts its sequence numbers, forget the previous stream.
+	if !f.started || ssrc != f.ssrc {
+		f.started = true
+		f.ssrc = ssrc
+		f.highest = sequenceNumber
+		for i := range f.received {
+			f.received[i] = 0
+		}
+		f.set(sequenceNumber)
+
+		return false
+	}
+
+	delta := int16(sequenceNumber - f.highest) //nolint:gosec // G115
+	switch {
+	case delta > 0:
+		if int(delta) > int(f.mask) {
+			for i := range f.received {
+				f.received[i] = 0
+			}
+		} else {
+			for seq := f.highest + 1; seq != sequenceNumber; seq++ {
+				f.clear(seq)
+			}
+		}
+		f.highest = sequenceNumber
+		f.set(sequenceNumber)
+
+		return false
+	case -int(delta) > int(f.mask):
+		// Older than the window, it can't be told apart from a late packet.
+		return false
+	case f.isSet(sequenceNumber):
+		f.duplicates++
+
+		return true
+	default:
+		f.set(sequenceNumber)
+
+		return false
+	}
+}
+
+func (f *duplicatePacketFilter) set(sequenceNumber uint16) {
+	i := sequenceNumber & f.mask
+	f.received[i/64] |= 1 << (i % 64)
+}
+
+func (f *duplicatePacketFilter) clear(sequenceNumber uint16) {
+	i := sequenceNumber & f.mask
+	f.received[i/64] &^= 1 << (i % 64)
+}
+
+func (f *duplicatePacketFilter) isSet(sequenceNumber uint16) bool {
+	i := sequenceNumber & f.mask
+
+	return f.received[i/64]&(1<<(i%64)) != 0
+}
+
+// getDuplicates returns the number of duplicates dropped so far.
+func (f *duplicatePacketFilter) getDuplicates() uint32 {
+	if f == nil {
+		return 0
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.duplicates
+}
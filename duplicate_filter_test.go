@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicatePacketFilter(t *testing.T) {
+	marshal := func(ssrc uint32, sequenceNumber uint16) []byte {
+		b, err := (&rtp.Packet{
+			Header:  rtp.Header{Version: 2, SSRC: ssrc, SequenceNumber: sequenceNumber},
+			Payload: []byte{0x00},
+		}).Marshal()
+		require.NoError(t, err)
+
+		return b
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		filter := newDuplicatePacketFilter(0)
+		assert.Nil(t, filter)
+		assert.False(t, filter.isDuplicate(marshal(1, 1)))
+		assert.False(t, filter.isDuplicate(marshal(1, 1)))
+		assert.Zero(t, filter.getDuplicates())
+	})
+
+	t.Run("Window", func(t *testing.T) {
+		filter := newDuplicatePacketFilter(100)
+		assert.Equal(t, uint16(127), filter.mask)
+
+		for _, step := range []struct {
+			ssrc           uint32
+			sequenceNumber uint16
+			duplicate      bool
+		}{
+			{1, 65534, false},
+			{1, 65534, true},
+			{1, 1, false},
+			// Reordered across the wrap, then retransmitted
+			{1, 65535, false},
+			{1, 65535, true},
+			{1, 0, false},
+			{1, 1, true},
+			// Far ahead, the window is reset
+			{1, 1000, false},
+			{1, 1, false},
+			{1, 999, false},
+			{1, 1000, true},
+			// Older than the window
+			{1, 800, false},
+			{1, 800, false},
+			// A new SSRC restarts the sequence numbers
+			{2, 1000, false},
+			{2, 1000, true},
+		} {
+			assert.Equal(t, step.duplicate, filter.isDuplicate(marshal(step.ssrc, step.sequenceNumber)), step)
+		}
+		assert.Equal(t, uint32(5), filter.getDuplicates())
+		assert.False(t, filter.isDuplicate([]byte{0x80}))
+	})
+}
+
+func TestTrackRemote_DuplicatePackets(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	settingEngine := SettingEngine{}
+	settingEngine.SetDuplicatePacketWindow(64)
+	// SRTP replay protection would drop the duplicates first
+	settingEngine.DisableSRTPReplayProtection(true)
+	pcOffer, pcAnswer, err := NewAPI(WithSettingEngine(settingEngine)).newPair(Configuration{})
+	require.NoError(t, err)
+
+	track, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	require.NoError(t, err)
+	_, err = pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	remoteTracks := make(chan *TrackRemote, 1)
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	pcAnswer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		remoteTracks <- trackRemote
+		onTrackFiredFunc()
+	})
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	newPacket := func(sequenceNumber uint16) *rtp.Packet {
+		return &rtp.Packet{Header: rtp.Header{Version: 2, SequenceNumber: sequenceNumber}, Payload: []byte{0x00}}
+	}
+
+	sequenceNumber := uint16(0)
+	for ; onTrackFired.Err() == nil; sequenceNumber++ {
+		time.Sleep(20 * time.Millisecond)
+		assert.NoError(t, track.WriteRTP(newPacket(sequenceNumber)))
+	}
+	remoteTrack := <-remoteTracks
+
+	for _, seq := range []uint16{sequenceNumber, sequenceNumber, sequenceNumber + 1, sequenceNumber, sequenceNumber + 2} {
+		assert.NoError(t, track.WriteRTP(newPacket(seq)))
+	}
+
+	for {
+		pkt, _, readErr := remoteTrack.ReadRTP()
+		require.NoError(t, readErr)
+		if pkt.SequenceNumber == sequenceNumber {
+			break
+		}
+	}
+	for _, seq := range []uint16{sequenceNumber + 1, sequenceNumber + 2} {
+		pkt, _, readErr := remoteTrack.ReadRTP()
+		require.NoError(t, readErr)
+		assert.Equal(t, seq, pkt.SequenceNumber)
+	}
+
+	stats, ok := pcAnswer.GetStats().GetInboundRTPStreamStats(remoteTrack)
+	require.True(t, ok)
+	assert.Equal(t, uint32(2), stats.PacketsDuplicated)
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+// bufferEarlyICECandidate buffers a candidate added before the remote description, it returns
+// false if the buffering is disabled or if the remote description was set in the meantime.
+func (pc *PeerConnection) bufferEarlyICECandidate(candidate ICECandidateInit) (bool, error) {
+	size := pc.api.settingEngine.earlyICECandidateBufferSize
+	if size <= 0 {
+		return false, nil
+	}
+
+	pc.earlyCandidatesLock.Lock()
+	defer pc.earlyCandidatesLock.Unlock()
+
+	// The remote description is set before the buffered candidates are added, checking it
+	// under the lock ensures a candidate can't be buffered after they were.
+	if pc.RemoteDescription() != nil {
+		return false, nil
+	}
+	if len(pc.earlyCandidates) >= size {
+		return false, ErrEarlyICECandidateBufferFull
+	}
+	pc.earlyCandidates = append(pc.earlyCandidates, candidate)
+
+	return true, nil
+}
+
+// addEarlyICECandidates adds the candidates buffered before the remote description was set.
+func (pc *PeerConnection) addEarlyICECandidates() {
+	pc.earlyCandidatesLock.Lock()
+	candidates := pc.earlyCandidates
+	pc.earlyCandidates = nil
+	pc.earlyCandidatesLock.Unlock()
+
+	for _, candidate := range candidates {
+		if err := pc.AddICECandidate(candidate); err != nil {
+			pc.log.Warnf("Failed to add ICE candidate buffered before the remote description: %s", err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4/pkg/rtcerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerConnection_EarlyICECandidates(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	t.Run("Disabled", func(t *testing.T) {
+		pc, err := NewPeerConnection(Configuration{})
+		require.NoError(t, err)
+
+		err = pc.AddICECandidate(ICECandidateInit{Candidate: "candidate:1 1 udp 2130706431 192.0.2.1 5000 typ host"})
+		assert.Equal(t, &rtcerr.InvalidStateError{Err: ErrNoRemoteDescription}, err)
+		assert.NoError(t, pc.Close())
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		settingEngine := SettingEngine{}
+		settingEngine.SetEarlyICECandidateBufferSize(1)
+		pc, err := NewAPI(WithSettingEngine(settingEngine)).NewPeerConnection(Configuration{})
+		require.NoError(t, err)
+
+		candidate := ICECandidateInit{Candidate: "candidate:1 1 udp 2130706431 192.0.2.1 5000 typ host"}
+		assert.NoError(t, pc.AddICECandidate(candidate))
+		assert.ErrorIs(t, pc.AddICECandidate(candidate), ErrEarlyICECandidateBufferFull)
+		assert.NoError(t, pc.Close())
+	})
+
+	// The candidates of the offerer are trickled to the answerer before its offer
+	t.Run("Trickled before the offer", func(t *testing.T) {
+		settingEngine := SettingEngine{}
+		settingEngine.SetEarlyICECandidateBufferSize(16)
+		api := NewAPI(WithSettingEngine(settingEngine))
+
+		pcOffer, err := api.NewPeerConnection(Configuration{})
+		require.NoError(t, err)
+		pcAnswer, err := api.NewPeerConnection(Configuration{})
+		require.NoError(t, err)
+
+		_, err = pcOffer.CreateDataChannel("data", nil)
+		require.NoError(t, err)
+
+		offerCandidates := []ICECandidateInit{}
+		offerGathered := make(chan struct{})
+		pcOffer.OnICECandidate(func(candidate *ICECandidate) {
+			if candidate == nil {
+				close(offerGathered)
+
+				return
+			}
+			offerCandidates = append(offerCandidates, candidate.ToJSON())
+		})
+		pcAnswer.OnICECandidate(func(candidate *ICECandidate) {
+			if candidate != nil {
+				assert.NoError(t, pcOffer.AddICECandidate(candidate.ToJSON()))
+			}
+		})
+
+		offer, err := pcOffer.CreateOffer(nil)
+		require.NoError(t, err)
+		require.NoError(t, pcOffer.SetLocalDescription(offer))
+		<-offerGathered
+		require.NotEmpty(t, offerCandidates)
+
+		for _, candidate := range offerCandidates {
+			require.NoError(t, pcAnswer.AddICECandidate(candidate))
+		}
+		assert.Empty(t, pcAnswer.RemoteICECandidates())
+
+		// The offer is sent without its candidates
+		connected := untilConnectionState(PeerConnectionStateConnected, pcOffer, pcAnswer)
+		require.NoError(t, pcAnswer.SetRemoteDescription(offer))
+		assert.Len(t, pcAnswer.RemoteICECandidates(), len(offerCandidates))
+
+		answer, err := pcAnswer.CreateAnswer(nil)
+		require.NoError(t, err)
+		require.NoError(t, pcAnswer.SetLocalDescription(answer))
+		require.NoError(t, pcOffer.SetRemoteDescription(answer))
+		connected.Wait()
+
+		closePairNow(t, pcOffer, pcAnswer)
+	})
+}
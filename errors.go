@@ -83,6 +83,11 @@ var (
 	// the remote description is not set.
 	ErrNoRemoteDescription = errors.New("remote description is not set")
 
+	// ErrEarlyICECandidateBufferFull indicates that AddICECandidate was called before the
+	// remote description was set, and the candidates already buffered fill the buffer of
+	// SettingEngine.SetEarlyICECandidateBufferSize.
+	ErrEarlyICECandidateBufferFull = errors.New("too many ice candidates added before the remote description")
+
 	// ErrIncorrectSDPSemantics indicates that the PeerConnection was configured to
 	// generate SDP Answers with different SDP Semantics than the received Offer.
 	ErrIncorrectSDPSemantics = errors.New("remote SessionDescription semantics does not match configuration")
@@ -175,8 +180,22 @@ var (
 	// and the requested SSRC was ignored.
 	ErrSimulcastProbeOverflow = errors.New("simulcast probe limit has been reached, new SSRC has been discarded")
 
+	// ErrPacketTooLarge indicates that a SRTP or SRTCP packet is larger than the maximum
+	// packet size set in the SettingEngine and wasn't sent.
+	ErrPacketTooLarge = errors.New("packet is larger than the maximum packet size")
+
+	// ErrNoOpOffer indicates that CreateOffer was called with OfferOptions.SkipIfNoOp and
+	// the offer would not change the negotiated session.
+	ErrNoOpOffer = errors.New("offer would not change the negotiated session")
+
+	// ErrKeyFrameRequestSuppressed indicates that WriteRTCP dropped a PLI or FIR because a
+	// keyframe was requested for the same media SSRC too recently, see
+	// SettingEngine.SetKeyFrameRequestInterval. The other packets were sent.
+	ErrKeyFrameRequestSuppressed = errors.New("keyframe request suppressed, a keyframe was requested too recently")
+
 	errDetachNotEnabled                 = errors.New("enable detaching by calling webrtc.DetachDataChannels()")
 	errDetachBeforeOpened               = errors.New("datachannel not opened yet, try calling Detach from OnOpen")
+	errDataChannelAsConnDetached        = errors.New("AsConn can't be used with detached datachannels")
 	errDtlsTransportNotStarted          = errors.New("the DTLS transport has not started yet")
 	errDtlsKeyExtractionFailed          = errors.New("failed extracting keys from DTLS for SRTP")
 	errFailedToStartSRTP                = errors.New("failed to start SRTP")
@@ -211,6 +230,8 @@ var (
 		"remoteDescription contained media section without mid value",
 	)
 	errPeerConnRemoteDescriptionNil                  = errors.New("remoteDescription has not been set yet")
+	errPeerConnAnswerReceiveOnlyNotOffer             = errors.New("AnswerReceiveOnly must be given an offer")
+	errPeerConnAnswerReceiveOnlyDirection            = errors.New("transceiver can't receive the media section")
 	errMediaSectionHasExplictSSRCAttribute           = errors.New("media section has an explicit SSRC")
 	errPeerConnRemoteSSRCAddTransceiver              = errors.New("could not add transceiver for remote SSRC")
 	errPeerConnSimulcastMidRTPExtensionRequired      = errors.New("mid RTP Extensions required for Simulcast")
@@ -236,6 +257,7 @@ var (
 	errRTPReceiverReceiveAlreadyCalled        = errors.New("Receive has already been called")
 	errRTPReceiverWithSSRCTrackStreamNotFound = errors.New("unable to find stream for Track with SSRC")
 	errRTPReceiverForRIDTrackStreamNotFound   = errors.New("no trackStreams found for RID")
+	errRTPReceiverInvalidBufferPolicy         = errors.New("invalid RTPReceiver buffer policy")
 
 	errRTPSenderTrackNil             = errors.New("Track must not be nil")
 	errRTPSenderDTLSTransportNil     = errors.New("DTLSTransport must not be nil")
@@ -247,20 +269,36 @@ var (
 	errRTPSenderBaseEncodingMismatch = errors.New("Sender cannot add encoding as provided track does not match base track")
 	errRTPSenderRIDCollision         = errors.New("Sender cannot encoding due to RID collision")
 	errRTPSenderNoTrackForRID        = errors.New("Sender does not have track for RID")
+	errRTPSenderInvalidMaxBitrate    = errors.New("Sender max bitrate must not be negative")
+	errRTPSenderInvalidExtProfile    = errors.New("Sender header extension profile is unknown")
+
+	errRTPSenderRTCPInjectionDisabled = errors.New("Sender RTCP injection requires EnableRTCPInjectionForTesting")
+	errRTPSenderRTCPInjectionNotSent  = errors.New("Sender cannot inject RTCP before Send is called")
+	errRTPSenderInvalidLayerBitrate   = errors.New("Sender simulcast layer bitrate must not be negative")
 
 	errRTPTransceiverCannotChangeMid        = errors.New("errRTPSenderTrackNil")
 	errRTPTransceiverSetSendingInvalidState = errors.New("invalid state change in RTPTransceiver.setSending")
 	errRTPTransceiverCodecUnsupported       = errors.New("unsupported codec type by this transceiver")
+	errRTPTransceiverCodecNotFound          = errors.New("no codec with this payload type on this transceiver")
+	errRTPTransceiverFmtpIncompatible       = errors.New("fmtp would change the codec configuration")
 
 	errSCTPTransportDTLS = errors.New("DTLS not established")
 
 	errSDPZeroTransceivers                 = errors.New("addTransceiverSDP() called with 0 transceivers")
+	errSDPInvalidRTCPAttribute             = errors.New("invalid a=rtcp attribute")
 	errSDPMediaSectionMediaDataChanInvalid = errors.New("invalid Media Section. Media + DataChannel both enabled")
 	errSDPMediaSectionMultipleTrackInvalid = errors.New(
 		"invalid Media Section. Can not have multiple tracks in one MediaSection in UnifiedPlan",
 	)
+	errSDPNonBundledTransports = errors.New(
+		"media sections that are not bundled must share the ICE credentials of a single transport",
+	)
 
 	errSettingEngineSetAnsweringDTLSRole = errors.New("SetAnsweringDTLSRole must DTLSRoleClient or DTLSRoleServer")
+	errSettingEngineSetICERole           = errors.New(
+		"SetICERole must be ICERoleUnknown, ICERoleControlling or ICERoleControlled",
+	)
+	errSettingEngineSRTPAuthTagLength = errors.New("SetSRTPAuthenticationTagLength must be 80 or 32 bits")
 
 	errSignalingStateCannotRollback            = errors.New("can't rollback from stable state")
 	errSignalingStateProposedTransitionInvalid = errors.New("invalid proposed signaling state transition")
@@ -281,5 +319,17 @@ var (
 
 	errRTPTooShort = errors.New("not long enough to be a RTP Packet")
 
+	errTrackLocalNotInWriteGroup = errors.New("track is not in the write group, or the group write is over")
+
+	errDTMFSenderCannotInsert = errors.New("can't send DTMF, the sender isn't sending or telephone-event isn't negotiated")
+	errDTMFSenderInvalidTone  = errors.New("invalid DTMF tone, tones are 0-9, A-D, #, * or ,")
+
+	errComfortNoiseNotNegotiated = errors.New("comfort noise wasn't negotiated with the clock rate of the track")
+	errComfortNoiseInvalid       = errors.New("invalid comfort noise payload")
+
+	errSRTPAuthTagLengthMismatch = errors.New("the SRTP protection profile chosen by the remote doesn't use the configured authentication tag length") // nolint: lll
+
 	errExcessiveRetries = errors.New("excessive retries in CreateOffer")
+
+	errTransportDescriptionNoCandidatePair = errors.New("no selected candidate pair to describe")
 )
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+
+	"github.com/pion/rtp"
+	"github.com/pion/rtp/codecs"
+)
+
+// OnFirstKeyFrame sets an event handler which is invoked once, when the first packet of the
+// first keyframe of the track is read, the moment from which the video can be decoded and
+// rendered. It is invoked right away if the keyframe was already read.
+//
+// Keyframes are detected from the payload descriptors of VP8, VP9 and AV1, and from the
+// NAL unit types of H264 and H265: IDR or IRAP pictures and the parameter sets in front of
+// them. Frames that only become decodable through recovery points or intra refresh are not
+// detected, and the handler is never invoked for other codecs and for audio tracks. Packets
+// are checked when they are read, and only while a handler is set: a handler set after a
+// keyframe was read without one is invoked at the next keyframe.
+func (t *TrackRemote) OnFirstKeyFrame(f func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onFirstKeyFrameHandler = f
+
+	if t.firstKeyFrameRead && f != nil {
+		go f()
+	}
+}
+
+// checkFirstKeyFrame invokes the OnFirstKeyFrame handler if the marshaled RTP packet b
+// starts the first keyframe of the track.
+func (t *TrackRemote) checkFirstKeyFrame(b []byte) {
+	t.mu.RLock()
+	done := t.firstKeyFrameRead || t.onFirstKeyFrameHandler == nil || t.kind != RTPCodecTypeVideo
+	mimeType := t.codec.MimeType
+	t.mu.RUnlock()
+	if done {
+		return
+	}
+
+	packet := &rtp.Packet{}
+	if err := packet.Unmarshal(b); err != nil || !isFirstKeyFrameStart(packet, mimeType) {
+		return
+	}
+
+	t.mu.Lock()
+	if t.firstKeyFrameRead {
+		t.mu.Unlock()
+
+		return
+	}
+	t.firstKeyFrameRead = true
+	handler := t.onFirstKeyFrameHandler
+	t.mu.Unlock()
+
+	if handler != nil {
+		go handler()
+	}
+}
+
+// isFirstKeyFrameStart reports whether packet is the first packet of a keyframe, like
+// isKeyFrameStart with the keyframes of VP9, AV1 and H265 as well.
+func isFirstKeyFrameStart(packet *rtp.Packet, mimeType string) bool {
+	payload := packet.Payload
+	switch {
+	case strings.EqualFold(mimeType, MimeTypeVP9):
+		vp9 := codecs.VP9Packet{}
+		if _, err := vp9.Unmarshal(payload); err != nil {
+			return false
+		}
+
+		// Start of the frame of the base spatial layer, not predicted from a previous picture
+		return vp9.B && !vp9.P && vp9.SID == 0
+	case strings.EqualFold(mimeType, MimeTypeAV1):
+		// The N bit of the aggregation header starts a coded video sequence, Z continues an OBU
+		return len(payload) != 0 && payload[0]&0x08 != 0 && payload[0]&0x80 == 0
+	case strings.EqualFold(mimeType, MimeTypeH265):
+		return isH265KeyFrameStart(payload)
+	default:
+		return isKeyFrameStart(packet, mimeType)
+	}
+}
+
+// isH265KeyFrameStart reports whether the payload starts a frame with an IRAP picture,
+// or the VPS or SPS sent in front of it.
+func isH265KeyFrameStart(payload []byte) bool {
+	const (
+		naluTypeIRAPFirst = 16
+		naluTypeIRAPLast  = 23
+		naluTypeVPS       = 32
+		naluTypeSPS       = 33
+		naluTypeAP        = 48
+		naluTypeFU        = 49
+	)
+
+	isKeyFrameNALU := func(naluType byte) bool {
+		return (naluType >= naluTypeIRAPFirst && naluType <= naluTypeIRAPLast) ||
+			naluType == naluTypeVPS || naluType == naluTypeSPS
+	}
+
+	if len(payload) < 2 {
+		return false
+	}
+
+	switch naluType := (payload[0] >> 1) & 0x3f; naluType {
+	case naluTypeAP:
+		for offset := 2; offset+2 < len(payload); {
+			size := int(payload[offset])<<8 | int(payload[offset+1])
+			if isKeyFrameNALU((payload[offset+2] >> 1) & 0x3f) {
+				return true
+			}
+			offset += 2 + size
+		}
+	case naluTypeFU:
+		return len(payload) > 2 && payload[2]&0x80 != 0 && isKeyFrameNALU(payload[2]&0x3f)
+	default:
+		return isKeyFrameNALU(naluType)
+	}
+
+	return false
+}
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsFirstKeyFrameStart(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		mimeType string
+		payload  []byte
+		keyFrame bool
+	}{
+		{"VP8 keyframe", MimeTypeVP8, []byte{0x10, 0x00, 0x01}, true},
+		{"H264 IDR", MimeTypeH264, []byte{0x65, 0x01}, true},
+		{"VP9 keyframe", MimeTypeVP9, []byte{0x08, 0x00}, true},
+		{"VP9 interframe", MimeTypeVP9, []byte{0x48, 0x00}, false},
+		{"VP9 continuation", MimeTypeVP9, []byte{0x00, 0x00}, false},
+		{"AV1 new coded video sequence", MimeTypeAV1, []byte{0x18, 0x00}, true},
+		{"AV1 interframe", MimeTypeAV1, []byte{0x10, 0x00}, false},
+		{"AV1 continued OBU", MimeTypeAV1, []byte{0x98, 0x00}, false},
+		{"H265 IDR", MimeTypeH265, []byte{0x26, 0x01, 0x01}, true},
+		{"H265 VPS", MimeTypeH265, []byte{0x40, 0x01, 0x01}, true},
+		{"H265 trailing picture", MimeTypeH265, []byte{0x02, 0x01, 0x01}, false},
+		{"H265 AP with VPS", MimeTypeH265, []byte{0x60, 0x01, 0x00, 0x02, 0x02, 0x01, 0x00, 0x02, 0x40, 0x01}, true},
+		{"H265 FU IDR start", MimeTypeH265, []byte{0x62, 0x01, 0x93, 0x01}, true},
+		{"H265 FU IDR middle", MimeTypeH265, []byte{0x62, 0x01, 0x13, 0x01}, false},
+		{"Opus", MimeTypeOpus, []byte{0x10, 0x00}, false},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.keyFrame, isFirstKeyFrameStart(&rtp.Packet{Payload: testCase.payload}, testCase.mimeType))
+		})
+	}
+
+	// The keyframes of VP9, AV1 and H265 are only detected for OnFirstKeyFrame
+	assert.False(t, isKeyFrameStart(&rtp.Packet{Payload: []byte{0x08, 0x00}}, MimeTypeVP9))
+}
+
+func TestTrackRemote_CheckFirstKeyFrameWithoutHandler(t *testing.T) {
+	track := &TrackRemote{kind: RTPCodecTypeVideo}
+	track.codec.MimeType = MimeTypeVP8
+	keyFrame, err := (&rtp.Packet{Header: rtp.Header{Version: 2}, Payload: []byte{0x10, 0x00, 0x01}}).Marshal()
+	require.NoError(t, err)
+
+	track.checkFirstKeyFrame(keyFrame)
+	assert.False(t, track.firstKeyFrameRead, "packets aren't checked without a handler")
+
+	fired := make(chan struct{})
+	track.OnFirstKeyFrame(func() {
+		close(fired)
+	})
+	track.checkFirstKeyFrame(keyFrame)
+	<-fired
+	assert.True(t, track.firstKeyFrameRead)
+}
+
+func TestTrackRemote_OnFirstKeyFrame(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	require.NoError(t, err)
+	_, err = pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	remoteTracks := make(chan *TrackRemote, 1)
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	pcAnswer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		remoteTracks <- trackRemote
+		onTrackFiredFunc()
+	})
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	// The P bit of the VP8 frame tag is set on interframes
+	interFrame := media.Sample{Data: []byte{0x01}, Duration: 20 * time.Millisecond}
+	keyFrame := media.Sample{Data: []byte{0x00}, Duration: 20 * time.Millisecond}
+	for onTrackFired.Err() == nil {
+		time.Sleep(20 * time.Millisecond)
+		assert.NoError(t, track.WriteSample(interFrame))
+	}
+	remoteTrack := <-remoteTracks
+
+	var fired atomic.Int32
+	firstKeyFrame, firstKeyFrameFunc := context.WithCancel(context.Background())
+	remoteTrack.OnFirstKeyFrame(func() {
+		fired.Add(1)
+		firstKeyFrameFunc()
+	})
+
+	read := make(chan struct{}, 100)
+	go func() {
+		defer close(read)
+		for {
+			if _, _, readErr := remoteTrack.ReadRTP(); readErr != nil {
+				return
+			}
+			read <- struct{}{}
+		}
+	}()
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, track.WriteSample(interFrame))
+		<-read
+	}
+	assert.Equal(t, int32(0), fired.Load())
+
+	for _, sample := range []media.Sample{keyFrame, interFrame, keyFrame} {
+		assert.NoError(t, track.WriteSample(sample))
+		<-read
+	}
+	<-firstKeyFrame.Done()
+
+	// A handler set after the first keyframe is invoked right away
+	late, lateFunc := context.WithCancel(context.Background())
+	remoteTrack.OnFirstKeyFrame(lateFunc)
+	<-late.Done()
+	assert.Equal(t, int32(1), fired.Load())
+
+	closePairNow(t, pcOffer, pcAnswer)
+	for range read {
+		assert.Equal(t, int32(1), fired.Load())
+	}
+}
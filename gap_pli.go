@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+const (
+	defaultGapPLIMinLostPackets = 20
+	defaultGapPLIMinDuration    = 500 * time.Millisecond
+	defaultGapPLIMinInterval    = time.Second
+)
+
+// GapPLIConfig sets the thresholds of the interceptor added by ConfigureGapPLI, the zero
+// value of each field uses its default.
+type GapPLIConfig struct {
+	// MinLostPackets is the number of consecutive sequence numbers that must be missing
+	// for a gap to be detected, 20 by default.
+	MinLostPackets uint16
+
+	// MinDuration detects a gap with fewer lost packets once the RTP timestamps before and
+	// after it are at least this far apart, 500ms by default. It catches losses of low
+	// bitrate streams.
+	MinDuration time.Duration
+
+	// MinInterval is the minimum time between two PLIs sent for a stream, 1s by default.
+	MinInterval time.Duration
+}
+
+// ConfigureGapPLI adds an interceptor that sends a Picture Loss Indication for a received
+// video stream when a long gap in its sequence numbers likely lost a keyframe and left the
+// decoder frozen. It is meant for receivers that don't request keyframes themselves, it
+// is off by default and not part of RegisterDefaultInterceptors.
+//
+// After a gap, a PLI is sent unless the next packet starts a keyframe, and again at most
+// every MinInterval until one is received. Keyframes are detected for VP8, VP9, H264, H265
+// and AV1, for other codecs a single PLI is sent per gap. Only streams that negotiated
+// "nack pli" feedback are watched.
+func ConfigureGapPLI(mediaEngine *MediaEngine, interceptorRegistry *interceptor.Registry, config GapPLIConfig) error {
+	if config.MinLostPackets == 0 {
+		config.MinLostPackets = defaultGapPLIMinLostPackets
+	}
+	if config.MinDuration == 0 {
+		config.MinDuration = defaultGapPLIMinDuration
+	}
+	if config.MinInterval == 0 {
+		config.MinInterval = defaultGapPLIMinInterval
+	}
+
+	mediaEngine.RegisterFeedback(RTCPFeedback{Type: TypeRTCPFBNACK, Parameter: "pli"}, RTPCodecTypeVideo)
+	interceptorRegistry.Add(&gapPLIInterceptorFactory{config: config})
+
+	return nil
+}
+
+type gapPLIInterceptorFactory struct {
+	config GapPLIConfig
+}
+
+func (f *gapPLIInterceptorFactory) NewInterceptor(string) (interceptor.Interceptor, error) {
+	return &gapPLIInterceptor{config: f.config}, nil
+}
+
+// gapPLIInterceptor watches the sequence numbers of remote video streams and writes a PLI
+// with the RTCP writer it is bound to when a gap is found.
+type gapPLIInterceptor struct {
+	interceptor.NoOp
+
+	config GapPLIConfig
+	writer atomic.Value // interceptor.RTCPWriter
+}
+
+func (g *gapPLIInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	g.writer.Store(writer)
+
+	return writer
+}
+
+func (g *gapPLIInterceptor) BindRemoteStream(
+	info *interceptor.StreamInfo,
+	reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	if !strings.HasPrefix(strings.ToLower(info.MimeType), "video/") || info.ClockRate == 0 || !hasPLIFeedback(info) {
+		return reader
+	}
+
+	stream := &gapPLIStream{
+		config:    g.config,
+		clockRate: info.ClockRate,
+		mimeType:  info.MimeType,
+	}
+
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attributes, err := reader.Read(b, a)
+		if err != nil {
+			return n, attributes, err
+		}
+
+		if attributes == nil {
+			attributes = make(interceptor.Attributes)
+		}
+		header, headerErr := attributes.GetRTPHeader(b[:n])
+		if headerErr != nil || header.MarshalSize() > n {
+			return n, attributes, nil
+		}
+
+		if !stream.check(header, b[header.MarshalSize():n], time.Now()) {
+			return n, attributes, nil
+		}
+		if writer, ok := g.writer.Load().(interceptor.RTCPWriter); ok {
+			// A failed PLI is requested again after MinInterval
+			_, _ = writer.Write([]rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: header.SSRC}}, nil)
+		}
+
+		return n, attributes, nil
+	})
+}
+
+func hasPLIFeedback(info *interceptor.StreamInfo) bool {
+	for _, feedback := range info.RTCPFeedback {
+		if feedback.Type == TypeRTCPFBNACK && feedback.Parameter == "pli" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canDetectKeyFrame reports whether isKeyFrameStart knows the keyframes of mimeType.
+func canDetectKeyFrame(mimeType string) bool {
+	return strings.EqualFold(mimeType, MimeTypeVP8) || strings.EqualFold(mimeType, MimeTypeH264)
+}
+
+// gapPLIStream is the gap detection state of a remote stream.
+type gapPLIStream struct {
+	mu sync.Mutex
+
+	config    GapPLIConfig
+	clockRate uint32
+	mimeType  string
+
+	started            bool
+	lastSequenceNumber uint16
+	lastTimestamp      uint32
+	waitingKeyFrame    bool
+	lastPLI            time.Time
+}
+
+// check processes a received packet and reports whether a PLI has to be sent.
+func (s *gapPLIStream) check(header *rtp.Header, payload []byte, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.started {
+		s.started = true
+		s.lastSequenceNumber, s.lastTimestamp = header.SequenceNumber, header.Timestamp
+	} else if diff := int16(header.SequenceNumber - s.lastSequenceNumber); diff > 0 { //nolint:gosec // G115
+		lost := uint16(diff) - 1
+		if lost > 0 && (lost >= s.config.MinLostPackets || s.elapsed(header.Timestamp) >= s.config.MinDuration) {
+			s.waitingKeyFrame = true
+		}
+		s.lastSequenceNumber, s.lastTimestamp = header.SequenceNumber, header.Timestamp
+	}
+
+	if !s.waitingKeyFrame {
+		return false
+	}
+
+	detectable := canDetectKeyFrame(s.mimeType)
+	if detectable && isKeyFrameStart(&rtp.Packet{Header: *header, Payload: payload}, s.mimeType) {
+		s.waitingKeyFrame = false
+
+		return false
+	}
+
+	if !s.lastPLI.IsZero() && now.Sub(s.lastPLI) < s.config.MinInterval {
+		return false
+	}
+
+	s.lastPLI = now
+	if !detectable {
+		s.waitingKeyFrame = false
+	}
+
+	return true
+}
+
+// elapsed returns the media time between the last packet and timestamp.
+func (s *gapPLIStream) elapsed(timestamp uint32) time.Duration {
+	diff := int32(timestamp - s.lastTimestamp) //nolint:gosec // G115
+	if diff <= 0 {
+		return 0
+	}
+
+	return time.Duration(diff) * time.Second / time.Duration(s.clockRate)
+}
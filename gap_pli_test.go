@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGapPLIStream(t *testing.T) {
+	config := GapPLIConfig{MinLostPackets: 10, MinDuration: time.Second, MinInterval: time.Second}
+	keyFrame, interFrame := []byte{0x10, 0x00, 0x01}, []byte{0x10, 0x01, 0x01}
+	now := time.Now()
+
+	t.Run("VP8", func(t *testing.T) {
+		stream := &gapPLIStream{config: config, clockRate: 90000, mimeType: MimeTypeVP8}
+		check := func(sequenceNumber uint16, timestamp uint32, payload []byte, at time.Duration) bool {
+			return stream.check(&rtp.Header{SequenceNumber: sequenceNumber, Timestamp: timestamp}, payload, now.Add(at))
+		}
+
+		assert.False(t, check(65530, 0, interFrame, 0))
+		assert.False(t, check(3, 0, interFrame, 0), "8 lost packets in the same frame are not a gap")
+		assert.False(t, check(2, 0, interFrame, 0), "reordered packets are not a gap")
+
+		assert.True(t, check(14, 3000, interFrame, 0))
+		assert.False(t, check(15, 3000, interFrame, 500*time.Millisecond), "PLIs are rate limited")
+		assert.True(t, check(16, 3000, interFrame, time.Second))
+		assert.False(t, check(17, 6000, keyFrame, 2*time.Second))
+		assert.False(t, check(18, 6000, interFrame, 3*time.Second))
+
+		// A few packets lost over a long time
+		assert.True(t, check(20, 6000+90000, interFrame, 4*time.Second))
+
+		// The packet after the gap starts a keyframe
+		stream.waitingKeyFrame = false
+		assert.False(t, check(40, 200000, keyFrame, 6*time.Second))
+	})
+
+	t.Run("Undetectable keyframes", func(t *testing.T) {
+		stream := &gapPLIStream{config: config, clockRate: 90000, mimeType: MimeTypeVP9}
+		assert.False(t, stream.check(&rtp.Header{SequenceNumber: 1}, keyFrame, now))
+		assert.True(t, stream.check(&rtp.Header{SequenceNumber: 20}, keyFrame, now))
+		assert.False(t, stream.check(&rtp.Header{SequenceNumber: 21}, keyFrame, now.Add(time.Hour)), "a single PLI per gap")
+	})
+}
+
+func TestGapPLIInterceptor(t *testing.T) {
+	mediaEngine := &MediaEngine{}
+	registry := &interceptor.Registry{}
+	require.NoError(t, ConfigureGapPLI(mediaEngine, registry, GapPLIConfig{}))
+	gapPLI, err := registry.Build("")
+	require.NoError(t, err)
+
+	var written []rtcp.Packet
+	gapPLI.BindRTCPWriter(interceptor.RTCPWriterFunc(func(pkts []rtcp.Packet, _ interceptor.Attributes) (int, error) {
+		written = append(written, pkts...)
+
+		return 0, nil
+	}))
+
+	var packets [][]byte
+	for _, sequenceNumber := range []uint16{1, 2, 30} {
+		pkt, marshalErr := (&rtp.Packet{
+			Header:  rtp.Header{Version: 2, SSRC: 5, SequenceNumber: sequenceNumber},
+			Payload: []byte{0x10, 0x01, 0x01},
+		}).Marshal()
+		require.NoError(t, marshalErr)
+		packets = append(packets, pkt)
+	}
+
+	for _, feedback := range [][]interceptor.RTCPFeedback{nil, {{Type: TypeRTCPFBNACK, Parameter: "pli"}}} {
+		written = nil
+		remaining := packets
+		reader := gapPLI.BindRemoteStream(&interceptor.StreamInfo{
+			SSRC:         5,
+			MimeType:     MimeTypeVP8,
+			ClockRate:    90000,
+			RTCPFeedback: feedback,
+		}, interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+			n := copy(b, remaining[0])
+			remaining = remaining[1:]
+
+			return n, a, nil
+		}))
+
+		b := make([]byte, 1500)
+		for range packets {
+			_, _, err = reader.Read(b, nil)
+			require.NoError(t, err)
+		}
+
+		if feedback == nil {
+			assert.Empty(t, written, "streams without PLI feedback are not watched")
+		} else {
+			assert.Equal(t, []rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: 5}}, written)
+		}
+	}
+	assert.NoError(t, gapPLI.Close())
+}
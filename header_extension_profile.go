@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpExtensionProfileOneByte = 0xBEDE
+	rtpExtensionProfileTwoByte = 0x1000
+
+	// rtpOneByteExtensionMaxID and rtpOneByteExtensionMaxLength are the limits of the
+	// one-byte header extensions, RFC 8285 section 4.2.
+	rtpOneByteExtensionMaxID     = 14
+	rtpOneByteExtensionMaxLength = 16
+)
+
+// RTPHeaderExtensionProfile is the form the header extensions of the packets sent by an
+// RTPSender are encoded with, see RTPSender.SetHeaderExtensionProfile.
+type RTPHeaderExtensionProfile int32
+
+const (
+	// RTPHeaderExtensionProfileAuto keeps the form chosen when the extensions were added
+	// to the packet: one-byte unless the first extension doesn't fit. This is the default.
+	RTPHeaderExtensionProfileAuto RTPHeaderExtensionProfile = iota
+
+	// RTPHeaderExtensionProfileOneByte encodes the extensions with the one-byte form of
+	// RFC 8285 section 4.2, a packet with an extension whose ID is above 14 or whose
+	// payload is empty or longer than 16 bytes still uses the two-byte form.
+	RTPHeaderExtensionProfileOneByte
+
+	// RTPHeaderExtensionProfileTwoByte encodes the extensions with the two-byte form of
+	// RFC 8285 section 4.3, which the remote must support, see extmap-allow-mixed.
+	RTPHeaderExtensionProfileTwoByte
+)
+
+// SetHeaderExtensionProfile pins the form of the header extensions of the packets sent,
+// whatever the IDs of the extensions. It is meant to test the interoperability with the
+// parsers of remote peers, it allocates a header for each rewritten packet.
+func (r *RTPSender) SetHeaderExtensionProfile(profile RTPHeaderExtensionProfile) error {
+	if profile < RTPHeaderExtensionProfileAuto || profile > RTPHeaderExtensionProfileTwoByte {
+		return errRTPSenderInvalidExtProfile
+	}
+
+	r.headerExtensionProfile.Store(int32(profile))
+
+	return nil
+}
+
+// HeaderExtensionProfile returns the form of the header extensions set with
+// SetHeaderExtensionProfile.
+func (r *RTPSender) HeaderExtensionProfile() RTPHeaderExtensionProfile {
+	return RTPHeaderExtensionProfile(r.headerExtensionProfile.Load())
+}
+
+// applyHeaderExtensionProfile returns header, or a copy with its extensions encoded with
+// the profile of the sender.
+func (r *RTPSender) applyHeaderExtensionProfile(header *rtp.Header) *rtp.Header {
+	profile := r.HeaderExtensionProfile()
+	if profile == RTPHeaderExtensionProfileAuto || !header.Extension {
+		return header
+	}
+
+	var extensionProfile uint16 = rtpExtensionProfileTwoByte
+	if profile == RTPHeaderExtensionProfileOneByte {
+		extensionProfile = rtpExtensionProfileOneByte
+	}
+	if header.ExtensionProfile == extensionProfile ||
+		(header.ExtensionProfile != rtpExtensionProfileOneByte && header.ExtensionProfile != rtpExtensionProfileTwoByte) {
+		return header
+	}
+
+	ids := header.GetExtensionIDs()
+	if extensionProfile == rtpExtensionProfileOneByte {
+		for _, id := range ids {
+			length := len(header.GetExtension(id))
+			if id > rtpOneByteExtensionMaxID || length == 0 || length > rtpOneByteExtensionMaxLength {
+				return header
+			}
+		}
+	}
+
+	rewritten := *header
+	rewritten.ExtensionProfile = extensionProfile
+	rewritten.Extensions = make([]rtp.Extension, 0, len(ids))
+	for _, id := range ids {
+		// The extensions were valid in the original form and fit in the new one
+		_ = rewritten.SetExtension(id, header.GetExtension(id))
+	}
+
+	return &rewritten
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRTPSender_SetHeaderExtensionProfile(t *testing.T) {
+	sender := &RTPSender{}
+	assert.Equal(t, RTPHeaderExtensionProfileAuto, sender.HeaderExtensionProfile())
+	assert.ErrorIs(t, sender.SetHeaderExtensionProfile(RTPHeaderExtensionProfile(3)), errRTPSenderInvalidExtProfile)
+
+	oneByte := &rtp.Header{}
+	require.NoError(t, oneByte.SetExtension(1, []byte{0x01}))
+	require.NoError(t, oneByte.SetExtension(3, []byte{0x02, 0x03}))
+	assert.Same(t, oneByte, sender.applyHeaderExtensionProfile(oneByte))
+
+	// Round trip the rewritten header to check its encoding
+	roundTrip := func(header *rtp.Header) *rtp.Header {
+		buf, err := header.Marshal()
+		require.NoError(t, err)
+		parsed := &rtp.Header{}
+		_, err = parsed.Unmarshal(buf)
+		require.NoError(t, err)
+
+		return parsed
+	}
+
+	require.NoError(t, sender.SetHeaderExtensionProfile(RTPHeaderExtensionProfileTwoByte))
+	twoByte := roundTrip(sender.applyHeaderExtensionProfile(oneByte))
+	assert.Equal(t, uint16(rtpExtensionProfileTwoByte), twoByte.ExtensionProfile)
+	assert.Equal(t, []byte{0x01}, twoByte.GetExtension(1))
+	assert.Equal(t, []byte{0x02, 0x03}, twoByte.GetExtension(3))
+	assert.Equal(t, uint16(rtpExtensionProfileOneByte), oneByte.ExtensionProfile, "the original is left unchanged")
+
+	require.NoError(t, sender.SetHeaderExtensionProfile(RTPHeaderExtensionProfileOneByte))
+	backToOneByte := roundTrip(sender.applyHeaderExtensionProfile(twoByte))
+	assert.Equal(t, uint16(rtpExtensionProfileOneByte), backToOneByte.ExtensionProfile)
+	assert.Equal(t, []byte{0x02, 0x03}, backToOneByte.GetExtension(3))
+
+	// IDs above 14 still force the two-byte form
+	require.NoError(t, twoByte.SetExtension(15, []byte{0x04}))
+	assert.Same(t, twoByte, sender.applyHeaderExtensionProfile(twoByte))
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"github.com/pion/rtp"
+)
+
+// RTPHeaderExtensionValues returns the payloads of the header extensions of an RTP packet by
+// their URI, the IDs are mapped with headerExtensions, the extensions negotiated for the
+// packet, as returned by RTPReceiver.GetParameters. Extensions whose ID isn't negotiated
+// are left out.
+//
+// values is cleared and filled, a nil map is allocated, so that reusing the map of the
+// previous packet avoids allocations. The payloads are not copied, they are only valid as
+// long as the packet is.
+func RTPHeaderExtensionValues(
+	header *rtp.Header,
+	headerExtensions []RTPHeaderExtensionParameter,
+	values map[string][]byte,
+) map[string][]byte {
+	if values == nil {
+		values = make(map[string][]byte, len(headerExtensions))
+	}
+	for uri := range values {
+		delete(values, uri)
+	}
+	if !header.Extension {
+		return values
+	}
+
+	for _, negotiated := range headerExtensions {
+		if payload := header.GetExtension(uint8(negotiated.ID)); payload != nil { //nolint:gosec // G115
+			values[negotiated.URI] = payload
+		}
+	}
+
+	return values
+}
+
+// HeaderExtensionValues returns the payloads of the header extensions of a packet of the
+// track by their URI, with the extensions negotiated for the track, see
+// RTPHeaderExtensionValues.
+func (t *TrackRemote) HeaderExtensionValues(header *rtp.Header, values map[string][]byte) map[string][]byte {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return RTPHeaderExtensionValues(header, t.params.HeaderExtensions, values)
+}
@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRTPHeaderExtensionValues(t *testing.T) {
+	headerExtensions := []RTPHeaderExtensionParameter{
+		{URI: sdp.SDESMidURI, ID: 1},
+		{URI: sdp.AudioLevelURI, ID: 3},
+	}
+
+	header := &rtp.Header{}
+	require.NoError(t, header.SetExtension(1, []byte("0")))
+	require.NoError(t, header.SetExtension(3, []byte{0x90}))
+	require.NoError(t, header.SetExtension(5, []byte{0x01}))
+
+	values := RTPHeaderExtensionValues(header, headerExtensions, nil)
+	assert.Equal(t, map[string][]byte{
+		sdp.SDESMidURI:    []byte("0"),
+		sdp.AudioLevelURI: {0x90},
+	}, values)
+
+	// The values of the previous packet are cleared
+	other := &rtp.Header{}
+	require.NoError(t, other.SetExtension(3, []byte{0x10}))
+	values = RTPHeaderExtensionValues(other, headerExtensions, values)
+	assert.Equal(t, map[string][]byte{sdp.AudioLevelURI: {0x10}}, values)
+
+	assert.Empty(t, RTPHeaderExtensionValues(&rtp.Header{}, headerExtensions, values))
+
+	allocs := testing.AllocsPerRun(10, func() {
+		values = RTPHeaderExtensionValues(header, headerExtensions, values)
+	})
+	assert.Zero(t, allocs)
+
+	track := &TrackRemote{params: RTPParameters{HeaderExtensions: headerExtensions}}
+	assert.Equal(t, map[string][]byte{sdp.AudioLevelURI: {0x10}}, track.HeaderExtensionValues(other, nil))
+}
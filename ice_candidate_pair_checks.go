@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"time"
+
+	"github.com/pion/ice/v4"
+)
+
+// defaultICECandidatePairChecksPollInterval is how often the connectivity checks are polled
+// when SettingEngine.SetICECandidatePairChecksPollInterval isn't set.
+const defaultICECandidatePairChecksPollInterval = 250 * time.Millisecond
+
+// ICECandidatePairChecksSample samples the connectivity checks of a candidate pair: it sums
+// the checks since the previous sample of that pair, see ICETransport.OnCandidatePairChecksSample.
+// It doesn't describe a single check.
+type ICECandidatePairChecksSample struct {
+	// Timestamp is the time the checks were collected.
+	Timestamp StatsTimestamp
+
+	// LocalCandidateID and RemoteCandidateID are the IDs of the ICECandidateStats of the
+	// candidates of the pair, the pair is the ICECandidatePairStats with the same IDs.
+	LocalCandidateID  string
+	RemoteCandidateID string
+
+	// State is the state of the pair, StatsICECandidatePairStateFailed once the agent gave up
+	// on it and StatsICECandidatePairStateSucceeded once a check succeeded.
+	State StatsICECandidatePairState
+
+	// Nominated is true once the pair is nominated, the nomination is reported once.
+	Nominated bool
+
+	// RequestsSent and ResponsesReceived are the checks sent on the pair, and the responses
+	// received for them, since the previous sample. Requests without responses are checks
+	// that failed or are still pending.
+	RequestsSent      uint64
+	ResponsesReceived uint64
+
+	// RoundTripTime is the round trip time, in seconds, of the last response received, 0 if
+	// no response was received since the previous sample.
+	RoundTripTime float64
+}
+
+// OnCandidatePairChecksSample sets an event handler which is invoked with samples of the
+// connectivity checks of every candidate pair, to debug why connectivity fails on some paths.
+// It is meant for diagnostics: pion/ice has no event for the checks, the stats of its pairs are
+// polled at the interval of SettingEngine.SetICECandidatePairChecksPollInterval while the
+// transport is checking, and a pair is sampled when its checks, its state or its nomination
+// changed since the previous poll. The polls stop once the transport is connected, failed or
+// closed, with a last sample, and start again with an ICE restart.
+//
+// Polling merges and misses checks: the checks between two polls are summed in one sample
+// with the round trip time of the last response only, a state the pair leaves before the next
+// poll is never reported, and the checks sent once connected, for consent freshness, aren't
+// sampled. Lower the interval for finer samples.
+//
+// The handler must be set before the checks start, it is invoked from a single goroutine in
+// the order of the polls. Once connected, the selected pair is reported by
+// OnSelectedCandidatePairChange and all pairs by the ICECandidatePairStats of GetStats.
+func (t *ICETransport) OnCandidatePairChecksSample(f func(ICECandidatePairChecksSample)) {
+	t.onCandidatePairChecksSampleHandler.Store(f)
+}
+
+// OnICECandidatePairChecksSample sets an event handler which is invoked with samples of the
+// connectivity checks of every candidate pair, see ICETransport.OnCandidatePairChecksSample.
+func (pc *PeerConnection) OnICECandidatePairChecksSample(f func(ICECandidatePairChecksSample)) {
+	pc.iceTransport.OnCandidatePairChecksSample(f)
+}
+
+// startCandidatePairChecks starts polling the candidate pairs of agent, unless there is no
+// handler or they are already polled.
+func (t *ICETransport) startCandidatePairChecks(agent *ice.Agent) {
+	handler, ok := t.onCandidatePairChecksSampleHandler.Load().(func(ICECandidatePairChecksSample))
+	if !ok || handler == nil || !t.candidatePairChecksRunning.CompareAndSwap(false, true) {
+		return
+	}
+
+	interval := t.gatherer.api.settingEngine.iceCandidatePairChecksPollInterval
+	if interval <= 0 {
+		interval = defaultICECandidatePairChecksPollInterval
+	}
+
+	go func() {
+		defer t.candidatePairChecksRunning.Store(false)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		previous := map[string]ice.CandidatePairStats{}
+		for range ticker.C {
+			state := t.State()
+			if state == ICETransportStateClosed {
+				return
+			}
+
+			for _, check := range candidatePairChecksSamples(agent.GetCandidatePairsStats(), previous) {
+				handler(check)
+			}
+
+			if state != ICETransportStateNew && state != ICETransportStateChecking {
+				return
+			}
+		}
+	}()
+}
+
+// candidatePairChecksSamples returns the samples of the pairs that changed since previous,
+// which is updated.
+func candidatePairChecksSamples(
+	pairs []ice.CandidatePairStats,
+	previous map[string]ice.CandidatePairStats,
+) []ICECandidatePairChecksSample {
+	checks := []ICECandidatePairChecksSample{}
+	for _, pair := range pairs {
+		key := newICECandidatePairStatsID(pair.LocalCandidateID, pair.RemoteCandidateID)
+		last := previous[key]
+		previous[key] = pair
+
+		if pair.RequestsSent == last.RequestsSent && pair.ResponsesReceived == last.ResponsesReceived &&
+			pair.State == last.State && pair.Nominated == last.Nominated {
+			continue
+		}
+
+		state, err := toStatsICECandidatePairState(pair.State)
+		if err != nil {
+			continue
+		}
+
+		check := ICECandidatePairChecksSample{
+			Timestamp:         statsTimestampFrom(pair.Timestamp),
+			LocalCandidateID:  pair.LocalCandidateID,
+			RemoteCandidateID: pair.RemoteCandidateID,
+			State:             state,
+			Nominated:         pair.Nominated,
+			RequestsSent:      pair.RequestsSent - last.RequestsSent,
+			ResponsesReceived: pair.ResponsesReceived - last.ResponsesReceived,
+		}
+		if check.ResponsesReceived != 0 {
+			check.RoundTripTime = pair.CurrentRoundTripTime
+		}
+		checks = append(checks, check)
+	}
+
+	return checks
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/ice/v4"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandidatePairChecksSamples(t *testing.T) {
+	previous := map[string]ice.CandidatePairStats{}
+	pair := ice.CandidatePairStats{
+		LocalCandidateID:  "local",
+		RemoteCandidateID: "remote",
+		State:             ice.CandidatePairStateInProgress,
+		RequestsSent:      2,
+	}
+
+	checks := candidatePairChecksSamples([]ice.CandidatePairStats{pair}, previous)
+	require.Len(t, checks, 1)
+	assert.Equal(t, StatsICECandidatePairStateInProgress, checks[0].State)
+	assert.Equal(t, uint64(2), checks[0].RequestsSent)
+	assert.Equal(t, uint64(0), checks[0].ResponsesReceived)
+	assert.Equal(t, float64(0), checks[0].RoundTripTime)
+
+	// Unchanged pairs aren't sampled
+	assert.Empty(t, candidatePairChecksSamples([]ice.CandidatePairStats{pair}, previous))
+
+	pair.State = ice.CandidatePairStateSucceeded
+	pair.RequestsSent, pair.ResponsesReceived = 3, 1
+	pair.CurrentRoundTripTime = 0.02
+	checks = candidatePairChecksSamples([]ice.CandidatePairStats{pair}, previous)
+	require.Len(t, checks, 1)
+	assert.Equal(t, StatsICECandidatePairStateSucceeded, checks[0].State)
+	assert.Equal(t, uint64(1), checks[0].RequestsSent)
+	assert.Equal(t, uint64(1), checks[0].ResponsesReceived)
+	assert.Equal(t, 0.02, checks[0].RoundTripTime)
+
+	pair.Nominated = true
+	checks = candidatePairChecksSamples([]ice.CandidatePairStats{pair}, previous)
+	require.Len(t, checks, 1)
+	assert.True(t, checks[0].Nominated)
+	assert.Equal(t, uint64(0), checks[0].ResponsesReceived)
+}
+
+func TestPeerConnection_OnICECandidatePairChecksSample(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	settingEngine := SettingEngine{}
+	settingEngine.SetICECandidatePairChecksPollInterval(10 * time.Millisecond)
+	pcOffer, pcAnswer, err := NewAPI(WithSettingEngine(settingEngine)).newPair(Configuration{})
+	require.NoError(t, err)
+
+	succeeded := make(chan ICECandidatePairChecksSample, 1)
+	pcOffer.OnICECandidatePairChecksSample(func(check ICECandidatePairChecksSample) {
+		if check.ResponsesReceived == 0 {
+			return
+		}
+		select {
+		case succeeded <- check:
+		default:
+		}
+	})
+
+	connected := untilConnectionState(PeerConnectionStateConnected, pcOffer, pcAnswer)
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	connected.Wait()
+
+	check := <-succeeded
+	assert.Equal(t, StatsICECandidatePairStateSucceeded, check.State)
+	assert.NotEmpty(t, check.LocalCandidateID)
+	assert.NotEmpty(t, check.RemoteCandidateID)
+	assert.NotZero(t, check.RequestsSent)
+
+	stats := pcOffer.GetStats()
+	_, ok := stats[newICECandidatePairStatsID(check.LocalCandidateID, check.RemoteCandidateID)]
+	assert.True(t, ok)
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+// RemoteICECandidates returns the remote candidates of the current ICE session: the ones of
+// the applied remote descriptions, then the ones added with AddICECandidate as they are
+// trickled, in the order they were added. A candidate is only listed once, even if a
+// renegotiation repeats it, and the list is cleared by an ICE restart.
+//
+// The candidates are listed as signaled, even those the ICE agent discards, like candidates
+// of network types that are not enabled. The peer reflexive candidates discovered during
+// the connectivity checks are not listed, they are reported by GetStats.
+func (pc *PeerConnection) RemoteICECandidates() []ICECandidate {
+	pc.remoteCandidatesLock.Lock()
+	defer pc.remoteCandidatesLock.Unlock()
+
+	return append([]ICECandidate{}, pc.remoteCandidates...)
+}
+
+func (pc *PeerConnection) addRemoteICECandidate(candidate ICECandidate) {
+	pc.remoteCandidatesLock.Lock()
+	defer pc.remoteCandidatesLock.Unlock()
+
+	for _, c := range pc.remoteCandidates {
+		if sameRemoteICECandidate(c, candidate) {
+			return
+		}
+	}
+	pc.remoteCandidates = append(pc.remoteCandidates, candidate)
+}
+
+func (pc *PeerConnection) resetRemoteICECandidates() {
+	pc.remoteCandidatesLock.Lock()
+	defer pc.remoteCandidatesLock.Unlock()
+
+	pc.remoteCandidates = nil
+}
+
+// sameRemoteICECandidate reports whether a and b are the same candidate, signaled for any
+// media section.
+func sameRemoteICECandidate(a, b ICECandidate) bool {
+	a.statsID, a.SDPMid, a.SDPMLineIndex = "", "", 0
+	b.statsID, b.SDPMid, b.SDPMLineIndex = "", "", 0
+
+	return a == b
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerConnection_RemoteICECandidates(t *testing.T) {
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	_, err = pcOffer.CreateDataChannel("data", nil)
+	require.NoError(t, err)
+
+	offer, err := pcOffer.CreateOffer(nil)
+	require.NoError(t, err)
+	gatherComplete := GatheringCompletePromise(pcOffer)
+	require.NoError(t, pcOffer.SetLocalDescription(offer))
+	<-gatherComplete
+
+	assert.Empty(t, pcAnswer.RemoteICECandidates())
+	require.NoError(t, pcAnswer.SetRemoteDescription(*pcOffer.LocalDescription()))
+
+	localCandidates, err := pcOffer.iceGatherer.GetLocalCandidates()
+	require.NoError(t, err)
+	require.NotEmpty(t, localCandidates)
+
+	remoteCandidates := pcAnswer.RemoteICECandidates()
+	require.Len(t, remoteCandidates, strings.Count(pcOffer.LocalDescription().SDP, "a=candidate:"))
+	for _, candidate := range remoteCandidates {
+		assert.Equal(t, "0", candidate.SDPMid)
+	}
+
+	// Trickled candidates are appended once
+	mid := "0"
+	trickled := ICECandidateInit{
+		Candidate: "candidate:1 1 udp 2130706431 192.0.2.1 5000 typ host",
+		SDPMid:    &mid,
+	}
+	require.NoError(t, pcAnswer.AddICECandidate(trickled))
+	require.NoError(t, pcAnswer.AddICECandidate(trickled))
+
+	candidates := pcAnswer.RemoteICECandidates()
+	require.Len(t, candidates, len(remoteCandidates)+1)
+	trickledCandidate := candidates[len(candidates)-1]
+	assert.Equal(t, "192.0.2.1", trickledCandidate.Address)
+	assert.Equal(t, uint16(5000), trickledCandidate.Port)
+	assert.Equal(t, "0", trickledCandidate.SDPMid)
+
+	// The returned slice is a copy
+	candidates[0].Address = "changed"
+	assert.NotEqual(t, "changed", pcAnswer.RemoteICECandidates()[0].Address)
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
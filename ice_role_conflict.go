@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"github.com/pion/ice/v4"
+	"github.com/pion/stun/v3"
+)
+
+// ICERoleConflict describes a connectivity check of a remote claiming the same ICE role
+// as the local agent, see RFC 8445 section 7.3.1.1.
+type ICERoleConflict struct {
+	// Role is the role claimed by both agents.
+	Role ICERole
+
+	// RemoteTieBreaker is the tie-breaker carried by the ICE-CONTROLLING or ICE-CONTROLLED
+	// attribute of the remote check. pion/ice doesn't expose the local one.
+	RemoteTieBreaker uint64
+}
+
+// OnRoleConflict sets an event handler which is invoked when a connectivity check of the
+// remote claims the same ICE role as the transport. It is invoked once per Start.
+//
+// Conflicts are detected from the binding requests pion/ice hands to the binding request
+// handler, see SettingEngine.SetICEBindingRequestHandler. pion/ice v4.0.10 discards the
+// checks of a remote claiming its role before calling that handler, so with that version
+// a conflict only shows as a failed ICE connection.
+func (t *ICETransport) OnRoleConflict(f func(ICERoleConflict)) {
+	t.onRoleConflictHandler.Store(f)
+}
+
+// OnICERoleConflict sets an event handler which is invoked when the remote claims the same
+// ICE role as the PeerConnection, see ICETransport.OnRoleConflict. Conflicts happen when the
+// remote doesn't follow RFC 8445 or with SettingEngine.SetICERole.
+func (pc *PeerConnection) OnICERoleConflict(f func(ICERoleConflict)) {
+	pc.iceTransport.OnRoleConflict(f)
+}
+
+// checkRoleConflict compares the role claimed by a binding request of the remote with the
+// local role, and reports the first conflict.
+func (t *ICETransport) checkRoleConflict(localRole ICERole, m *stun.Message) {
+	conflict, ok := newICERoleConflict(localRole, m)
+	if !ok || !t.roleConflictReported.CompareAndSwap(false, true) {
+		return
+	}
+
+	if handler, ok := t.onRoleConflictHandler.Load().(func(ICERoleConflict)); ok && handler != nil {
+		go handler(conflict)
+	}
+}
+
+func newICERoleConflict(localRole ICERole, m *stun.Message) (ICERoleConflict, bool) {
+	switch localRole {
+	case ICERoleControlling:
+		var controlling ice.AttrControlling
+		if err := controlling.GetFrom(m); err == nil {
+			return ICERoleConflict{Role: localRole, RemoteTieBreaker: uint64(controlling)}, true
+		}
+	case ICERoleControlled:
+		var controlled ice.AttrControlled
+		if err := controlled.GetFrom(m); err == nil {
+			return ICERoleConflict{Role: localRole, RemoteTieBreaker: uint64(controlled)}, true
+		}
+	default:
+	}
+
+	return ICERoleConflict{}, false
+}
+
+// bindingRequestHandler is the BindingRequestHandler of the agent, it lets the transport
+// check the binding requests for role conflicts before calling the one of the SettingEngine.
+func (g *ICEGatherer) bindingRequestHandler(
+	m *stun.Message,
+	local, remote ice.Candidate,
+	pair *ice.CandidatePair,
+) bool {
+	if handler, ok := g.onBindingRequestHandler.Load().(func(*stun.Message)); ok && handler != nil {
+		handler(m)
+	}
+
+	if handler := g.api.settingEngine.iceBindingRequestHandler; handler != nil {
+		return handler(m, local, remote, pair)
+	}
+
+	return false
+}
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/ice/v4"
+	"github.com/pion/logging"
+	"github.com/pion/stun/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewICERoleConflict(t *testing.T) {
+	controlling := stun.MustBuild(stun.BindingRequest, ice.AttrControlling(42))
+	controlled := stun.MustBuild(stun.BindingRequest, ice.AttrControlled(24))
+
+	testCases := []struct {
+		localRole        ICERole
+		message          *stun.Message
+		expectedConflict bool
+		expectedTie      uint64
+	}{
+		{ICERoleControlling, controlling, true, 42},
+		{ICERoleControlling, controlled, false, 0},
+		{ICERoleControlled, controlled, true, 24},
+		{ICERoleControlled, controlling, false, 0},
+		{ICERoleUnknown, controlling, false, 0},
+	}
+
+	for i, testCase := range testCases {
+		conflict, ok := newICERoleConflict(testCase.localRole, testCase.message)
+		assert.Equal(t, testCase.expectedConflict, ok, "testCase: %d", i)
+		if ok {
+			assert.Equal(t, testCase.localRole, conflict.Role, "testCase: %d", i)
+			assert.Equal(t, testCase.expectedTie, conflict.RemoteTieBreaker, "testCase: %d", i)
+		}
+	}
+}
+
+func TestICETransport_OnRoleConflict(t *testing.T) {
+	api := NewAPI()
+	gatherer, err := api.NewICEGatherer(ICEGatherOptions{})
+	require.NoError(t, err)
+	transport := NewICETransport(gatherer, logging.NewDefaultLoggerFactory())
+
+	conflicts := make(chan ICERoleConflict, 2)
+	transport.OnRoleConflict(func(conflict ICERoleConflict) {
+		conflicts <- conflict
+	})
+
+	transport.checkRoleConflict(ICERoleControlling, stun.MustBuild(stun.BindingRequest, ice.AttrControlled(1)))
+	for i := 0; i < 2; i++ {
+		transport.checkRoleConflict(ICERoleControlling, stun.MustBuild(stun.BindingRequest, ice.AttrControlling(7)))
+	}
+
+	select {
+	case conflict := <-conflicts:
+		assert.Equal(t, ICERoleConflict{Role: ICERoleControlling, RemoteTieBreaker: 7}, conflict)
+	case <-time.After(time.Second):
+		assert.Fail(t, "timed out waiting for the role conflict")
+	}
+
+	// Only the first conflict is reported
+	select {
+	case conflict := <-conflicts:
+		assert.Failf(t, "unexpected role conflict", "%v", conflict)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestICEGatherer_BindingRequestHandler(t *testing.T) {
+	settingEngine := SettingEngine{}
+	var userCalls int
+	settingEngine.SetICEBindingRequestHandler(func(*stun.Message, ice.Candidate, ice.Candidate, *ice.CandidatePair) bool {
+		userCalls++
+
+		return true
+	})
+
+	api := NewAPI(WithSettingEngine(settingEngine))
+	gatherer, err := api.NewICEGatherer(ICEGatherOptions{})
+	require.NoError(t, err)
+
+	var checks int
+	gatherer.onBindingRequestHandler.Store(func(*stun.Message) {
+		checks++
+	})
+
+	message := stun.MustBuild(stun.BindingRequest)
+	assert.True(t, gatherer.bindingRequestHandler(message, nil, nil, nil))
+	assert.Equal(t, 1, checks)
+	assert.Equal(t, 1, userCalls)
+
+	// Without a SettingEngine handler the agent keeps its selection
+	gatherer, err = NewAPI().NewICEGatherer(ICEGatherOptions{})
+	require.NoError(t, err)
+	assert.False(t, gatherer.bindingRequestHandler(message, nil, nil, nil))
+}
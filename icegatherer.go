@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/pion/ice/v4"
 	"github.com/pion/logging"
@@ -30,12 +31,21 @@ type ICEGatherer struct {
 
 	agent *ice.Agent
 
+	// Completes the gathering if SettingEngine.SetICEGatherTimeout was used
+	gatherTimer *time.Timer
+
 	onLocalCandidateHandler atomic.Value // func(candidate *ICECandidate)
 	onStateChangeHandler    atomic.Value // func(state ICEGathererState)
 
+	// Used for ICETransport.OnGatheringStateChange
+	onTransportStateChangeHandler atomic.Value // func(state ICEGathererState)
+
 	// Used for GatheringCompletePromise
 	onGatheringCompleteHandler atomic.Value // func()
 
+	// Used for ICETransport.OnRoleConflict
+	onBindingRequestHandler atomic.Value // func(m *stun.Message)
+
 	api *API
 
 	// Used to set the corresponding media stream identification tag and media description index
@@ -131,7 +141,15 @@ func (g *ICEGatherer) createAgent() error { //nolint:cyclop
 		ProxyDialer:            g.api.settingEngine.iceProxyDialer,
 		DisableActiveTCP:       g.api.settingEngine.iceDisableActiveTCP,
 		MaxBindingRequests:     g.api.settingEngine.iceMaxBindingRequests,
-		BindingRequestHandler:  g.api.settingEngine.iceBindingRequestHandler,
+		BindingRequestHandler:  g.bindingRequestHandler,
+	}
+
+	if dscp := g.api.settingEngine.dscp; dscp != nil {
+		dscpNet, err := newDSCPNet(g.api.settingEngine.net, *dscp, g.log)
+		if err != nil {
+			return err
+		}
+		config.Net = dscpNet
 	}
 
 	requestedNetworkTypes := g.api.settingEngine.candidates.ICENetworkTypes
@@ -166,7 +184,14 @@ func (g *ICEGatherer) Gather() error { //nolint:cyclop
 	}
 
 	g.setState(ICEGathererStateGathering)
-	if err := agent.OnCandidate(func(candidate ice.Candidate) {
+
+	// Completes the gathering once, when the agent is done or when the gather timeout elapsed
+	var completed atomic.Bool
+	complete := func() {
+		if !completed.CompareAndSwap(false, true) {
+			return
+		}
+
 		onLocalCandidateHandler := func(*ICECandidate) {}
 		if handler, ok := g.onLocalCandidateHandler.Load().(func(candidate *ICECandidate)); ok && handler != nil {
 			onLocalCandidateHandler = handler
@@ -177,6 +202,30 @@ func (g *ICEGatherer) Gather() error { //nolint:cyclop
 			onGatheringCompleteHandler = handler
 		}
 
+		g.setState(ICEGathererStateComplete)
+
+		onGatheringCompleteHandler()
+		onLocalCandidateHandler(nil)
+	}
+
+	if err := agent.OnCandidate(func(candidate ice.Candidate) {
+		if candidate == nil {
+			complete()
+
+			return
+		}
+
+		if completed.Load() {
+			g.log.Debugf("Candidate gathered after the gather timeout is not signaled: %s", candidate)
+
+			return
+		}
+
+		onLocalCandidateHandler := func(*ICECandidate) {}
+		if handler, ok := g.onLocalCandidateHandler.Load().(func(candidate *ICECandidate)); ok && handler != nil {
+			onLocalCandidateHandler = handler
+		}
+
 		sdpMid := ""
 
 		if mid, ok := g.sdpMid.Load().(string); ok {
@@ -185,24 +234,34 @@ func (g *ICEGatherer) Gather() error { //nolint:cyclop
 
 		sdpMLineIndex := uint16(g.sdpMLineIndex.Load()) //nolint:gosec // G115
 
-		if candidate != nil {
-			c, err := newICECandidateFromICE(candidate, sdpMid, sdpMLineIndex)
-			if err != nil {
-				g.log.Warnf("Failed to convert ice.Candidate: %s", err)
+		c, err := newICECandidateFromICE(candidate, sdpMid, sdpMLineIndex)
+		if err != nil {
+			g.log.Warnf("Failed to convert ice.Candidate: %s", err)
 
-				return
-			}
-			onLocalCandidateHandler(&c)
-		} else {
-			g.setState(ICEGathererStateComplete)
-
-			onGatheringCompleteHandler()
-			onLocalCandidateHandler(nil)
+			return
 		}
+		onLocalCandidateHandler(&c)
 	}); err != nil {
 		return err
 	}
 
+	if timeout := g.api.settingEngine.timeout.ICEGatherTimeout; timeout != nil {
+		g.lock.Lock()
+		if g.gatherTimer != nil {
+			g.gatherTimer.Stop()
+		}
+		g.gatherTimer = time.AfterFunc(*timeout, func() {
+			if g.getAgent() == nil {
+				return
+			}
+			if !completed.Load() {
+				g.log.Debugf("Gathering completed after the gather timeout of %s, servers still gathering are ignored", *timeout)
+			}
+			complete()
+		})
+		g.lock.Unlock()
+	}
+
 	return agent.GatherCandidates()
 }
 
@@ -228,6 +287,11 @@ func (g *ICEGatherer) close(shouldGracefullyClose bool) error {
 	g.lock.Lock()
 	defer g.lock.Unlock()
 
+	if g.gatherTimer != nil {
+		g.gatherTimer.Stop()
+		g.gatherTimer = nil
+	}
+
 	if g.agent == nil {
 		return nil
 	}
@@ -317,6 +381,9 @@ func (g *ICEGatherer) State() ICEGathererState {
 func (g *ICEGatherer) setState(s ICEGathererState) {
 	atomicStoreICEGathererState(&g.state, s)
 
+	if handler, ok := g.onTransportStateChangeHandler.Load().(func(state ICEGathererState)); ok && handler != nil {
+		handler(s)
+	}
 	if handler, ok := g.onStateChangeHandler.Load().(func(state ICEGathererState)); ok && handler != nil {
 		handler(s)
 	}
@@ -329,7 +396,9 @@ func (g *ICEGatherer) getAgent() *ice.Agent {
 	return g.agent
 }
 
-func (g *ICEGatherer) collectStats(collector *statsReportCollector) {
+// collectStats collects the stats of the candidates and candidate pairs, role is the role of
+// the ICETransport used to compute the pair priorities.
+func (g *ICEGatherer) collectStats(collector *statsReportCollector, role ICERole) { //nolint:cyclop
 	agent := g.getAgent()
 	if agent == nil {
 		return
@@ -337,6 +406,16 @@ func (g *ICEGatherer) collectStats(collector *statsReportCollector) {
 
 	collector.Collecting()
 	go func(collector *statsReportCollector, agent *ice.Agent) {
+		localCandidatesStats := agent.GetLocalCandidatesStats()
+		remoteCandidatesStats := agent.GetRemoteCandidatesStats()
+		priorities := make(map[string]uint32, len(localCandidatesStats)+len(remoteCandidatesStats))
+		for _, candidateStats := range localCandidatesStats {
+			priorities[candidateStats.ID] = candidateStats.Priority
+		}
+		for _, candidateStats := range remoteCandidatesStats {
+			priorities[candidateStats.ID] = candidateStats.Priority
+		}
+
 		for _, candidatePairStats := range agent.GetCandidatePairsStats() {
 			collector.Collecting()
 
@@ -347,11 +426,16 @@ func (g *ICEGatherer) collectStats(collector *statsReportCollector) {
 
 				continue
 			}
+			stats.Priority = iceCandidatePairPriority(
+				role,
+				priorities[candidatePairStats.LocalCandidateID],
+				priorities[candidatePairStats.RemoteCandidateID],
+			)
 
 			collector.Collect(stats.ID, stats)
 		}
 
-		for _, candidateStats := range agent.GetLocalCandidatesStats() {
+		for _, candidateStats := range localCandidatesStats {
 			collector.Collecting()
 
 			networkType, err := getNetworkType(candidateStats.NetworkType)
@@ -377,10 +461,13 @@ func (g *ICEGatherer) collectStats(collector *statsReportCollector) {
 				RelayProtocol: candidateStats.RelayProtocol,
 				Deleted:       candidateStats.Deleted,
 			}
+			stats.TypePreference, stats.LocalPreference, stats.ComponentID = iceCandidatePriorityPreferences(
+				candidateStats.Priority,
+			)
 			collector.Collect(stats.ID, stats)
 		}
 
-		for _, candidateStats := range agent.GetRemoteCandidatesStats() {
+		for _, candidateStats := range remoteCandidatesStats {
 			collector.Collecting()
 			networkType, err := getNetworkType(candidateStats.NetworkType)
 			if err != nil {
@@ -404,13 +491,16 @@ func (g *ICEGatherer) collectStats(collector *statsReportCollector) {
 				URL:           candidateStats.URL,
 				RelayProtocol: candidateStats.RelayProtocol,
 			}
+			stats.TypePreference, stats.LocalPreference, stats.ComponentID = iceCandidatePriorityPreferences(
+				candidateStats.Priority,
+			)
 			collector.Collect(stats.ID, stats)
 		}
 		collector.Done()
 	}(collector, agent)
 }
 
-func (g *ICEGatherer) getSelectedCandidatePairStats() (ICECandidatePairStats, bool) {
+func (g *ICEGatherer) getSelectedCandidatePairStats(role ICERole) (ICECandidatePairStats, bool) {
 	agent := g.getAgent()
 	if agent == nil {
 		return ICECandidatePairStats{}, false
@@ -428,5 +518,9 @@ func (g *ICEGatherer) getSelectedCandidatePairStats() (ICECandidatePairStats, bo
 		return ICECandidatePairStats{}, false
 	}
 
+	if pair, err := agent.GetSelectedCandidatePair(); err == nil && pair != nil {
+		stats.Priority = iceCandidatePairPriority(role, pair.Local.Priority(), pair.Remote.Priority())
+	}
+
 	return stats, true
 }
@@ -8,6 +8,8 @@ package webrtc
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"strings"
 	"testing"
 	"time"
@@ -84,6 +86,55 @@ func TestICEGather_mDNSCandidateGathering(t *testing.T) {
 	assert.NoError(t, gatherer.Close())
 }
 
+func TestICEGatherer_GatherTimeout(t *testing.T) {
+	// Limit runtime in case of deadlocks
+	lim := test.TimeOut(time.Second * 20)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	// A STUN server that never answers
+	deadServer, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	s := SettingEngine{}
+	s.SetSTUNGatherTimeout(time.Second * 10)
+	s.SetICEGatherTimeout(time.Millisecond * 200)
+	s.SetNetworkTypes([]NetworkType{NetworkTypeUDP4})
+	s.SetIncludeLoopbackCandidate(true)
+
+	gatherer, err := NewAPI(WithSettingEngine(s)).NewICEGatherer(ICEGatherOptions{
+		ICEServers: []ICEServer{{URLs: []string{fmt.Sprintf("stun:%s", deadServer.LocalAddr())}}},
+	})
+	assert.NoError(t, err)
+
+	gotHostCandidate := make(chan struct{})
+	gatherComplete := make(chan struct{})
+	gatherer.OnLocalCandidate(func(c *ICECandidate) {
+		if c == nil {
+			close(gatherComplete)
+		} else if c.Typ == ICECandidateTypeHost {
+			select {
+			case <-gotHostCandidate:
+			default:
+				close(gotHostCandidate)
+			}
+		}
+	})
+
+	start := time.Now()
+	assert.NoError(t, gatherer.Gather())
+
+	<-gotHostCandidate
+	<-gatherComplete
+	assert.Less(t, time.Since(start), time.Second*5)
+	assert.Equal(t, ICEGathererStateComplete, gatherer.State())
+
+	assert.NoError(t, gatherer.Close())
+	assert.NoError(t, deadServer.Close())
+}
+
 func TestICEGatherer_AlreadyClosed(t *testing.T) {
 	// Limit runtime in case of deadlocks
 	lim := test.TimeOut(time.Second * 20)
@@ -15,6 +15,7 @@ import (
 
 	"github.com/pion/ice/v4"
 	"github.com/pion/logging"
+	"github.com/pion/stun/v3"
 	"github.com/pion/webrtc/v4/internal/mux"
 	"github.com/pion/webrtc/v4/internal/util"
 )
@@ -26,9 +27,19 @@ type ICETransport struct {
 
 	role ICERole
 
+	// descriptionRole is the role negotiated by the session descriptions, it differs from
+	// role when it is forced with SettingEngine.SetICERole.
+	descriptionRole ICERole
+
 	onConnectionStateChangeHandler         atomic.Value // func(ICETransportState)
 	internalOnConnectionStateChangeHandler atomic.Value // func(ICETransportState)
 	onSelectedCandidatePairChangeHandler   atomic.Value // func(*ICECandidatePair)
+	onCandidatePairChecksSampleHandler     atomic.Value // func(ICECandidatePairChecksSample)
+	onRoleConflictHandler                  atomic.Value // func(ICERoleConflict)
+
+	roleConflictReported atomic.Bool
+
+	candidatePairChecksRunning atomic.Bool
 
 	state atomic.Value // ICETransportState
 
@@ -38,6 +49,9 @@ type ICETransport struct {
 
 	ctxCancel func()
 
+	// SRTP and SRTCP packets larger than the maximum packet size, see packetSizeLimitConn
+	oversizedPackets oversizedPacketCounts
+
 	loggerFactory logging.LoggerFactory
 
 	log logging.LeveledLogger
@@ -72,7 +86,7 @@ func (t *ICETransport) GetSelectedCandidatePair() (*ICECandidatePair, error) {
 // GetSelectedCandidatePairStats returns the selected candidate pair stats on which packets are sent
 // if there is no selected pair empty stats, false is returned to indicate stats not available.
 func (t *ICETransport) GetSelectedCandidatePairStats() (ICECandidatePairStats, bool) {
-	return t.gatherer.getSelectedCandidatePairStats()
+	return t.gatherer.getSelectedCandidatePairStats(t.Role())
 }
 
 // NewICETransport creates a new NewICETransport.
@@ -113,6 +127,9 @@ func (t *ICETransport) Start(gatherer *ICEGatherer, params ICEParameters, role *
 		state := newICETransportStateFromICE(iceState)
 
 		t.setState(state)
+		if state == ICETransportStateChecking {
+			t.startCandidatePairChecks(agent)
+		}
 		t.onConnectionStateChange(state)
 	}); err != nil {
 		return err
@@ -134,6 +151,11 @@ func (t *ICETransport) Start(gatherer *ICEGatherer, params ICEParameters, role *
 		role = &controlled
 	}
 	t.role = *role
+	localRole := *role
+	t.roleConflictReported.Store(false)
+	t.gatherer.onBindingRequestHandler.Store(func(m *stun.Message) {
+		t.checkRoleConflict(localRole, m)
+	})
 
 	ctx, ctxCancel := context.WithCancel(context.Background())
 	t.ctxCancel = ctxCancel
@@ -173,7 +195,7 @@ func (t *ICETransport) Start(gatherer *ICEGatherer, params ICEParameters, role *
 
 	config := mux.Config{
 		Conn:          t.conn,
-		BufferSize:    int(t.gatherer.api.settingEngine.getReceiveMTU()), //nolint:gosec // G115
+		BufferSize:    int(t.gatherer.api.settingEngine.getMuxBufferSize()), //nolint:gosec // G115
 		LoggerFactory: t.loggerFactory,
 	}
 	t.mux = mux.NewMux(config)
@@ -275,6 +297,52 @@ func (t *ICETransport) onConnectionStateChange(state ICETransportState) {
 	}
 }
 
+// GatheringState returns the candidate gathering state of the ICE transport, the
+// gatheringState of the RTCIceTransport.
+//
+// The state isn't tracked per component: the ICE agent only gathers candidates for the RTP
+// component, RTCP is always multiplexed with it, and a PeerConnection uses a single
+// ICETransport for all its media sections and data channels, whether they are bundled or not.
+// The state is therefore the state of the ICEGatherer of the transport, and it always matches
+// the aggregate PeerConnection.ICEGatheringState.
+func (t *ICETransport) GatheringState() ICEGatheringState {
+	if t.gatherer == nil {
+		return ICEGatheringStateNew
+	}
+
+	return newICEGatheringStateFromGatherer(t.gatherer.State())
+}
+
+// OnGatheringStateChange sets a handler that is fired when the candidate gathering state
+// of the ICE transport changes, the ongatheringstatechange of the RTCIceTransport. As the
+// state isn't tracked per component, see GatheringState, it fires with the same values as
+// the aggregate PeerConnection.OnICEGatheringStateChange, right before it.
+func (t *ICETransport) OnGatheringStateChange(f func(ICEGatheringState)) {
+	if t.gatherer == nil {
+		return
+	}
+
+	t.gatherer.onTransportStateChangeHandler.Store(func(gathererState ICEGathererState) {
+		switch gathererState {
+		case ICEGathererStateGathering, ICEGathererStateComplete:
+			f(newICEGatheringStateFromGatherer(gathererState))
+		default:
+			// Other states ignored
+		}
+	})
+}
+
+func newICEGatheringStateFromGatherer(gathererState ICEGathererState) ICEGatheringState {
+	switch gathererState {
+	case ICEGathererStateNew:
+		return ICEGatheringStateNew
+	case ICEGathererStateGathering:
+		return ICEGatheringStateGathering
+	default:
+		return ICEGatheringStateComplete
+	}
+}
+
 // Role indicates the current role of the ICE transport.
 func (t *ICETransport) Role() ICERole {
 	t.lock.RLock()
@@ -283,6 +351,26 @@ func (t *ICETransport) Role() ICERole {
 	return t.role
 }
 
+func (t *ICETransport) setDescriptionRole(role ICERole) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.descriptionRole = role
+}
+
+// getDescriptionRole returns the role negotiated by the session descriptions, which the
+// DTLS role follows, or the role of the transport if it wasn't started by a PeerConnection.
+func (t *ICETransport) getDescriptionRole() ICERole {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.descriptionRole != ICERoleUnknown {
+		return t.descriptionRole
+	}
+
+	return t.role
+}
+
 // SetRemoteCandidates sets the sequence of candidates associated with the remote ICETransport.
 func (t *ICETransport) SetRemoteCandidates(remoteCandidates []ICECandidate) error {
 	t.lock.RLock()
@@ -403,7 +491,7 @@ func (t *ICETransport) ensureGatherer() error {
 	return nil
 }
 
-func (t *ICETransport) collectStats(collector *statsReportCollector) {
+func (t *ICETransport) collectStats(collector *statsReportCollector, dtlsTransport *DTLSTransport) {
 	t.lock.Lock()
 	conn := t.conn
 	t.lock.Unlock()
@@ -420,6 +508,11 @@ func (t *ICETransport) collectStats(collector *statsReportCollector) {
 		stats.BytesSent = conn.BytesSent()
 		stats.BytesReceived = conn.BytesReceived()
 	}
+	stats.OversizedPacketsReceived = t.oversizedPackets.received.Load()
+	stats.OversizedPacketsSent = t.oversizedPackets.sent.Load()
+	if dtlsTransport != nil {
+		stats.SRTPCipher = dtlsTransport.srtpCipher()
+	}
 
 	collector.Collect(stats.ID, stats)
 }
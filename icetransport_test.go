@@ -157,3 +157,58 @@ func TestICETransport_GetLocalAndRemoteParameters(t *testing.T) {
 
 	closePairNow(t, offerer, answerer)
 }
+
+func TestICETransport_OnGatheringStateChange(t *testing.T) {
+	report := test.CheckRoutines(t)
+	defer report()
+
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	pc, err := NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	_, err = pc.CreateDataChannel("data", nil)
+	assert.NoError(t, err)
+
+	iceTransport := pc.SCTP().Transport().ICETransport()
+	assert.Equal(t, ICEGatheringStateNew, iceTransport.GatheringState())
+
+	var (
+		mu     sync.Mutex
+		states []string
+	)
+	gatheringComplete := make(chan struct{})
+	iceTransport.OnGatheringStateChange(func(state ICEGatheringState) {
+		mu.Lock()
+		defer mu.Unlock()
+		states = append(states, "transport "+state.String())
+	})
+	pc.OnICEGatheringStateChange(func(state ICEGatheringState) {
+		mu.Lock()
+		defer mu.Unlock()
+		states = append(states, "peerconnection "+state.String())
+		if state == ICEGatheringStateComplete {
+			close(gatheringComplete)
+		}
+	})
+
+	offer, err := pc.CreateOffer(nil)
+	assert.NoError(t, err)
+	assert.NoError(t, pc.SetLocalDescription(offer))
+	<-gatheringComplete
+
+	assert.Equal(t, ICEGatheringStateComplete, iceTransport.GatheringState())
+	assert.Equal(t, pc.ICEGatheringState(), iceTransport.GatheringState())
+
+	mu.Lock()
+	assert.Equal(t, []string{
+		"transport gathering",
+		"peerconnection gathering",
+		"transport complete",
+		"peerconnection complete",
+	}, states)
+	mu.Unlock()
+
+	assert.NoError(t, pc.Close())
+}
@@ -160,6 +160,22 @@ func ConfigureSimulcastExtensionHeaders(mediaEngine *MediaEngine) error {
 	)
 }
 
+// ConfigureAbsSendTimeHeaderExtension negotiates the abs-send-time RTP header extension for
+// audio and video, without any congestion control interceptor. Once negotiated, every packet
+// sent is stamped with the time it is handed over to SRTP, after the interceptors and any
+// pacing, and the send time of received packets is set with AttributeAbsSendTime.
+func ConfigureAbsSendTimeHeaderExtension(mediaEngine *MediaEngine) error {
+	if err := mediaEngine.RegisterHeaderExtension(
+		RTPHeaderExtensionCapability{URI: sdp.ABSSendTimeURI}, RTPCodecTypeVideo,
+	); err != nil {
+		return err
+	}
+
+	return mediaEngine.RegisterHeaderExtension(
+		RTPHeaderExtensionCapability{URI: sdp.ABSSendTimeURI}, RTPCodecTypeAudio,
+	)
+}
+
 type interceptorToTrackLocalWriter struct{ interceptor atomic.Value } // interceptor.RTPWriter }
 
 func (i *interceptorToTrackLocalWriter) WriteRTP(header *rtp.Header, payload []byte) (int, error) {
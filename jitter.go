@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+)
+
+// jitterEstimator computes the interarrival jitter of the packets of a track, the mean
+// deviation of the difference between their arrival and RTP timestamps, RFC 3550 section
+// 6.4.1 and appendix A.8.
+type jitterEstimator struct {
+	mu sync.Mutex
+
+	started   bool
+	ssrc      SSRC
+	clockRate uint32
+	transit   uint32 // arrival time minus RTP timestamp of the last packet, in timestamp units
+
+	jitter float64 // in timestamp units
+}
+
+// update adds the marshaled RTP packet b, of a codec sampled at clockRate, that arrived at now.
+func (e *jitterEstimator) update(b []byte, clockRate uint32, now time.Time) {
+	if clockRate == 0 || len(b) < rtpHeaderMinLength {
+		return
+	}
+
+	timestamp := binary.BigEndian.Uint32(b[4:8])
+	ssrc := SSRC(binary.BigEndian.Uint32(b[8:12]))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// The arrival time only needs to be in the same units as the RTP timestamps, the
+	// difference between two transit times wraps like the timestamps do.
+	nanoseconds := now.UnixNano()
+	arrival := uint32( //nolint:gosec // G115
+		nanoseconds/int64(time.Second)*int64(clockRate) + nanoseconds%int64(time.Second)*int64(clockRate)/int64(time.Second),
+	)
+	transit := arrival - timestamp
+
+	if !e.started || ssrc != e.ssrc || clockRate != e.clockRate {
+		e.started = true
+		e.ssrc = ssrc
+		e.clockRate = clockRate
+		e.transit = transit
+		e.jitter = 0
+
+		return
+	}
+
+	delta := float64(int32(transit - e.transit)) //nolint:gosec // G115
+	e.transit = transit
+	e.jitter += (math.Abs(delta) - e.jitter) / 16
+}
+
+// get returns the jitter in timestamp units and the clock rate of the timestamps.
+func (e *jitterEstimator) get() (jitter float64, clockRate uint32) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.jitter, e.clockRate
+}
+
+// jitterSeconds converts a jitter in timestamp units of a codec sampled at clockRate to
+// seconds.
+func jitterSeconds(jitter float64, clockRate uint32) float64 {
+	if clockRate == 0 {
+		return 0
+	}
+
+	return jitter / float64(clockRate)
+}
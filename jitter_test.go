@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJitterEstimator(t *testing.T) {
+	marshal := func(ssrc, timestamp uint32) []byte {
+		b, err := (&rtp.Packet{
+			Header:  rtp.Header{Version: 2, SSRC: ssrc, Timestamp: timestamp},
+			Payload: []byte{0x00},
+		}).Marshal()
+		require.NoError(t, err)
+
+		return b
+	}
+
+	// Packets of 20ms arriving alternately on time and 10ms late, the deviation of the
+	// transit time is always 10ms and the jitter converges to it.
+	for _, clockRate := range []uint32{8000, 48000, 90000} {
+		estimator := jitterEstimator{}
+		start := time.Unix(1700000000, 0)
+		timestamp := uint32(0xfffff000) // wraps around
+		for i := 0; i < 500; i++ {
+			arrival := start.Add(time.Duration(i) * 20 * time.Millisecond)
+			if i%2 == 1 {
+				arrival = arrival.Add(10 * time.Millisecond)
+			}
+			estimator.update(marshal(1, timestamp), clockRate, arrival)
+			timestamp += clockRate / 50
+		}
+
+		jitter, rate := estimator.get()
+		assert.Equal(t, clockRate, rate)
+		assert.InDelta(t, float64(clockRate)/100, jitter, 1)
+		assert.InDelta(t, 0.01, jitterSeconds(jitter, rate), 0.0001)
+
+		// A new SSRC restarts the estimation
+		estimator.update(marshal(2, 0), clockRate, start)
+		jitter, _ = estimator.get()
+		assert.Zero(t, jitter)
+	}
+
+	assert.Zero(t, jitterSeconds(10, 0))
+}
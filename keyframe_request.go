@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pion/rtcp"
+)
+
+// keyFrameRequestCounts are the keyframe requests sent and suppressed for a media SSRC.
+type keyFrameRequestCounts struct {
+	pli, fir, suppressed uint32
+	last                 time.Time
+}
+
+// keyFrameRequestLimiter drops the PLI and FIR sent for a media SSRC less than interval
+// after the previous one. interval is read from the SettingEngine when the limiter is used.
+type keyFrameRequestLimiter struct {
+	mu     sync.Mutex
+	counts map[uint32]*keyFrameRequestCounts
+}
+
+// allow reports whether a keyframe request for ssrc can be sent now and counts it.
+func (l *keyFrameRequestLimiter) allow(ssrc uint32, interval time.Duration, now time.Time, fir bool) bool {
+	if l.counts == nil {
+		l.counts = map[uint32]*keyFrameRequestCounts{}
+	}
+
+	counts, ok := l.counts[ssrc]
+	if !ok {
+		counts = &keyFrameRequestCounts{}
+		l.counts[ssrc] = counts
+	}
+
+	if interval > 0 && !counts.last.IsZero() && now.Sub(counts.last) < interval {
+		counts.suppressed++
+
+		return false
+	}
+
+	counts.last = now
+	if fir {
+		counts.fir++
+	} else {
+		counts.pli++
+	}
+
+	return true
+}
+
+// filter returns pkts without the keyframe requests that come too early, and whether any was
+// dropped. pkts is returned as is when nothing is dropped, it is never modified.
+func (l *keyFrameRequestLimiter) filter(pkts []rtcp.Packet, interval time.Duration) ([]rtcp.Packet, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	var filtered []rtcp.Packet
+	for i, pkt := range pkts {
+		keep, changed := pkt, false
+		switch p := pkt.(type) {
+		case *rtcp.PictureLossIndication:
+			if !l.allow(p.MediaSSRC, interval, now, false) {
+				keep, changed = nil, true
+			}
+		case *rtcp.FullIntraRequest:
+			var entries []rtcp.FIREntry
+			for _, entry := range p.FIR {
+				if l.allow(entry.SSRC, interval, now, true) {
+					entries = append(entries, entry)
+				}
+			}
+
+			switch {
+			case len(entries) == 0:
+				keep, changed = nil, true
+			case len(entries) != len(p.FIR):
+				keep = &rtcp.FullIntraRequest{SenderSSRC: p.SenderSSRC, MediaSSRC: p.MediaSSRC, FIR: entries}
+				changed = true
+			}
+		}
+
+		if changed && filtered == nil {
+			filtered = append(make([]rtcp.Packet, 0, len(pkts)), pkts[:i]...)
+		}
+		if filtered != nil && keep != nil {
+			filtered = append(filtered, keep)
+		}
+	}
+
+	if filtered == nil {
+		return pkts, false
+	}
+
+	return filtered, true
+}
+
+func (l *keyFrameRequestLimiter) getCounts(ssrc uint32) keyFrameRequestCounts {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if counts, ok := l.counts[ssrc]; ok {
+		return *counts
+	}
+
+	return keyFrameRequestCounts{}
+}
+
+// RequestKeyFrame sends a Picture Loss Indication asking the remote sender for a keyframe
+// of this track. With SettingEngine.SetKeyFrameRequestInterval, like every PLI and FIR written
+// to the DTLSTransport, it is dropped if a keyframe was already requested for the track less
+// than the interval ago: ErrKeyFrameRequestSuppressed is returned and the request is counted by
+// the KeyFrameRequestsSuppressed of its InboundRTPStreamStats.
+func (t *TrackRemote) RequestKeyFrame() error {
+	t.mu.RLock()
+	receiver := t.receiver
+	ssrc := t.ssrc
+	t.mu.RUnlock()
+
+	_, err := receiver.transport.WriteRTCP([]rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: uint32(ssrc)}})
+
+	return err
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyFrameRequestLimiter(t *testing.T) {
+	limiter := &keyFrameRequestLimiter{}
+	interval := time.Hour
+
+	receiverReport := &rtcp.ReceiverReport{SSRC: 1}
+	pkts := []rtcp.Packet{receiverReport, &rtcp.PictureLossIndication{MediaSSRC: 10}}
+	filtered, suppressed := limiter.filter(pkts, interval)
+	assert.Equal(t, pkts, filtered)
+	assert.False(t, suppressed)
+
+	// A second PLI is dropped, other packets are kept
+	filtered, suppressed = limiter.filter(pkts, interval)
+	assert.Equal(t, []rtcp.Packet{receiverReport}, filtered)
+	assert.True(t, suppressed)
+
+	fir := &rtcp.FullIntraRequest{MediaSSRC: 10, FIR: []rtcp.FIREntry{{SSRC: 10}, {SSRC: 20}}}
+	filtered, suppressed = limiter.filter([]rtcp.Packet{fir}, interval)
+	assert.Equal(t, []rtcp.Packet{&rtcp.FullIntraRequest{MediaSSRC: 10, FIR: []rtcp.FIREntry{{SSRC: 20}}}}, filtered)
+	assert.True(t, suppressed)
+	assert.Len(t, fir.FIR, 2, "packets written by the caller must not be modified")
+
+	filtered, suppressed = limiter.filter([]rtcp.Packet{fir}, interval)
+	assert.Empty(t, filtered)
+	assert.True(t, suppressed)
+
+	assert.Equal(t, keyFrameRequestCounts{pli: 1, suppressed: 3}, withoutLast(limiter.getCounts(10)))
+	assert.Equal(t, keyFrameRequestCounts{fir: 1, suppressed: 1}, withoutLast(limiter.getCounts(20)))
+	assert.Equal(t, keyFrameRequestCounts{}, limiter.getCounts(30))
+
+	// An interval of 0, the default, disables the limit
+	pli := []rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: 10}}
+	for i := 0; i < 2; i++ {
+		filtered, suppressed = limiter.filter(pli, 0)
+		assert.Equal(t, pli, filtered)
+		assert.False(t, suppressed)
+	}
+	assert.Equal(t, uint32(3), limiter.getCounts(10).pli)
+}
+
+func withoutLast(counts keyFrameRequestCounts) keyFrameRequestCounts {
+	counts.last = time.Time{}
+
+	return counts
+}
+
+func TestTrackRemote_RequestKeyFrame(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	settingEngine := SettingEngine{}
+	settingEngine.SetKeyFrameRequestInterval(time.Hour)
+	pcOffer, pcAnswer, err := NewAPI(WithSettingEngine(settingEngine)).newPair(Configuration{})
+	require.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	require.NoError(t, err)
+	sender, err := pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	remoteTracks := make(chan *TrackRemote, 1)
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	pcAnswer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		remoteTracks <- trackRemote
+		onTrackFiredFunc()
+	})
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	sendVideoUntilDone(t, onTrackFired.Done(), []*TrackLocalStaticSample{track})
+	remoteTrack := <-remoteTracks
+
+	assert.NoError(t, remoteTrack.RequestKeyFrame())
+	assert.ErrorIs(t, remoteTrack.RequestKeyFrame(), ErrKeyFrameRequestSuppressed)
+	assert.ErrorIs(t, pcAnswer.WriteRTCP([]rtcp.Packet{
+		&rtcp.PictureLossIndication{MediaSSRC: uint32(remoteTrack.SSRC())},
+		&rtcp.ReceiverReport{SSRC: 1},
+	}), ErrKeyFrameRequestSuppressed)
+
+	// Receiver reports may be read before the PLI
+	func() {
+		for {
+			pkts, _, readErr := sender.ReadRTCP()
+			require.NoError(t, readErr)
+			for _, pkt := range pkts {
+				if pli, ok := pkt.(*rtcp.PictureLossIndication); ok {
+					assert.Equal(t, uint32(remoteTrack.SSRC()), pli.MediaSSRC)
+
+					return
+				}
+			}
+		}
+	}()
+
+	stats, ok := pcAnswer.GetStats().GetInboundRTPStreamStats(remoteTrack)
+	require.True(t, ok)
+	assert.Equal(t, uint32(1), stats.PLICount)
+	assert.Equal(t, uint32(2), stats.KeyFrameRequestsSuppressed)
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
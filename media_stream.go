@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+
+	"github.com/pion/webrtc/v4/pkg/rtcerr"
+)
+
+const mediaStreamIDLength = 16
+
+// MediaStream groups local tracks of a PeerConnection, such as the audio and the video of a
+// camera. Its tracks are announced with its ID in their msid and CNAME, whatever the
+// StreamID of the tracks, so that the remote puts them in a single stream and synchronizes
+// them.
+type MediaStream struct {
+	id string
+	pc *PeerConnection
+
+	mu      sync.Mutex
+	senders []*RTPSender
+}
+
+// NewMediaStream returns an empty MediaStream with id, a random ID if it is empty.
+func (pc *PeerConnection) NewMediaStream(id string) *MediaStream {
+	if id == "" {
+		id = pc.api.settingEngine.random.alpha(mediaStreamIDLength)
+	}
+
+	return &MediaStream{id: id, pc: pc}
+}
+
+// ID returns the ID of the stream, the first value of the msid of its tracks.
+func (s *MediaStream) ID() string {
+	return s.id
+}
+
+// AddTrack adds track to the PeerConnection, like PeerConnection.AddTrack, as a track of
+// the stream.
+func (s *MediaStream) AddTrack(track TrackLocal) (*RTPSender, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sender := range s.senders {
+		if sender.Track() == track {
+			return nil, &rtcerr.InvalidAccessError{Err: ErrExistingTrack}
+		}
+	}
+
+	sender, err := s.pc.addTrack(track, s.id)
+	if err != nil {
+		return nil, err
+	}
+	s.senders = append(s.senders, sender)
+
+	return sender, nil
+}
+
+// RemoveTrack removes track from the stream and from the PeerConnection, like
+// PeerConnection.RemoveTrack.
+func (s *MediaStream) RemoveTrack(track TrackLocal) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, sender := range s.senders {
+		if sender.Track() != track {
+			continue
+		}
+		if err := s.pc.RemoveTrack(sender); err != nil {
+			return err
+		}
+		s.senders = append(s.senders[:i], s.senders[i+1:]...)
+
+		return nil
+	}
+
+	return &rtcerr.InvalidAccessError{Err: ErrSenderNotCreatedByConnection}
+}
+
+// Tracks returns the tracks of the stream.
+func (s *MediaStream) Tracks() []TrackLocal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tracks := make([]TrackLocal, 0, len(s.senders))
+	for _, sender := range s.senders {
+		if track := sender.Track(); track != nil {
+			tracks = append(tracks, track)
+		}
+	}
+
+	return tracks
+}
+
+// mediaStreamID returns the stream the track of the sender is announced in.
+func (r *RTPSender) mediaStreamID(track TrackLocal) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.streamID != "" {
+		return r.streamID
+	}
+
+	return track.StreamID()
+}
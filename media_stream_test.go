@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/sdp/v3"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaStream(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	assert.Len(t, pcOffer.NewMediaStream("").ID(), mediaStreamIDLength)
+
+	stream := pcOffer.NewMediaStream("camera")
+	audio, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeOpus}, "audio", "microphone")
+	require.NoError(t, err)
+	video, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "webcam")
+	require.NoError(t, err)
+	_, err = stream.AddTrack(audio)
+	require.NoError(t, err)
+	_, err = stream.AddTrack(video)
+	require.NoError(t, err)
+	_, err = stream.AddTrack(video)
+	assert.ErrorIs(t, err, ErrExistingTrack)
+	assert.Equal(t, []TrackLocal{audio, video}, stream.Tracks())
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	streamIDs := make(chan string, 2)
+	pcAnswer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		streamIDs <- trackRemote.StreamID()
+		wg.Done()
+	})
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	msids := []string{}
+	for _, media := range pcOffer.LocalDescription().parsed.MediaDescriptions {
+		if msid, ok := media.Attribute(sdp.AttrKeyMsid); ok {
+			msids = append(msids, msid)
+		}
+		for _, attribute := range media.Attributes {
+			if attribute.Key == sdp.AttrKeySSRC && !strings.Contains(attribute.Value, "label:") {
+				assert.Contains(t, attribute.Value, "camera", "the cname and msid of the SSRC")
+			}
+		}
+	}
+	assert.Equal(t, []string{"camera audio", "camera video"}, msids)
+	assert.True(t, pcOffer.IsOfferNoOp(), "the msid of the stream was negotiated")
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	sendVideoUntilDone(t, done, []*TrackLocalStaticSample{audio, video})
+	assert.Equal(t, "camera", <-streamIDs)
+	assert.Equal(t, "camera", <-streamIDs)
+
+	require.NoError(t, stream.RemoveTrack(audio))
+	assert.Equal(t, []TrackLocal{video}, stream.Tracks())
+	assert.ErrorIs(t, stream.RemoveTrack(audio), ErrSenderNotCreatedByConnection)
+	assert.False(t, pcOffer.IsOfferNoOp())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
@@ -81,6 +81,10 @@ func (m *MediaEngine) RegisterDefaultCodecs() error {
 			RTPCodecCapability: RTPCodecCapability{MimeTypePCMA, 8000, 0, "", nil},
 			PayloadType:        rtp.PayloadTypePCMA,
 		},
+		{
+			RTPCodecCapability: RTPCodecCapability{MimeTypeCN, 8000, 0, "", nil},
+			PayloadType:        rtp.PayloadTypeCN,
+		},
 	} {
 		if err := m.RegisterCodec(codec, RTPCodecTypeAudio); err != nil {
 			return err
@@ -574,6 +578,52 @@ func (m *MediaEngine) updateHeaderExtension(id int, extension string, typ RTPCod
 	return nil
 }
 
+// Update the fmtp of already negotiated codecs from a remote media section. This allows
+// the remote to change codec parameters in a renegotiation, changes that would modify the
+// codec configuration are ignored.
+func (m *MediaEngine) updateFmtpFromMediaSection(media *sdp.MediaDescription, typ RTPCodecType) error {
+	var negotiatedCodecs []RTPCodecParameters
+	switch typ {
+	case RTPCodecTypeAudio:
+		negotiatedCodecs = m.negotiatedAudioCodecs
+	case RTPCodecTypeVideo:
+		negotiatedCodecs = m.negotiatedVideoCodecs
+	default:
+		return nil
+	}
+
+	remoteCodecs, err := codecsFromMediaDescription(media)
+	if err != nil {
+		return err
+	}
+
+	var updatedCodecs []RTPCodecParameters
+	for _, remoteCodec := range remoteCodecs {
+		for i, codec := range negotiatedCodecs {
+			if codec.PayloadType != remoteCodec.PayloadType || codec.SDPFmtpLine == remoteCodec.SDPFmtpLine ||
+				!codecFmtpCompatible(codec, remoteCodec) {
+				continue
+			}
+
+			// Copy before updating, the negotiated codecs may be in use by readers
+			if updatedCodecs == nil {
+				updatedCodecs = append([]RTPCodecParameters{}, negotiatedCodecs...)
+			}
+			updatedCodecs[i].SDPFmtpLine = remoteCodec.SDPFmtpLine
+		}
+	}
+
+	switch {
+	case updatedCodecs == nil:
+	case typ == RTPCodecTypeAudio:
+		m.negotiatedAudioCodecs = updatedCodecs
+	default:
+		m.negotiatedVideoCodecs = updatedCodecs
+	}
+
+	return nil
+}
+
 func (m *MediaEngine) pushCodecs(codecs []RTPCodecParameters, typ RTPCodecType) error {
 	var joinedErr error
 	for _, codec := range codecs {
@@ -620,6 +670,10 @@ func (m *MediaEngine) updateFromRemoteDescription(desc sdp.SessionDescription) e
 				return err
 			}
 
+			if err := m.updateFmtpFromMediaSection(media, typ); err != nil {
+				return err
+			}
+
 			if !m.negotiateMultiCodecs || (typ != RTPCodecTypeAudio && typ != RTPCodecTypeVideo) {
 				continue
 			}
@@ -31,6 +31,13 @@ const (
 	// MimeTypePCMA PCMA MIME type
 	// Note: Matching should be case insensitive.
 	MimeTypePCMA = "audio/PCMA"
+	// MimeTypeTelephoneEvent telephone-event MIME type, DTMF events of RFC 4733
+	// Note: Matching should be case insensitive.
+	MimeTypeTelephoneEvent = "audio/telephone-event"
+	// MimeTypeCN comfort noise MIME type of RFC 3389, usually registered with the static
+	// payload type rtp.PayloadTypeCN and a clock rate of 8000, see ComfortNoise.
+	// Note: Matching should be case insensitive.
+	MimeTypeCN = "audio/CN"
 	// MimeTypeRTX RTX MIME type
 	// Note: Matching should be case insensitive.
 	MimeTypeRTX = "video/rtx"
@@ -26,4 +26,9 @@ type OfferOptions struct {
 	// When this value is true, the generated description will have ICE
 	// credentials that are different from the current credentials
 	ICERestart bool
+
+	// SkipIfNoOp makes CreateOffer return ErrNoOpOffer instead of an offer that would not
+	// change the negotiated session, see PeerConnection.IsOfferNoOp. It is ignored when
+	// ICERestart is set.
+	SkipIfNoOp bool
 }
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"fmt"
+	"net"
+	"sync/atomic"
+
+	"github.com/pion/logging"
+	"github.com/pion/webrtc/v4/internal/mux"
+)
+
+// oversizedPacketCounts counts the SRTP and SRTCP packets larger than the maximum packet size.
+type oversizedPacketCounts struct {
+	received, sent atomic.Uint32
+}
+
+// packetSizeLimitConn is the conn of a SRTP or SRTCP session. It drops the received packets
+// larger than maxSize and refuses to send them, instead of letting them be truncated by the
+// read buffers or fragmented on the path. It is only used when a maximum is set.
+type packetSizeLimitConn struct {
+	net.Conn
+
+	protocol string
+	maxSize  int
+	counts   *oversizedPacketCounts
+	log      logging.LeveledLogger
+}
+
+func (t *DTLSTransport) newPacketSizeLimitConn(endpoint *mux.Endpoint, protocol string, maxSize uint) net.Conn {
+	if maxSize == 0 {
+		return endpoint
+	}
+
+	return &packetSizeLimitConn{
+		Conn:     endpoint,
+		protocol: protocol,
+		maxSize:  int(maxSize), //nolint:gosec // G115
+		counts:   &t.iceTransport.oversizedPackets,
+		log:      t.log,
+	}
+}
+
+func (c *packetSizeLimitConn) Read(b []byte) (int, error) {
+	for {
+		n, err := c.Conn.Read(b)
+		if err != nil || n <= c.maxSize {
+			return n, err
+		}
+
+		c.counts.received.Add(1)
+		c.log.Warnf("Dropped received %s packet of %d bytes or more, larger than the maximum of %d", c.protocol, n, c.maxSize)
+	}
+}
+
+func (c *packetSizeLimitConn) Write(b []byte) (int, error) {
+	if len(b) > c.maxSize {
+		c.counts.sent.Add(1)
+
+		return 0, fmt.Errorf("%w: %s packet of %d bytes, maximum is %d", ErrPacketTooLarge, c.protocol, len(b), c.maxSize)
+	}
+
+	return c.Conn.Write(b)
+}
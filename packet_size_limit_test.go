@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pion/logging"
+	"github.com/pion/rtp"
+	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4/internal/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacketSizeLimitConn(t *testing.T) {
+	local, remote := net.Pipe()
+	counts := &oversizedPacketCounts{}
+	conn := &packetSizeLimitConn{
+		Conn:     local,
+		protocol: "SRTP",
+		maxSize:  4,
+		counts:   counts,
+		log:      logging.NewDefaultLoggerFactory().NewLogger("test"),
+	}
+
+	go func() {
+		for _, packet := range [][]byte{{1, 2, 3, 4, 5}, {1, 2, 3, 4}} {
+			_, err := remote.Write(packet)
+			assert.NoError(t, err)
+		}
+	}()
+
+	b := make([]byte, 10)
+	n, err := conn.Read(b)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3, 4}, b[:n])
+	assert.Equal(t, uint32(1), counts.received.Load())
+
+	_, err = conn.Write([]byte{1, 2, 3, 4, 5})
+	assert.ErrorIs(t, err, ErrPacketTooLarge)
+	assert.Equal(t, uint32(1), counts.sent.Load())
+
+	assert.NoError(t, local.Close())
+	assert.NoError(t, remote.Close())
+
+	// Without a maximum the endpoint isn't wrapped and the read buffers keep the receive MTU
+	_, limited := (&DTLSTransport{}).newPacketSizeLimitConn(&mux.Endpoint{}, "SRTP", 0).(*packetSizeLimitConn)
+	assert.False(t, limited)
+	assert.Equal(t, uint(receiveMTU), (&SettingEngine{}).getMuxBufferSize())
+}
+
+func TestPeerConnection_MaxSRTPPacketSize(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	settingEngine := SettingEngine{}
+	settingEngine.SetMaxSRTPPacketSize(300)
+
+	pcOffer, err := NewPeerConnection(Configuration{})
+	require.NoError(t, err)
+	pcAnswer, err := NewAPI(WithSettingEngine(settingEngine)).NewPeerConnection(Configuration{})
+	require.NoError(t, err)
+
+	offerTrack, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "offer")
+	require.NoError(t, err)
+	_, err = pcOffer.AddTrack(offerTrack)
+	require.NoError(t, err)
+
+	answerTrack, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "answer")
+	require.NoError(t, err)
+	_, err = pcAnswer.AddTrack(answerTrack)
+	require.NoError(t, err)
+
+	connected := untilConnectionState(PeerConnectionStateConnected, pcOffer, pcAnswer)
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	connected.Wait()
+
+	transportStats := func(pc *PeerConnection) TransportStats {
+		stats, ok := pc.GetStats()["iceTransport"].(TransportStats)
+		require.True(t, ok)
+
+		return stats
+	}
+
+	// Packets larger than the maximum are dropped by the receiver
+	for sequenceNumber := uint16(0); transportStats(pcAnswer).OversizedPacketsReceived == 0; sequenceNumber++ {
+		time.Sleep(20 * time.Millisecond)
+		assert.NoError(t, offerTrack.WriteRTP(&rtp.Packet{
+			Header:  rtp.Header{Version: 2, SequenceNumber: sequenceNumber},
+			Payload: make([]byte, 400),
+		}))
+	}
+
+	// and fail to be sent, once the SRTP session is started
+	for sequenceNumber := uint16(0); ; sequenceNumber++ {
+		err = answerTrack.WriteRTP(&rtp.Packet{
+			Header:  rtp.Header{Version: 2, SequenceNumber: sequenceNumber},
+			Payload: make([]byte, 400),
+		})
+		if err != nil {
+			assert.True(t, strings.Contains(err.Error(), ErrPacketTooLarge.Error()), err)
+
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.Equal(t, uint32(1), transportStats(pcAnswer).OversizedPacketsSent)
+	assert.Zero(t, transportStats(pcOffer).OversizedPacketsReceived)
+
+	// Smaller packets are still sent
+	assert.NoError(t, answerTrack.WriteRTP(&rtp.Packet{
+		Header:  rtp.Header{Version: 2, SequenceNumber: 1000},
+		Payload: make([]byte, 100),
+	}))
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
@@ -74,12 +74,17 @@ type PeerConnection struct {
 	rtpTransceivers        []*RTPTransceiver
 	nonMediaBandwidthProbe atomic.Value // RTPReceiver
 
+	// number of running goroutines started with goTracked
+	goroutines atomic.Int64
+
 	onSignalingStateChangeHandler     func(SignalingState)
 	onICEConnectionStateChangeHandler atomic.Value // func(ICEConnectionState)
 	onConnectionStateChangeHandler    atomic.Value // func(PeerConnectionState)
 	onTrackHandler                    func(*TrackRemote, *RTPReceiver)
 	onDataChannelHandler              func(*DataChannel)
 	onNegotiationNeededHandler        atomic.Value // func()
+	onReadyHandler                    atomic.Value // func()
+	isReady                           atomic.Bool
 
 	iceGatherer   *ICEGatherer
 	iceTransport  *ICETransport
@@ -90,7 +95,18 @@ type PeerConnection struct {
 	api *API
 	log logging.LeveledLogger
 
+	// The API the connection was created with, see Clone
+	parentAPI *API
+
 	interceptorRTCPWriter interceptor.RTCPWriter
+
+	// Candidates of the applied remote descriptions and of AddICECandidate, see RemoteICECandidates
+	remoteCandidatesLock sync.Mutex
+	remoteCandidates     []ICECandidate
+
+	// Candidates added before the remote description, see SetEarlyICECandidateBufferSize
+	earlyCandidatesLock sync.Mutex
+	earlyCandidates     []ICECandidateInit
 }
 
 // NewPeerConnection creates a PeerConnection with the default codecs and interceptors.
@@ -134,8 +150,8 @@ func (api *API) NewPeerConnection(configuration Configuration) (*PeerConnection,
 		greaterMid:                              -1,
 		signalingState:                          SignalingStateStable,
 
-		api: api,
-		log: api.settingEngine.LoggerFactory.NewLogger("pc"),
+		api:       api,
+		parentAPI: api,
 	}
 	pc.ops = newOperations(pc.updateNegotiationNeededFlagOnEmptyChain, pc.onNegotiationNeeded)
 
@@ -152,6 +168,18 @@ func (api *API) NewPeerConnection(configuration Configuration) (*PeerConnection,
 		interceptor:   i,
 	}
 
+	if api.settingEngine.structuredLogger != nil || api.settingEngine.random != nil {
+		settingEngine := *api.settingEngine
+		if settingEngine.structuredLogger != nil {
+			// Tag every message of this PeerConnection with its ID
+			settingEngine.LoggerFactory = newStructuredLoggerFactory(settingEngine.structuredLogger, pc.statsID)
+		}
+		// The values of this PeerConnection don't depend on what the others generate
+		settingEngine.random = settingEngine.random.fork()
+		pc.api.settingEngine = &settingEngine
+	}
+	pc.log = pc.api.settingEngine.LoggerFactory.NewLogger("pc")
+
 	if api.settingEngine.disableMediaEngineCopy {
 		pc.api.mediaEngine = api.mediaEngine
 	} else {
@@ -399,10 +427,19 @@ func (pc *PeerConnection) checkNegotiationNeeded() bool { //nolint:gocognit,cycl
 				// As calling replaceTrack does not require renegotiation, we skip check for this transceiver
 				continue
 			}
-			if !okMsid || descMsid != track.StreamID()+" "+track.ID() {
+			if !okMsid || descMsid != sender.mediaStreamID(track)+" "+track.ID() {
 				return true
 			}
 		}
+		// fmtp updated with SetCodecFmtp since the last negotiation
+		if transceiver.fmtpChanged(mid) {
+			return true
+		}
+		// RTCP feedback updated with SetRTCPFeedback since the last negotiation
+		if transceiver.rtcpFeedbackChanged(mid) {
+			return true
+		}
+
 		switch localDesc.Type {
 		case SDPTypeOffer:
 			// Step 5.3.2
@@ -433,6 +470,26 @@ func (pc *PeerConnection) checkNegotiationNeeded() bool { //nolint:gocognit,cycl
 	return false
 }
 
+// IsOfferNoOp reports whether an offer created now would not change the negotiated session,
+// so the renegotiation can be skipped, see OfferOptions.SkipIfNoOp. It is the check the
+// negotiationneeded event relies on. An offer is a no-op when the signaling state is stable
+// with a local and a remote description applied, and since that negotiation:
+//   - no transceiver was added and no data channel was created without a negotiated one,
+//   - the direction of no transceiver was changed,
+//   - no sender started sending a track with another stream or track ID,
+//   - SetCodecFmtp and SetRTCPFeedback didn't change the codecs of a transceiver.
+//
+// Changes that don't need a renegotiation, such as ReplaceTrack or new ICE candidates, are
+// no-ops. Codec preferences and ICE restarts are not part of the check, an offer created
+// with OfferOptions.ICERestart is never a no-op.
+func (pc *PeerConnection) IsOfferNoOp() bool {
+	if pc.isClosed.get() || pc.SignalingState() != SignalingStateStable || pc.CurrentRemoteDescription() == nil {
+		return false
+	}
+
+	return !pc.checkNegotiationNeeded()
+}
+
 // OnICECandidate sets an event handler which is invoked when a new ICE
 // candidate is found.
 // ICE candidate gathering only begins when SetLocalDescription or
@@ -444,7 +501,8 @@ func (pc *PeerConnection) OnICECandidate(f func(*ICECandidate)) {
 }
 
 // OnICEGatheringStateChange sets an event handler which is invoked when the
-// ICE candidate gathering state has changed.
+// ICE candidate gathering state has changed. The PeerConnection has a single ICE
+// transport, its state is the same, see ICETransport.OnGatheringStateChange.
 func (pc *PeerConnection) OnICEGatheringStateChange(f func(ICEGatheringState)) {
 	pc.iceGatherer.OnStateChange(
 		func(gathererState ICEGathererState) {
@@ -510,6 +568,54 @@ func (pc *PeerConnection) onConnectionStateChange(cs PeerConnectionState) {
 	}
 }
 
+// OnReady sets an event handler which is called once, the first time media and data can
+// flow on the PeerConnection. It waits for all of:
+//
+//   - the PeerConnectionState to be connected, that is ICE connected and the DTLS handshake
+//     complete,
+//   - the SRTP and SRTCP sessions to be started with the keys of the DTLS handshake, so
+//     tracks can be written and read,
+//   - the SCTP association to be established, if the remote description negotiated data
+//     channels.
+//
+// It doesn't wait for a first packet, tracks are reported by OnTrack and data channels by
+// OnOpen. It isn't called again after a disconnection or an ICE restart, and isn't called
+// at all if the handler is set once the PeerConnection is ready.
+func (pc *PeerConnection) OnReady(f func()) {
+	pc.onReadyHandler.Store(f)
+}
+
+// checkReady calls the OnReady handler if the PeerConnection just became ready. It is
+// called from the operations queue once the transports or the SCTP association started.
+func (pc *PeerConnection) checkReady() {
+	if pc.isReady.Load() || pc.ConnectionState() != PeerConnectionStateConnected {
+		return
+	}
+
+	select {
+	case <-pc.dtlsTransport.srtpReady:
+	default:
+		return
+	}
+
+	remoteDescription := pc.RemoteDescription()
+	if remoteDescription == nil {
+		return
+	}
+	if haveDataChannel(remoteDescription) != nil && pc.sctpTransport.State() != SCTPTransportStateConnected {
+		return
+	}
+
+	if !pc.isReady.CompareAndSwap(false, true) {
+		return
+	}
+
+	pc.log.Info("peer connection is ready")
+	if handler, ok := pc.onReadyHandler.Load().(func()); ok && handler != nil {
+		go handler()
+	}
+}
+
 // SetConfiguration updates the configuration of this PeerConnection object.
 func (pc *PeerConnection) SetConfiguration(configuration Configuration) error { //nolint:gocognit,cyclop
 	// https://www.w3.org/TR/webrtc/#dom-rtcpeerconnection-setconfiguration (step #2)
@@ -590,6 +696,24 @@ func (pc *PeerConnection) GetConfiguration() Configuration {
 	return pc.configuration
 }
 
+// Clone creates a new PeerConnection from the API and the Configuration of pc, for instance
+// to reconnect once pc is closed. It can be called in any state of pc.
+//
+// The new connection shares the Certificates of pc, so that its DTLS fingerprints stay the
+// same, its ICE servers and policies, and the MediaEngine, interceptor Registry and
+// SettingEngine of the API, with the codecs and header extensions registered on it before
+// any negotiation. Everything else starts anew: the transceivers, tracks and data channels,
+// the event handlers, the session descriptions and the negotiated codecs, the ICE
+// credentials and candidates, the interceptors, which are built again from the Registry, and
+// the statistics. Clone fails with ErrCertificateExpired once the certificates expired.
+func (pc *PeerConnection) Clone() (*PeerConnection, error) {
+	configuration := pc.GetConfiguration()
+	configuration.ICEServers = append([]ICEServer{}, configuration.ICEServers...)
+	configuration.Certificates = append([]Certificate{}, configuration.Certificates...)
+
+	return pc.parentAPI.NewPeerConnection(configuration)
+}
+
 func (pc *PeerConnection) getStatsID() string {
 	pc.mu.RLock()
 	defer pc.mu.RUnlock()
@@ -617,6 +741,12 @@ func (pc *PeerConnection) hasLocalDescriptionChanged(desc *SessionDescription) b
 // CreateOffer starts the PeerConnection and generates the localDescription
 // https://w3c.github.io/webrtc-pc/#dom-rtcpeerconnection-createoffer
 //
+// The offer is generated from the current state: media sections keep their mid and order and
+// only list the codecs and header extensions already negotiated, so an offer created during
+// a renegotiation only differs from the current description by the changes made since. If
+// options.SkipIfNoOp is set and there are no such changes, ErrNoOpOffer is returned, see
+// IsOfferNoOp.
+//
 //nolint:gocognit,cyclop
 func (pc *PeerConnection) CreateOffer(options *OfferOptions) (SessionDescription, error) {
 	useIdentity := pc.idpLoginURL != nil
@@ -627,6 +757,10 @@ func (pc *PeerConnection) CreateOffer(options *OfferOptions) (SessionDescription
 		return SessionDescription{}, &rtcerr.InvalidStateError{Err: ErrConnectionClosed}
 	}
 
+	if options != nil && options.SkipIfNoOp && !options.ICERestart && pc.IsOfferNoOp() {
+		return SessionDescription{}, ErrNoOpOffer
+	}
+
 	if options != nil && options.ICERestart {
 		if err := pc.iceTransport.restart(); err != nil {
 			return SessionDescription{}, err
@@ -886,6 +1020,68 @@ func (pc *PeerConnection) CreateAnswer(*AnswerOptions) (SessionDescription, erro
 	return desc, nil
 }
 
+// AnswerReceiveOnly sets offer as the remote description and answers it as a pure media
+// sink, such as a recorder or an ingest endpoint: every media section in which the remote
+// sends is received with a recvonly transceiver, the others are inactive. The answer is set
+// as the local description and returned, OnTrack then fires for each received track.
+//
+// No transceiver has to be added beforehand, those created from the offer use the codecs of
+// the offer that exactly match a codec of the MediaEngine, mime type and fmtp, in the order
+// of the offer. A media section without such a codec is rejected in the answer. The tracks
+// of transceivers that were added beforehand are removed, as with RemoveTrack, and the
+// transceivers of the sections in which the remote doesn't send are stopped. An
+// InvalidModificationError is returned if a transceiver added beforehand can't receive a
+// media section in which the remote sends, such as a sendonly one matched by its mid.
+func (pc *PeerConnection) AnswerReceiveOnly(offer SessionDescription) (SessionDescription, error) {
+	if offer.Type != SDPTypeOffer {
+		return SessionDescription{}, &rtcerr.InvalidStateError{Err: errPeerConnAnswerReceiveOnlyNotOffer}
+	}
+	if err := pc.SetRemoteDescription(offer); err != nil {
+		return SessionDescription{}, err
+	}
+
+	remoteDirections := map[string]RTPTransceiverDirection{}
+	for _, media := range pc.RemoteDescription().parsed.MediaDescriptions {
+		remoteDirections[getMidValue(media)] = getPeerDirection(media)
+	}
+	for _, transceiver := range pc.GetTransceivers() {
+		direction, ok := remoteDirections[transceiver.Mid()]
+		if !ok {
+			continue
+		}
+
+		// Removing the track turns a sendrecv transceiver into a recvonly one, and a sendonly
+		// one into an inactive one
+		if sender := transceiver.Sender(); sender != nil && sender.Track() != nil {
+			if err := pc.RemoveTrack(sender); err != nil {
+				return SessionDescription{}, err
+			}
+		}
+
+		remoteSends := direction == RTPTransceiverDirectionSendrecv || direction == RTPTransceiverDirectionSendonly
+		switch current := transceiver.Direction(); {
+		case remoteSends && current != RTPTransceiverDirectionRecvonly:
+			return SessionDescription{}, &rtcerr.InvalidModificationError{
+				Err: fmt.Errorf("%w: %s transceiver for mid %s", errPeerConnAnswerReceiveOnlyDirection, current, transceiver.Mid()),
+			}
+		case !remoteSends && current != RTPTransceiverDirectionInactive:
+			if err := transceiver.Stop(); err != nil {
+				return SessionDescription{}, &rtcerr.InvalidStateError{Err: err}
+			}
+		}
+	}
+
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		return SessionDescription{}, err
+	}
+	if err = pc.SetLocalDescription(answer); err != nil {
+		return SessionDescription{}, err
+	}
+
+	return answer, nil
+}
+
 // 4.4.1.6 Set the SessionDescription
 //
 //nolint:gocognit,cyclop
@@ -1044,6 +1240,7 @@ func (pc *PeerConnection) SetLocalDescription(desc SessionDescription) error {
 	remoteDesc := pc.RemoteDescription()
 	if weAnswer && remoteDesc != nil {
 		_ = setRTPTransceiverCurrentDirection(&desc, currentTransceivers, false)
+		setRTPTransceiverSimulcastRIDs(remoteDesc, currentTransceivers)
 		if err := pc.startRTPSenders(currentTransceivers); err != nil {
 			return err
 		}
@@ -1090,6 +1287,7 @@ func (pc *PeerConnection) SetRemoteDescription(desc SessionDescription) error {
 	if _, err := desc.Unmarshal(); err != nil {
 		return err
 	}
+	pc.checkRemoteRTCP(desc.parsed)
 	if err := pc.setDescription(&desc, stateChangeOpSetRemote); err != nil {
 		return err
 	}
@@ -1103,9 +1301,28 @@ func (pc *PeerConnection) SetRemoteDescription(desc SessionDescription) error {
 		sender.configureRTXAndFEC()
 	}
 
+	// Apply codec parameters the remote changed in a renegotiation
+	if isRenegotiation {
+		if desc.Type == SDPTypeOffer {
+			for _, media := range desc.parsed.MediaDescriptions {
+				for _, transceiver := range pc.GetTransceivers() {
+					if mid := getMidValue(media); mid != "" && transceiver.Mid() == mid {
+						transceiver.updateFmtpFromMediaSection(media)
+					}
+				}
+			}
+		}
+
+		for _, receiver := range pc.GetReceivers() {
+			for _, track := range receiver.Tracks() {
+				track.updateCodecFmtp()
+			}
+		}
+	}
+
 	var transceiver *RTPTransceiver
 	localTransceivers := append([]*RTPTransceiver{}, pc.GetTransceivers()...)
-	detectedPlanB := descriptionIsPlanB(pc.RemoteDescription(), pc.log)
+	detectedPlanB := descriptionIsPlanB(pc.RemoteDescription(), pc.log, pc.api.settingEngine.simulcastSSRCGroups)
 	if pc.configuration.SDPSemantics != SDPSemanticsUnifiedPlan {
 		detectedPlanB = descriptionPossiblyPlanB(pc.RemoteDescription())
 	}
@@ -1203,6 +1420,9 @@ func (pc *PeerConnection) SetRemoteDescription(desc SessionDescription) error {
 	if err != nil {
 		return err
 	}
+	if err = checkNonBundledTransports(desc.parsed, iceDetails.Ufrag); err != nil {
+		return err
+	}
 
 	if isRenegotiation && pc.iceTransport.haveRemoteCredentialsChange(iceDetails.Ufrag, iceDetails.Password) {
 		// An ICE Restart only happens implicitly for a SetRemoteDescription of type offer
@@ -1215,19 +1435,23 @@ func (pc *PeerConnection) SetRemoteDescription(desc SessionDescription) error {
 		if err = pc.iceTransport.setRemoteCredentials(iceDetails.Ufrag, iceDetails.Password); err != nil {
 			return err
 		}
+		pc.resetRemoteICECandidates()
 	}
 
 	for i := range iceDetails.Candidates {
 		if err = pc.iceTransport.AddRemoteCandidate(&iceDetails.Candidates[i]); err != nil {
 			return err
 		}
+		pc.addRemoteICECandidate(iceDetails.Candidates[i])
 	}
+	pc.addEarlyICECandidates()
 
 	currentTransceivers := append([]*RTPTransceiver{}, pc.GetTransceivers()...)
 
 	if isRenegotiation {
 		if weOffer {
 			_ = setRTPTransceiverCurrentDirection(&desc, currentTransceivers, true)
+			setRTPTransceiverSimulcastRIDs(&desc, currentTransceivers)
 			if err = pc.startRTPSenders(currentTransceivers); err != nil {
 				return err
 			}
@@ -1255,11 +1479,16 @@ func (pc *PeerConnection) SetRemoteDescription(desc SessionDescription) error {
 		(remoteIsLite && !pc.api.settingEngine.candidates.ICELite) {
 		iceRole = ICERoleControlling
 	}
+	pc.iceTransport.setDescriptionRole(iceRole)
+	if forcedRole := pc.api.settingEngine.iceRole; forcedRole != ICERoleUnknown {
+		iceRole = forcedRole
+	}
 
 	// Start the networking in a new routine since it will block until
 	// the connection is actually established.
 	if weOffer {
 		_ = setRTPTransceiverCurrentDirection(&desc, currentTransceivers, true)
+		setRTPTransceiverSimulcastRIDs(&desc, currentTransceivers)
 		if err := pc.startRTPSenders(currentTransceivers); err != nil {
 			return err
 		}
@@ -1314,7 +1543,8 @@ func (pc *PeerConnection) startReceiver(incoming trackDetails, receiver *RTPRece
 
 			return
 		}
-		go func(track *TrackRemote) {
+		track := track
+		pc.goTracked(func() {
 			b := make([]byte, pc.api.settingEngine.getReceiveMTU())
 			n, _, err := track.peek(b)
 			if err != nil {
@@ -1330,7 +1560,7 @@ func (pc *PeerConnection) startReceiver(incoming trackDetails, receiver *RTPRece
 			}
 
 			pc.onTrack(track, receiver)
-		}(track)
+		})
 	}
 }
 
@@ -1421,7 +1651,7 @@ func (pc *PeerConnection) configureRTPReceivers(
 	remoteDesc *SessionDescription,
 	currentTransceivers []*RTPTransceiver,
 ) {
-	incomingTracks := trackDetailsFromSDP(pc.log, remoteDesc.parsed)
+	incomingTracks := trackDetailsFromSDP(pc.log, remoteDesc.parsed, pc.api.settingEngine.simulcastSSRCGroups)
 
 	if isRenegotiation { //nolint:nestif
 		for _, transceiver := range currentTransceivers {
@@ -1508,7 +1738,7 @@ func (pc *PeerConnection) configureRTPReceivers(
 
 // startRTPReceivers opens knows inbound SRTP streams from the RemoteDescription.
 func (pc *PeerConnection) startRTPReceivers(remoteDesc *SessionDescription, currentTransceivers []*RTPTransceiver) {
-	incomingTracks := trackDetailsFromSDP(pc.log, remoteDesc.parsed)
+	incomingTracks := trackDetailsFromSDP(pc.log, remoteDesc.parsed, pc.api.settingEngine.simulcastSSRCGroups)
 	if len(incomingTracks) == 0 {
 		return
 	}
@@ -1582,6 +1812,59 @@ func (pc *PeerConnection) handleUndeclaredSSRC(
 	ssrc SSRC,
 	mediaSection *sdp.MediaDescription,
 ) (handled bool, err error) {
+	incoming, ok, err := undeclaredTrackDetails(ssrc, mediaSection)
+	if !ok || err != nil {
+		return false, err
+	}
+
+	t, err := pc.AddTransceiverFromKind(incoming.kind, RTPTransceiverInit{
+		Direction: RTPTransceiverDirectionSendrecv,
+	})
+	if err != nil {
+		// nolint
+		return false, fmt.Errorf("%w: %d: %s", errPeerConnRemoteSSRCAddTransceiver, ssrc, err)
+	}
+
+	pc.configureReceiver(incoming, t.Receiver())
+	pc.startReceiver(incoming, t.Receiver())
+
+	return true, nil
+}
+
+// handleUndeclaredMid starts the receiver of the transceiver with the given mid for an
+// undeclared SSRC, it is used when the media section has neither a=ssrc nor a=rid and
+// the stream can only be matched by its MID extension, e.g. when the remote doesn't bundle.
+func (pc *PeerConnection) handleUndeclaredMid(ssrc SSRC, mid string, remoteDescription *SessionDescription) error {
+	for _, mediaSection := range remoteDescription.parsed.MediaDescriptions {
+		if getMidValue(mediaSection) != mid {
+			continue
+		}
+
+		incoming, ok, err := undeclaredTrackDetails(ssrc, mediaSection)
+		if !ok || err != nil {
+			return err
+		}
+		incoming.mid = mid
+
+		for _, t := range pc.GetTransceivers() {
+			receiver := t.Receiver()
+			if t.Mid() != mid || receiver == nil {
+				continue
+			}
+
+			pc.configureReceiver(incoming, receiver)
+			pc.startReceiver(incoming, receiver)
+
+			return nil
+		}
+	}
+
+	return errPeerConnSimulcastIncomingSSRCFailed
+}
+
+// undeclaredTrackDetails returns the trackDetails of an undeclared SSRC received for
+// mediaSection, ok is false if the media section uses RIDs.
+func undeclaredTrackDetails(ssrc SSRC, mediaSection *sdp.MediaDescription) (incoming trackDetails, ok bool, err error) {
 	streamID := ""
 	id := ""
 	hasRidAttribute := false
@@ -1602,12 +1885,12 @@ func (pc *PeerConnection) handleUndeclaredSSRC(
 	}
 
 	if hasRidAttribute {
-		return false, nil
+		return incoming, false, nil
 	} else if hasSSRCAttribute {
-		return false, errMediaSectionHasExplictSSRCAttribute
+		return incoming, false, errMediaSectionHasExplictSSRCAttribute
 	}
 
-	incoming := trackDetails{
+	incoming = trackDetails{
 		ssrcs:    []SSRC{ssrc},
 		kind:     RTPCodecTypeVideo,
 		streamID: streamID,
@@ -1617,18 +1900,18 @@ func (pc *PeerConnection) handleUndeclaredSSRC(
 		incoming.kind = RTPCodecTypeAudio
 	}
 
-	t, err := pc.AddTransceiverFromKind(incoming.kind, RTPTransceiverInit{
-		Direction: RTPTransceiverDirectionSendrecv,
-	})
-	if err != nil {
-		// nolint
-		return false, fmt.Errorf("%w: %d: %s", errPeerConnRemoteSSRCAddTransceiver, ssrc, err)
-	}
+	return incoming, true, nil
+}
 
-	pc.configureReceiver(incoming, t.Receiver())
-	pc.startReceiver(incoming, t.Receiver())
+// remoteMidHasRids reports whether the remote media section with the given mid uses RIDs.
+func remoteMidHasRids(remoteDescription *SessionDescription, mid string) bool {
+	for _, mediaSection := range remoteDescription.parsed.MediaDescriptions {
+		if getMidValue(mediaSection) == mid {
+			return len(getRids(mediaSection)) != 0
+		}
+	}
 
-	return true, nil
+	return false
 }
 
 // For legacy clients that didn't support urn:ietf:params:rtp-hdrext:sdes:rtp-stream-id
@@ -1699,7 +1982,7 @@ func (pc *PeerConnection) handleIncomingSSRC(rtpStream io.Reader, ssrc SSRC) err
 	}
 
 	// If a SSRC already exists in the RemoteDescription don't perform heuristics upon it
-	for _, track := range trackDetailsFromSDP(pc.log, remoteDescription.parsed) {
+	for _, track := range trackDetailsFromSDP(pc.log, remoteDescription.parsed, pc.api.settingEngine.simulcastSSRCGroups) {
 		if track.repairSsrc != nil && ssrc == *track.repairSsrc {
 			return nil
 		}
@@ -1718,6 +2001,9 @@ func (pc *PeerConnection) handleIncomingSSRC(rtpStream io.Reader, ssrc SSRC) err
 	// while this can be incorrect, this is done to maintain compatibility with older behavior.
 	if len(remoteDescription.parsed.MediaDescriptions) == 1 {
 		mediaSection := remoteDescription.parsed.MediaDescriptions[0]
+		if receiver, track := pc.ssrcChangeTrack(getMidValue(mediaSection)); track != nil {
+			return pc.rebindSSRC(ssrc, receiver, track)
+		}
 		if handled, err := pc.handleUndeclaredSSRC(ssrc, mediaSection); handled || err != nil {
 			return err
 		}
@@ -1756,12 +2042,12 @@ func (pc *PeerConnection) handleIncomingSSRC(rtpStream io.Reader, ssrc SSRC) err
 		return errPeerConnSimulcastMidRTPExtensionRequired
 	}
 
+	// The stream id extension is only required to demultiplex simulcast, media sections
+	// without RIDs are matched by MID alone.
 	streamIDExtensionID, audioSupported, videoSupported := pc.api.mediaEngine.getHeaderExtensionID(
 		RTPHeaderExtensionCapability{sdp.SDESRTPStreamIDURI},
 	)
-	if !audioSupported && !videoSupported {
-		return errPeerConnSimulcastStreamIDRTPExtensionRequired
-	}
+	streamIDSupported := audioSupported || videoSupported
 
 	repairStreamIDExtensionID, _, _ := pc.api.mediaEngine.getHeaderExtensionID(
 		RTPHeaderExtensionCapability{sdp.SDESRepairRTPStreamIDURI},
@@ -1784,7 +2070,14 @@ func (pc *PeerConnection) handleIncomingSSRC(rtpStream io.Reader, ssrc SSRC) err
 	var mid, rid, rsid string
 	var paddingOnly bool
 	for readCount := 0; readCount <= simulcastProbeCount; readCount++ {
-		if mid == "" || (rid == "" && rsid == "") {
+		hasRids := mid != "" && remoteMidHasRids(remoteDescription, mid)
+		if hasRids && !streamIDSupported {
+			pc.api.interceptor.UnbindRemoteStream(streamInfo)
+
+			return errPeerConnSimulcastStreamIDRTPExtensionRequired
+		}
+
+		if mid == "" || (rid == "" && rsid == "" && hasRids) {
 			// skip padding only packets for probing
 			if paddingOnly {
 				readCount--
@@ -1809,6 +2102,16 @@ func (pc *PeerConnection) handleIncomingSSRC(rtpStream io.Reader, ssrc SSRC) err
 			continue
 		}
 
+		if rid == "" && rsid == "" {
+			if receiver, track := pc.ssrcChangeTrack(mid); track != nil && !isRepairCodec(params.Codecs[0]) {
+				return receiver.changeSSRC(track, ssrc, streamInfo, readStream, interceptor, rtcpReadStream, rtcpInterceptor)
+			}
+
+			pc.api.interceptor.UnbindRemoteStream(streamInfo)
+
+			return pc.handleUndeclaredMid(ssrc, mid, remoteDescription)
+		}
+
 		for _, t := range pc.GetTransceivers() {
 			receiver := t.Receiver()
 			if t.Mid() != mid || receiver == nil {
@@ -1847,8 +2150,8 @@ func (pc *PeerConnection) handleIncomingSSRC(rtpStream io.Reader, ssrc SSRC) err
 
 // undeclaredMediaProcessor handles RTP/RTCP packets that don't match any a:ssrc lines.
 func (pc *PeerConnection) undeclaredMediaProcessor() {
-	go pc.undeclaredRTPMediaProcessor()
-	go pc.undeclaredRTCPMediaProcessor()
+	pc.goTracked(pc.undeclaredRTPMediaProcessor)
+	pc.goTracked(pc.undeclaredRTCPMediaProcessor)
 }
 
 func (pc *PeerConnection) undeclaredRTPMediaProcessor() { //nolint:cyclop
@@ -1897,7 +2200,7 @@ func (pc *PeerConnection) undeclaredRTPMediaProcessor() { //nolint:cyclop
 		pc.dtlsTransport.storeSimulcastStream(srtpReadStream, srtcpReadStream)
 
 		if ssrc == 0 {
-			go pc.handleNonMediaBandwidthProbe()
+			pc.goTracked(pc.handleNonMediaBandwidthProbe)
 
 			continue
 		}
@@ -1909,12 +2212,13 @@ func (pc *PeerConnection) undeclaredRTPMediaProcessor() { //nolint:cyclop
 			continue
 		}
 
-		go func(rtpStream io.Reader, ssrc SSRC) {
-			if err := pc.handleIncomingSSRC(rtpStream, ssrc); err != nil {
-				pc.log.Errorf(incomingUnhandledRTPSsrc, ssrc, err)
+		incomingSSRC := SSRC(ssrc)
+		pc.goTracked(func() {
+			if err := pc.handleIncomingSSRC(srtpReadStream, incomingSSRC); err != nil {
+				pc.log.Errorf(incomingUnhandledRTPSsrc, incomingSSRC, err)
 			}
 			atomic.AddUint64(&simulcastRoutineCount, ^uint64(0))
-		}(srtpReadStream, SSRC(ssrc))
+		})
 	}
 }
 
@@ -1964,7 +2268,14 @@ func (pc *PeerConnection) RemoteDescription() *SessionDescription {
 func (pc *PeerConnection) AddICECandidate(candidate ICECandidateInit) error {
 	remoteDesc := pc.RemoteDescription()
 	if remoteDesc == nil {
-		return &rtcerr.InvalidStateError{Err: ErrNoRemoteDescription}
+		if buffered, err := pc.bufferEarlyICECandidate(candidate); buffered || err != nil {
+			return err
+		}
+
+		// The remote description may have been set while buffering
+		if remoteDesc = pc.RemoteDescription(); remoteDesc == nil {
+			return &rtcerr.InvalidStateError{Err: ErrNoRemoteDescription}
+		}
 	}
 
 	candidateValue := strings.TrimPrefix(candidate.Candidate, "candidate:")
@@ -2003,7 +2314,19 @@ func (pc *PeerConnection) AddICECandidate(candidate ICECandidateInit) error {
 		return err
 	}
 
-	return pc.iceTransport.AddRemoteCandidate(&c)
+	if err = pc.iceTransport.AddRemoteCandidate(&c); err != nil {
+		return err
+	}
+
+	if candidate.SDPMid != nil {
+		c.SDPMid = *candidate.SDPMid
+	}
+	if candidate.SDPMLineIndex != nil {
+		c.SDPMLineIndex = *candidate.SDPMLineIndex
+	}
+	pc.addRemoteICECandidate(c)
+
+	return nil
 }
 
 // Return true if the sdp contains a specific ufrag.
@@ -2073,6 +2396,11 @@ func (pc *PeerConnection) GetTransceivers() []*RTPTransceiver {
 //
 //nolint:cyclop
 func (pc *PeerConnection) AddTrack(track TrackLocal) (*RTPSender, error) {
+	return pc.addTrack(track, "")
+}
+
+// addTrack adds track, announced in the stream streamID instead of its StreamID if set.
+func (pc *PeerConnection) addTrack(track TrackLocal, streamID string) (*RTPSender, error) {
 	if pc.isClosed.get() {
 		return nil, &rtcerr.InvalidStateError{Err: ErrConnectionClosed}
 	}
@@ -2089,6 +2417,7 @@ func (pc *PeerConnection) AddTrack(track TrackLocal) (*RTPSender, error) {
 			!(currentDirection == RTPTransceiverDirectionSendrecv || currentDirection == RTPTransceiverDirectionSendonly) {
 			sender, err := pc.api.NewRTPSender(track, pc.dtlsTransport)
 			if err == nil {
+				sender.streamID = streamID
 				err = transceiver.SetSender(sender, track)
 				if err != nil {
 					_ = sender.Stop()
@@ -2108,6 +2437,7 @@ func (pc *PeerConnection) AddTrack(track TrackLocal) (*RTPSender, error) {
 	if err != nil {
 		return nil, err
 	}
+	transceiver.Sender().streamID = streamID
 	pc.addRTPTransceiver(transceiver)
 
 	return transceiver.Sender(), nil
@@ -2199,7 +2529,9 @@ func (pc *PeerConnection) AddTransceiverFromKind(
 		if len(codecs) == 0 {
 			return nil, ErrNoCodecsAvailable
 		}
-		track, err := NewTrackLocalStaticSample(codecs[0].RTPCodecCapability, util.MathRandAlpha(16), util.MathRandAlpha(16))
+		track, err := NewTrackLocalStaticSample(
+			codecs[0].RTPCodecCapability, pc.api.settingEngine.random.alpha(16), pc.api.settingEngine.random.alpha(16),
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -2353,6 +2685,29 @@ func (pc *PeerConnection) writeRTCP(pkts []rtcp.Packet, _ interceptor.Attributes
 	return pc.dtlsTransport.WriteRTCP(pkts)
 }
 
+// PauseRTCP stops sending RTCP while media keeps flowing: receiver and sender reports, NACKs,
+// keyframe requests, congestion control feedback and the packets written with WriteRTCP are
+// discarded until ResumeRTCP is called. The interceptors keep running, so the reports sent
+// after resuming cover the paused period.
+//
+// It is meant for measurements and peers that don't handle RTCP. The remote peer loses its
+// round trip time and loss statistics, can't recover lost packets or keyframes, and its
+// congestion control can lower its bitrate. Some endpoints close a connection once they
+// don't receive RTCP for a while, RFC 3550 section 6.3.5 suggests five report intervals.
+func (pc *PeerConnection) PauseRTCP() {
+	pc.dtlsTransport.rtcpPaused.set(true)
+}
+
+// ResumeRTCP sends RTCP again after PauseRTCP.
+func (pc *PeerConnection) ResumeRTCP() {
+	pc.dtlsTransport.rtcpPaused.set(false)
+}
+
+// RTCPPaused reports whether RTCP is paused, see PauseRTCP.
+func (pc *PeerConnection) RTCPPaused() bool {
+	return pc.dtlsTransport.rtcpPaused.get()
+}
+
 // Close ends the PeerConnection.
 func (pc *PeerConnection) Close() error {
 	return pc.close(false /* shouldGracefullyClose */)
@@ -2459,6 +2814,7 @@ func (pc *PeerConnection) close(shouldGracefullyClose bool) error { //nolint:cyc
 	pc.sctpTransport.lock.Lock()
 	for _, d := range pc.sctpTransport.dataChannels {
 		d.setReadyState(DataChannelStateClosed)
+		d.closeConn()
 	}
 	pc.sctpTransport.lock.Unlock()
 
@@ -2491,6 +2847,7 @@ func (pc *PeerConnection) close(shouldGracefullyClose bool) error { //nolint:cyc
 // and fires onNegotiationNeeded;
 // caller of this method should hold `pc.mu` lock.
 func (pc *PeerConnection) addRTPTransceiver(t *RTPTransceiver) {
+	t.setNegotiationNeededHandler(pc.onNegotiationNeeded)
 	pc.rtpTransceivers = append(pc.rtpTransceivers, t)
 	pc.onNegotiationNeeded()
 }
@@ -2561,14 +2918,7 @@ func (pc *PeerConnection) ICEGatheringState() ICEGatheringState {
 		return ICEGatheringStateNew
 	}
 
-	switch pc.iceGatherer.State() {
-	case ICEGathererStateNew:
-		return ICEGatheringStateNew
-	case ICEGathererStateGathering:
-		return ICEGatheringStateGathering
-	default:
-		return ICEGatheringStateComplete
-	}
+	return newICEGatheringStateFromGatherer(pc.iceGatherer.State())
 }
 
 // ConnectionState attribute returns the connection state of the
@@ -2594,10 +2944,14 @@ func (pc *PeerConnection) GetStats() StatsReport {
 
 	pc.mu.Lock()
 	if pc.iceGatherer != nil {
-		pc.iceGatherer.collectStats(statsCollector)
+		var role ICERole
+		if pc.iceTransport != nil {
+			role = pc.iceTransport.Role()
+		}
+		pc.iceGatherer.collectStats(statsCollector, role)
 	}
 	if pc.iceTransport != nil {
-		pc.iceTransport.collectStats(statsCollector)
+		pc.iceTransport.collectStats(statsCollector, pc.dtlsTransport)
 	}
 
 	pc.sctpTransport.lock.Lock()
@@ -2617,6 +2971,15 @@ func (pc *PeerConnection) GetStats() StatsReport {
 	}
 	pc.sctpTransport.collectStats(statsCollector)
 
+	for _, transceiver := range pc.rtpTransceivers {
+		if sender := transceiver.Sender(); sender != nil {
+			sender.collectStats(statsCollector)
+		}
+		if receiver := transceiver.Receiver(); receiver != nil {
+			receiver.collectStats(statsCollector)
+		}
+	}
+
 	stats := PeerConnectionStats{
 		Timestamp:             statsTimestampNow(),
 		Type:                  StatsTypePeerConnection,
@@ -2688,6 +3051,8 @@ func (pc *PeerConnection) startTransports(
 
 		return
 	}
+
+	pc.checkReady()
 }
 
 // nolint: gocognit
@@ -2704,6 +3069,8 @@ func (pc *PeerConnection) startRTP(
 	if d := haveDataChannel(remoteDesc); d != nil {
 		pc.startSCTP(getMaxMessageSize(d))
 	}
+
+	pc.checkReady()
 }
 
 // generateUnmatchedSDP generates an SDP that doesn't take remote state into account
@@ -2831,7 +3198,7 @@ func (pc *PeerConnection) generateMatchedSDP(
 	isExtmapAllowMixed := isExtMapAllowMixedSet(remoteDescription.parsed)
 	localTransceivers := append([]*RTPTransceiver{}, transceivers...)
 
-	detectedPlanB := descriptionIsPlanB(remoteDescription, pc.log)
+	detectedPlanB := descriptionIsPlanB(remoteDescription, pc.log, pc.api.settingEngine.simulcastSSRCGroups)
 	if pc.configuration.SDPSemantics != SDPSemanticsUnifiedPlan {
 		detectedPlanB = descriptionPossiblyPlanB(remoteDescription)
 	}
@@ -2845,7 +3212,7 @@ func (pc *PeerConnection) generateMatchedSDP(
 		}
 
 		if media.MediaName.Media == mediaSectionApplication {
-			mediaSections = append(mediaSections, mediaSection{id: midValue, data: true})
+			mediaSections = append(mediaSections, mediaSection{id: midValue, data: true, bundleOnly: isBundleOnly(media)})
 			alreadyHaveApplicationMediaSection = true
 
 			continue
@@ -2908,7 +3275,13 @@ func (pc *PeerConnection) generateMatchedSDP(
 			extensions, _ := rtpExtensionsFromMediaDescription(media)
 			mediaSections = append(
 				mediaSections,
-				mediaSection{id: midValue, transceivers: mediaTransceivers, matchExtensions: extensions, rids: getRids(media)},
+				mediaSection{
+					id:              midValue,
+					transceivers:    mediaTransceivers,
+					matchExtensions: extensions,
+					rids:            getRids(media),
+					bundleOnly:      isBundleOnly(media),
+				},
 			)
 		}
 	}
@@ -19,6 +19,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/pion/logging"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
+	"github.com/pion/sdp/v3"
 	"github.com/pion/transport/v3/test"
 	"github.com/pion/transport/v3/vnet"
 	"github.com/pion/webrtc/v4/internal/util"
@@ -1843,6 +1845,164 @@ func Test_WriteRTCP_Disconnected(t *testing.T) {
 	assert.NoError(t, peerConnection.Close())
 }
 
+func TestPeerConnection_PauseRTCP(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+	sender, err := pcOffer.AddTrack(track)
+	assert.NoError(t, err)
+
+	connected := untilConnectionState(PeerConnectionStateConnected, pcOffer, pcAnswer)
+	assert.NoError(t, signalPair(pcOffer, pcAnswer))
+	connected.Wait()
+
+	ssrc := uint32(sender.GetParameters().Encodings[0].SSRC)
+	nack := func(sequenceNumber uint16) []rtcp.Packet {
+		return []rtcp.Packet{&rtcp.TransportLayerNack{
+			MediaSSRC: ssrc,
+			Nacks:     []rtcp.NackPair{{PacketID: sequenceNumber}},
+		}}
+	}
+
+	assert.False(t, pcAnswer.RTCPPaused())
+	pcAnswer.PauseRTCP()
+	assert.True(t, pcAnswer.RTCPPaused())
+	assert.NoError(t, pcAnswer.WriteRTCP(nack(1)))
+
+	pcAnswer.ResumeRTCP()
+	assert.False(t, pcAnswer.RTCPPaused())
+	assert.NoError(t, pcAnswer.WriteRTCP(nack(2)))
+
+	// Only the NACK written after resuming is received
+	for received := false; !received; {
+		pkts, _, readErr := sender.ReadRTCP()
+		if !assert.NoError(t, readErr) {
+			break
+		}
+		for _, pkt := range pkts {
+			if n, ok := pkt.(*rtcp.TransportLayerNack); ok {
+				assert.Equal(t, uint16(2), n.Nacks[0].PacketID)
+				received = true
+			}
+		}
+	}
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
+
+func TestPeerConnection_IsOfferNoOp(t *testing.T) {
+	pcOffer, pcAnswer, err := newPair()
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+	sender, err := pcOffer.AddTrack(track)
+	assert.NoError(t, err)
+
+	assert.False(t, pcOffer.IsOfferNoOp())
+	assert.NoError(t, signalPair(pcOffer, pcAnswer))
+	assert.True(t, pcOffer.IsOfferNoOp())
+	assert.True(t, pcAnswer.IsOfferNoOp())
+
+	_, err = pcOffer.CreateOffer(&OfferOptions{SkipIfNoOp: true})
+	assert.ErrorIs(t, err, ErrNoOpOffer)
+	_, err = pcOffer.CreateOffer(&OfferOptions{SkipIfNoOp: true, ICERestart: true})
+	assert.NoError(t, err)
+
+	// Replacing the track doesn't need a renegotiation
+	otherTrack, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+	assert.NoError(t, sender.ReplaceTrack(otherTrack))
+	assert.True(t, pcOffer.IsOfferNoOp())
+
+	assert.NoError(t, pcOffer.RemoveTrack(sender))
+	assert.False(t, pcOffer.IsOfferNoOp())
+	offer, err := pcOffer.CreateOffer(&OfferOptions{SkipIfNoOp: true})
+	assert.NoError(t, err)
+	assert.NoError(t, pcOffer.SetLocalDescription(offer))
+	assert.False(t, pcOffer.IsOfferNoOp())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
+
+func TestPeerConnection_AnswerReceiveOnly(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	assert.NoError(t, err)
+
+	video, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+	_, err = pcOffer.AddTrack(video)
+	assert.NoError(t, err)
+	audio, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeOpus}, "audio", "pion")
+	assert.NoError(t, err)
+	_, err = pcOffer.AddTrack(audio)
+	assert.NoError(t, err)
+	_, err = pcOffer.AddTransceiverFromKind(RTPCodecTypeAudio, RTPTransceiverInit{
+		Direction: RTPTransceiverDirectionRecvonly,
+	})
+	assert.NoError(t, err)
+
+	var onTrackCount atomic.Int32
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	pcAnswer.OnTrack(func(*TrackRemote, *RTPReceiver) {
+		if onTrackCount.Add(1) == 2 {
+			onTrackFiredFunc()
+		}
+	})
+
+	_, err = pcAnswer.AnswerReceiveOnly(SessionDescription{Type: SDPTypeAnswer})
+	assert.ErrorIs(t, err, errPeerConnAnswerReceiveOnlyNotOffer)
+	var invalidStateErr *rtcerr.InvalidStateError
+	assert.ErrorAs(t, err, &invalidStateErr)
+
+	// The track of a transceiver added beforehand isn't sent
+	answerTrack, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "answer")
+	assert.NoError(t, err)
+	answerSender, err := pcAnswer.AddTrack(answerTrack)
+	assert.NoError(t, err)
+
+	offer, err := pcOffer.CreateOffer(nil)
+	assert.NoError(t, err)
+	offerGatheringComplete := GatheringCompletePromise(pcOffer)
+	assert.NoError(t, pcOffer.SetLocalDescription(offer))
+	<-offerGatheringComplete
+
+	answerGatheringComplete := GatheringCompletePromise(pcAnswer)
+	answer, err := pcAnswer.AnswerReceiveOnly(*pcOffer.LocalDescription())
+	assert.NoError(t, err)
+	assert.Equal(t, SDPTypeAnswer, answer.Type)
+	<-answerGatheringComplete
+	assert.NoError(t, pcOffer.SetRemoteDescription(*pcAnswer.LocalDescription()))
+
+	sendVideoUntilDone(t, onTrackFired.Done(), []*TrackLocalStaticSample{video, audio})
+
+	directions := []RTPTransceiverDirection{}
+	for _, transceiver := range pcAnswer.GetTransceivers() {
+		directions = append(directions, transceiver.Direction())
+	}
+	assert.Equal(t, []RTPTransceiverDirection{
+		RTPTransceiverDirectionRecvonly, RTPTransceiverDirectionRecvonly, RTPTransceiverDirectionInactive,
+	}, directions)
+	assert.Equal(t, int32(2), onTrackCount.Load())
+	assert.Nil(t, answerSender.Track())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
+
 func Test_IPv6(t *testing.T) { //nolint: cyclop
 	interfaces, err := net.Interfaces()
 	if err != nil {
@@ -2005,3 +2165,213 @@ func TestAddICECandidate__DroppingOldGenerationCandidates(t *testing.T) {
 
 	closePairNow(t, pc, remotePC)
 }
+
+// bundleOnlyOffer turns the second media section of offer into a bundle-only one, without
+// a transport of its own, as browsers offer with the max-bundle policy.
+func bundleOnlyOffer(t *testing.T, offer SessionDescription, bundled bool) SessionDescription {
+	t.Helper()
+
+	parsed := &sdp.SessionDescription{}
+	assert.NoError(t, parsed.UnmarshalString(offer.SDP))
+
+	media := parsed.MediaDescriptions[1]
+	media.MediaName.Port = sdp.RangedPort{Value: 0}
+	attributes := []sdp.Attribute{}
+	for _, attr := range media.Attributes {
+		switch attr.Key {
+		case "ice-ufrag", "ice-pwd", "ice-options", "fingerprint", "setup", "candidate", "end-of-candidates":
+		default:
+			attributes = append(attributes, attr)
+		}
+	}
+	media.Attributes = append(attributes, sdp.Attribute{Key: "bundle-only"})
+
+	if !bundled {
+		attributes = []sdp.Attribute{}
+		for _, attr := range parsed.Attributes {
+			if attr.Key != sdp.AttrKeyGroup {
+				attributes = append(attributes, attr)
+			}
+		}
+		parsed.Attributes = attributes
+	}
+
+	raw, err := parsed.Marshal()
+	assert.NoError(t, err)
+
+	return SessionDescription{Type: SDPTypeOffer, SDP: string(raw)}
+}
+
+func TestPeerConnection_BundleOnlyOffer(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	t.Run("Bundled", func(t *testing.T) {
+		pcOffer, pcAnswer, err := newPair()
+		assert.NoError(t, err)
+
+		_, err = pcOffer.AddTransceiverFromKind(RTPCodecTypeAudio)
+		assert.NoError(t, err)
+		video, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+		assert.NoError(t, err)
+		_, err = pcOffer.AddTrack(video)
+		assert.NoError(t, err)
+
+		onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+		pcAnswer.OnTrack(func(*TrackRemote, *RTPReceiver) {
+			onTrackFiredFunc()
+		})
+
+		offer, err := pcOffer.CreateOffer(nil)
+		assert.NoError(t, err)
+		offerGatheringComplete := GatheringCompletePromise(pcOffer)
+		assert.NoError(t, pcOffer.SetLocalDescription(offer))
+		<-offerGatheringComplete
+
+		assert.NoError(t, pcAnswer.SetRemoteDescription(bundleOnlyOffer(t, *pcOffer.LocalDescription(), true)))
+		answer, err := pcAnswer.CreateAnswer(nil)
+		assert.NoError(t, err)
+		answerGatheringComplete := GatheringCompletePromise(pcAnswer)
+		assert.NoError(t, pcAnswer.SetLocalDescription(answer))
+		<-answerGatheringComplete
+
+		// The bundle-only media section is accepted within the BUNDLE group
+		parsed := pcAnswer.LocalDescription().parsed
+		group, _ := parsed.Attribute(sdp.AttrKeyGroup)
+		assert.Equal(t, "BUNDLE 0 1", group)
+		assert.NotZero(t, parsed.MediaDescriptions[1].MediaName.Port.Value)
+		assert.Equal(t, RTPTransceiverDirectionRecvonly, pcAnswer.GetTransceivers()[1].Direction())
+
+		assert.NoError(t, pcOffer.SetRemoteDescription(*pcAnswer.LocalDescription()))
+
+		done := make(chan struct{})
+		go func() {
+			<-onTrackFired.Done()
+			close(done)
+		}()
+		sendVideoUntilDone(t, done, []*TrackLocalStaticSample{video})
+
+		closePairNow(t, pcOffer, pcAnswer)
+	})
+
+	t.Run("Not bundled", func(t *testing.T) {
+		pcOffer, pcAnswer, err := newPair()
+		assert.NoError(t, err)
+
+		_, err = pcOffer.AddTransceiverFromKind(RTPCodecTypeAudio)
+		assert.NoError(t, err)
+		_, err = pcOffer.AddTransceiverFromKind(RTPCodecTypeVideo)
+		assert.NoError(t, err)
+
+		offer, err := pcOffer.CreateOffer(nil)
+		assert.NoError(t, err)
+		assert.NoError(t, pcAnswer.SetRemoteDescription(bundleOnlyOffer(t, offer, false)))
+		answer, err := pcAnswer.CreateAnswer(nil)
+		assert.NoError(t, err)
+
+		// Without a BUNDLE group to share, the bundle-only media section is rejected
+		parsed, err := answer.Unmarshal()
+		assert.NoError(t, err)
+		assert.NotZero(t, parsed.MediaDescriptions[0].MediaName.Port.Value)
+		assert.Zero(t, parsed.MediaDescriptions[1].MediaName.Port.Value)
+
+		closePairNow(t, pcOffer, pcAnswer)
+	})
+}
+
+func TestPeerConnection_Clone(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	settingEngine := SettingEngine{}
+	settingEngine.SetICETimeouts(time.Second, time.Second, 200*time.Millisecond)
+	api := NewAPI(WithSettingEngine(settingEngine))
+
+	pcOffer, pcAnswer, err := api.newPair(Configuration{BundlePolicy: BundlePolicyMaxBundle})
+	assert.NoError(t, err)
+	_, err = pcOffer.CreateDataChannel("data", nil)
+	assert.NoError(t, err)
+
+	connected := untilConnectionState(PeerConnectionStateConnected, pcOffer, pcAnswer)
+	assert.NoError(t, signalPair(pcOffer, pcAnswer))
+	connected.Wait()
+
+	firstFingerprint, _, err := extractFingerprint(pcOffer.LocalDescription().parsed)
+	assert.NoError(t, err)
+	firstUfrag, _ := pcOffer.LocalDescription().parsed.MediaDescriptions[0].Attribute("ice-ufrag")
+	closePairNow(t, pcOffer, pcAnswer)
+
+	// The clones reconnect with the same certificates and configuration
+	cloneOffer, err := pcOffer.Clone()
+	assert.NoError(t, err)
+	cloneAnswer, err := pcAnswer.Clone()
+	assert.NoError(t, err)
+
+	assert.Equal(t, pcOffer.GetConfiguration(), cloneOffer.GetConfiguration())
+	assert.Equal(t, BundlePolicyMaxBundle, cloneOffer.GetConfiguration().BundlePolicy)
+	assert.Empty(t, cloneOffer.GetTransceivers())
+	assert.Nil(t, cloneOffer.LocalDescription())
+	assert.Same(t, api.settingEngine, cloneOffer.api.settingEngine)
+
+	_, err = cloneOffer.CreateDataChannel("data", nil)
+	assert.NoError(t, err)
+
+	connected = untilConnectionState(PeerConnectionStateConnected, cloneOffer, cloneAnswer)
+	assert.NoError(t, signalPair(cloneOffer, cloneAnswer))
+	connected.Wait()
+
+	fingerprint, _, err := extractFingerprint(cloneOffer.LocalDescription().parsed)
+	assert.NoError(t, err)
+	assert.Equal(t, firstFingerprint, fingerprint)
+	ufrag, _ := cloneOffer.LocalDescription().parsed.MediaDescriptions[0].Attribute("ice-ufrag")
+	assert.NotEqual(t, firstUfrag, ufrag)
+
+	closePairNow(t, cloneOffer, cloneAnswer)
+}
+
+func TestPeerConnection_OnReady(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	assert.NoError(t, err)
+
+	_, err = pcOffer.CreateDataChannel("data", nil)
+	assert.NoError(t, err)
+	_, err = pcOffer.AddTransceiverFromKind(RTPCodecTypeVideo)
+	assert.NoError(t, err)
+
+	var readyCount atomic.Int32
+	ready := make(chan *PeerConnection, 2)
+	for _, pc := range []*PeerConnection{pcOffer, pcAnswer} {
+		pc := pc
+		pc.OnReady(func() {
+			readyCount.Add(1)
+			assert.Equal(t, PeerConnectionStateConnected, pc.ConnectionState())
+			assert.Equal(t, SCTPTransportStateConnected, pc.SCTP().State())
+			_, srtpErr := pc.dtlsTransport.getSRTPSession()
+			assert.NoError(t, srtpErr)
+			ready <- pc
+		})
+	}
+
+	assert.NoError(t, signalPair(pcOffer, pcAnswer))
+	assert.ElementsMatch(t, []*PeerConnection{pcOffer, pcAnswer}, []*PeerConnection{<-ready, <-ready})
+
+	// Renegotiating doesn't make it ready again
+	_, err = pcOffer.AddTransceiverFromKind(RTPCodecTypeAudio)
+	assert.NoError(t, err)
+	assert.NoError(t, signalPair(pcOffer, pcAnswer))
+	assert.Equal(t, int32(2), readyCount.Load())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
@@ -1983,3 +1983,121 @@ func Test_PeerConnection_RTX_E2E(t *testing.T) { //nolint:cyclop
 	assert.NoError(t, wan.Stop())
 	closePairNow(t, pcOffer, pcAnswer)
 }
+
+// A remote that doesn't bundle still gets its media sections, with the MID
+// extension used to demultiplex the streams of the shared transport.
+func TestPeerConnection_NonBundledMedia(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	mediaEngine := &MediaEngine{}
+	require.NoError(t, mediaEngine.RegisterDefaultCodecs())
+	for _, codecType := range []RTPCodecType{RTPCodecTypeAudio, RTPCodecTypeVideo} {
+		require.NoError(t, mediaEngine.RegisterHeaderExtension(RTPHeaderExtensionCapability{URI: sdp.SDESMidURI}, codecType))
+	}
+	pcOffer, pcAnswer, err := NewAPI(WithMediaEngine(mediaEngine)).newPair(Configuration{})
+	require.NoError(t, err)
+
+	tracks := map[*RTPSender]*TrackLocalStaticRTP{}
+	for _, mimeType := range []string{MimeTypeVP8, MimeTypeOpus} {
+		track, trackErr := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: mimeType}, mimeType, "pion")
+		require.NoError(t, trackErr)
+		sender, trackErr := pcOffer.AddTrack(track)
+		require.NoError(t, trackErr)
+		tracks[sender] = track
+	}
+
+	var onTrackCount atomic.Int32
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	pcAnswer.OnTrack(func(trackRemote *TrackRemote, receiver *RTPReceiver) {
+		assert.Equal(t, receiver.RTPTransceiver().Kind(), trackRemote.Kind())
+		if onTrackCount.Add(1) == 2 {
+			onTrackFiredFunc()
+		}
+	})
+
+	// Without BUNDLE group and SSRCs the streams can only be matched by MID
+	bundleGroup := regexp.MustCompile(`a=group:BUNDLE[^\n]*\n`)
+	require.NoError(t, signalPairWithModification(pcOffer, pcAnswer, func(offer string) string {
+		return filterSsrc(bundleGroup.ReplaceAllString(offer, ""))
+	}))
+
+	answer := pcAnswer.LocalDescription().parsed
+	_, bundled := answer.Attribute(sdp.AttrKeyGroup)
+	assert.False(t, bundled)
+	require.Len(t, answer.MediaDescriptions, 3)
+	for _, media := range answer.MediaDescriptions {
+		assert.NotZero(t, media.MediaName.Port.Value, media.MediaName.Media)
+
+		// The remote expects a transport in every media section
+		_, hasCandidate := media.Attribute(sdp.AttrKeyCandidate)
+		assert.True(t, hasCandidate, media.MediaName.Media)
+	}
+
+	for sequenceNumber := uint16(0); onTrackFired.Err() == nil; sequenceNumber++ {
+		time.Sleep(20 * time.Millisecond)
+
+		for _, transceiver := range pcOffer.GetTransceivers() {
+			var midID uint8
+			for _, extension := range transceiver.Sender().GetParameters().HeaderExtensions {
+				if extension.URI == sdp.SDESMidURI {
+					midID = uint8(extension.ID) //nolint:gosec // G115
+				}
+			}
+			require.NotZero(t, midID)
+
+			pkt := &rtp.Packet{Header: rtp.Header{Version: 2, SequenceNumber: sequenceNumber}, Payload: []byte{0x00}}
+			assert.NoError(t, pkt.Header.SetExtension(midID, []byte(transceiver.Mid())))
+			assert.NoError(t, tracks[transceiver.Sender()].WriteRTP(pkt))
+		}
+	}
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
+
+// A remote that runs a separate ICE transport per media section can't be served by the
+// single ICE transport of a PeerConnection.
+func TestPeerConnection_NonBundledTransports(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	for _, kind := range []RTPCodecType{RTPCodecTypeVideo, RTPCodecTypeAudio} {
+		_, err = pcOffer.AddTransceiverFromKind(kind)
+		require.NoError(t, err)
+	}
+
+	offer, err := pcOffer.CreateOffer(nil)
+	require.NoError(t, err)
+	offerGatheringComplete := GatheringCompletePromise(pcOffer)
+	require.NoError(t, pcOffer.SetLocalDescription(offer))
+	<-offerGatheringComplete
+
+	// Every media section gets ICE credentials of its own, as a remote that doesn't bundle does
+	parsed := pcOffer.LocalDescription().parsed
+	_, bundled := parsed.Attribute(sdp.AttrKeyGroup)
+	require.True(t, bundled)
+	parsed.Attributes = nil
+	for i, media := range parsed.MediaDescriptions {
+		for j, attribute := range media.Attributes {
+			if attribute.Key == "ice-ufrag" {
+				media.Attributes[j].Value = fmt.Sprintf("%s%d", attribute.Value, i)
+			}
+		}
+	}
+	raw, err := parsed.Marshal()
+	require.NoError(t, err)
+
+	err = pcAnswer.SetRemoteDescription(SessionDescription{Type: SDPTypeOffer, SDP: string(raw)})
+	assert.ErrorIs(t, err, errSDPNonBundledTransports)
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
@@ -540,6 +540,60 @@ func TestPeerConnection_Renegotiation_CodecChange(t *testing.T) {
 	closePairNow(t, pcOffer, pcAnswer)
 }
 
+func TestPeerConnection_Renegotiation_CodecFmtp(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	require.NoError(t, err)
+
+	sender, err := pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	tracksCh := make(chan *TrackRemote, 1)
+	pcAnswer.OnTrack(func(track *TrackRemote, _ *RTPReceiver) {
+		onTrackFiredFunc()
+		tracksCh <- track
+	})
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	sendVideoUntilDone(t, onTrackFired.Done(), []*TrackLocalStaticSample{track})
+	remoteTrack := <-tracksCh
+	assert.Equal(t, "", remoteTrack.Codec().SDPFmtpLine)
+
+	transceiver := pcOffer.GetTransceivers()[0]
+	payloadType := sender.GetParameters().Codecs[0].PayloadType
+	rtxPayloadType := findRTXPayloadType(payloadType, sender.GetParameters().Codecs)
+
+	assert.ErrorIs(t, transceiver.SetCodecFmtp(PayloadType(0), "max-fr=30"), errRTPTransceiverCodecNotFound)
+	assert.ErrorIs(t, transceiver.SetCodecFmtp(rtxPayloadType, "apt=0"), errRTPTransceiverFmtpIncompatible)
+	assert.False(t, pcOffer.checkNegotiationNeeded())
+
+	negotiationNeeded := make(chan struct{}, 1)
+	pcOffer.OnNegotiationNeeded(func() {
+		negotiationNeeded <- struct{}{}
+	})
+
+	require.NoError(t, transceiver.SetCodecFmtp(payloadType, "max-fr=30;max-fs=3600"))
+	<-negotiationNeeded
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	assert.Contains(t, pcOffer.LocalDescription().SDP, "max-fr=30;max-fs=3600")
+	assert.Contains(t, pcAnswer.LocalDescription().SDP, "max-fr=30;max-fs=3600")
+	assert.Equal(t, "max-fr=30;max-fs=3600", remoteTrack.Codec().SDPFmtpLine)
+	assert.Equal(t, MimeTypeVP8, remoteTrack.Codec().MimeType)
+	assert.False(t, pcOffer.checkNegotiationNeeded())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
+
 func TestPeerConnection_Renegotiation_RemoveTrack(t *testing.T) {
 	lim := test.TimeOut(time.Second * 30)
 	defer lim.Stop()
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"sync"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/internal/util"
+)
+
+const (
+	randomSourceAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	// randomSourceMaxSequenceNumber keeps the first sequence number in the lower half, like
+	// rtp.NewRandomSequencer, so the SRTP rollover counter can't be mistaken at the start.
+	randomSourceMaxSequenceNumber = 1 << 15
+)
+
+// randomSource produces the random values of a PeerConnection that don't need to be
+// unpredictable: SSRCs, first sequence numbers and timestamps, and the identifiers of the
+// senders and the streams. They are read from the reader of SettingEngine.SetRandomSource,
+// a nil randomSource uses the package generator seeded from crypto/rand.
+type randomSource struct {
+	mu     sync.Mutex
+	reader io.Reader
+}
+
+func (s *randomSource) uint32() uint32 {
+	if s == nil {
+		return util.RandUint32()
+	}
+
+	var b [4]byte
+	s.mu.Lock()
+	_, err := io.ReadFull(s.reader, b[:])
+	s.mu.Unlock()
+	if err != nil {
+		return util.RandUint32()
+	}
+
+	return binary.BigEndian.Uint32(b[:])
+}
+
+// fork returns a randomSource seeded from s, for a PeerConnection to generate its values
+// independently of the other PeerConnections reading s. The fork of a nil randomSource, or of
+// one whose reader fails, is nil.
+func (s *randomSource) fork() *randomSource {
+	if s == nil {
+		return nil
+	}
+
+	var b [8]byte
+	s.mu.Lock()
+	_, err := io.ReadFull(s.reader, b[:])
+	s.mu.Unlock()
+	if err != nil {
+		return nil
+	}
+
+	seed := int64(binary.BigEndian.Uint64(b[:])) //nolint:gosec // G115
+
+	return &randomSource{reader: rand.New(rand.NewSource(seed))} //nolint:gosec // G404, not cryptographic
+}
+
+// alpha returns a string of n random letters.
+func (s *randomSource) alpha(n int) string {
+	if s == nil {
+		return util.MathRandAlpha(n)
+	}
+
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = randomSourceAlphabet[s.uint32()%uint32(len(randomSourceAlphabet))]
+	}
+
+	return string(b)
+}
+
+// sequencer returns the sequencer of a new stream, starting at a random sequence number.
+func (s *randomSource) sequencer() rtp.Sequencer {
+	if s == nil {
+		return rtp.NewRandomSequencer()
+	}
+
+	return rtp.NewFixedSequencer(uint16(s.uint32() % randomSourceMaxSequenceNumber)) //nolint:gosec // G115
+}
+
+// randomSourceFromContext returns the randomSource of the PeerConnection a track is bound to.
+func randomSourceFromContext(ctx TrackLocalContext) *randomSource {
+	if ctx, ok := ctx.(*baseTrackLocalContext); ok {
+		return ctx.random
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// headerRecorder records the headers of the packets written to it.
+type headerRecorder struct {
+	headers []rtp.Header
+}
+
+func (w *headerRecorder) WriteRTP(header *rtp.Header, _ []byte) (int, error) {
+	w.headers = append(w.headers, *header)
+
+	return 1, nil
+}
+
+func (w *headerRecorder) Write([]byte) (int, error) {
+	return 0, nil
+}
+
+func TestSettingEngine_SetRandomSource(t *testing.T) {
+	type randoms struct {
+		ssrc           SSRC
+		senderID       string
+		streamID       string
+		sequenceNumber uint16
+		timestamp      uint32
+	}
+
+	generate := func(seed int64) randoms {
+		settingEngine := SettingEngine{}
+		settingEngine.SetRandomSource(rand.New(rand.NewSource(seed))) //nolint:gosec
+		api := NewAPI(WithSettingEngine(settingEngine))
+
+		pc, err := api.NewPeerConnection(Configuration{})
+		require.NoError(t, err)
+		track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+		require.NoError(t, err)
+		sender, err := pc.AddTrack(track)
+		require.NoError(t, err)
+
+		generated := randoms{
+			ssrc:     sender.GetParameters().Encodings[0].SSRC,
+			senderID: sender.id,
+			streamID: pc.NewMediaStream("").ID(),
+		}
+		assert.NoError(t, pc.Close())
+
+		// The first sequence number and timestamp of a bound track
+		recorder := &headerRecorder{}
+		_, err = track.Bind(&baseTrackLocalContext{
+			params: RTPParameters{Codecs: []RTPCodecParameters{{
+				RTPCodecCapability: RTPCodecCapability{MimeType: MimeTypeVP8, ClockRate: 90000},
+				PayloadType:        96,
+			}}},
+			ssrc:        1,
+			writeStream: recorder,
+			random:      api.settingEngine.random,
+		})
+		require.NoError(t, err)
+		require.NoError(t, track.WriteSample(media.Sample{Data: []byte{0x00}, Duration: time.Second}))
+		require.NotEmpty(t, recorder.headers)
+		generated.sequenceNumber = recorder.headers[0].SequenceNumber
+		generated.timestamp = recorder.headers[0].Timestamp
+
+		return generated
+	}
+
+	first := generate(1)
+	assert.Equal(t, first, generate(1))
+	assert.NotEqual(t, first, generate(2))
+	assert.Less(t, first.sequenceNumber, uint16(randomSourceMaxSequenceNumber))
+}
+
+func TestSettingEngine_SetRandomSourcePerPeerConnection(t *testing.T) {
+	// The SSRC of the track of the second PeerConnection doesn't depend on the tracks of the first
+	generate := func(firstTracks int) SSRC {
+		settingEngine := SettingEngine{}
+		settingEngine.SetRandomSource(rand.New(rand.NewSource(1))) //nolint:gosec
+		api := NewAPI(WithSettingEngine(settingEngine))
+
+		first, err := api.NewPeerConnection(Configuration{})
+		require.NoError(t, err)
+		second, err := api.NewPeerConnection(Configuration{})
+		require.NoError(t, err)
+
+		for i := 0; i < firstTracks; i++ {
+			_, err = first.AddTransceiverFromKind(RTPCodecTypeVideo)
+			require.NoError(t, err)
+		}
+
+		track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+		require.NoError(t, err)
+		sender, err := second.AddTrack(track)
+		require.NoError(t, err)
+
+		assert.NoError(t, first.Close())
+		assert.NoError(t, second.Close())
+
+		return sender.GetParameters().Encodings[0].SSRC
+	}
+
+	assert.Equal(t, generate(0), generate(3))
+}
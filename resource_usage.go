@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+// ResourceUsage is an approximate count of the resources held by a PeerConnection.
+// It is cheap to compute and meant for capacity planning and leak detection,
+// not for exact accounting.
+type ResourceUsage struct {
+	// Goroutines is the number of running goroutines started by this package on
+	// behalf of the connection: RTP/RTCP processing, RTX readers and DataChannel
+	// read loops. Attribution is best-effort, goroutines owned by the ICE, DTLS and
+	// SCTP libraries and by interceptors are not included.
+	Goroutines int
+
+	// BufferedBytes is the number of bytes currently waiting in the receive
+	// buffers of the SRTP and SRTCP streams.
+	BufferedBytes int
+
+	// Transceivers is the number of RTPTransceivers of the connection.
+	Transceivers int
+
+	// DataChannels is the number of DataChannels of the connection.
+	DataChannels int
+}
+
+// ResourceUsage returns an approximate snapshot of the resources held by the PeerConnection.
+func (pc *PeerConnection) ResourceUsage() ResourceUsage {
+	usage := ResourceUsage{
+		Goroutines:    int(pc.goroutines.Load()),
+		BufferedBytes: pc.dtlsTransport.bufferedBytes(),
+	}
+
+	for _, transceiver := range pc.GetTransceivers() {
+		usage.Transceivers++
+		if receiver := transceiver.Receiver(); receiver != nil {
+			usage.Goroutines += receiver.runningGoroutines()
+		}
+	}
+
+	pc.sctpTransport.lock.RLock()
+	dataChannels := append([]*DataChannel{}, pc.sctpTransport.dataChannels...)
+	pc.sctpTransport.lock.RUnlock()
+
+	for _, dataChannel := range dataChannels {
+		usage.DataChannels++
+		if dataChannel.readLoopRunning() {
+			usage.Goroutines++
+		}
+	}
+
+	return usage
+}
+
+// goTracked runs fn in a new goroutine that is counted by ResourceUsage.
+func (pc *PeerConnection) goTracked(fn func()) {
+	pc.goroutines.Add(1)
+	go func() {
+		defer pc.goroutines.Add(-1)
+		fn()
+	}()
+}
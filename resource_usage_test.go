@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerConnection_ResourceUsage(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	assert.Equal(t, ResourceUsage{}, pcOffer.ResourceUsage())
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	require.NoError(t, err)
+	_, err = pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	dataChannelOpened, dataChannelOpenedFunc := context.WithCancel(context.Background())
+	pcAnswer.OnDataChannel(func(d *DataChannel) {
+		d.OnOpen(dataChannelOpenedFunc)
+	})
+
+	// The track is never read, so packets pile up in the receive buffers
+	trackFired := make(chan struct{})
+	pcAnswer.OnTrack(func(*TrackRemote, *RTPReceiver) {
+		close(trackFired)
+	})
+
+	// signalPair creates a DataChannel on pcOffer
+	connected := untilConnectionState(PeerConnectionStateConnected, pcOffer, pcAnswer)
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	connected.Wait()
+	<-dataChannelOpened.Done()
+
+	assert.Eventually(t, func() bool {
+		assert.NoError(t, track.WriteSample(media.Sample{Data: []byte{0x00}, Duration: time.Millisecond}))
+
+		select {
+		case <-trackFired:
+		default:
+			return false
+		}
+
+		return pcAnswer.ResourceUsage().BufferedBytes > 0
+	}, 10*time.Second, 10*time.Millisecond)
+
+	usage := pcAnswer.ResourceUsage()
+	assert.Equal(t, 1, usage.Transceivers)
+	assert.Equal(t, 1, usage.DataChannels)
+	// The undeclared RTP and RTCP processors and the DataChannel read loop
+	assert.GreaterOrEqual(t, usage.Goroutines, 3)
+
+	closePairNow(t, pcOffer, pcAnswer)
+
+	// The buffers are forgotten once their streams are closed
+	pcAnswer.dtlsTransport.streamBuffersLock.Lock()
+	assert.Empty(t, pcAnswer.dtlsTransport.streamBuffers)
+	pcAnswer.dtlsTransport.streamBuffersLock.Unlock()
+
+	assert.Eventually(t, func() bool {
+		return pcOffer.ResourceUsage().Goroutines == 0 && pcAnswer.ResourceUsage().Goroutines == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
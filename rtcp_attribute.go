@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pion/sdp/v3"
+)
+
+// sdpAttributeRTCP is the attribute of RFC 3605 with the port, and optionally the address,
+// RTCP is received on when it isn't multiplexed with RTP.
+const sdpAttributeRTCP = "rtcp"
+
+// rtcpAttribute is the value of an a=rtcp attribute, "port [nettype addrtype address]".
+type rtcpAttribute struct {
+	port        int
+	networkType string
+	addressType string
+	address     string
+}
+
+func parseRTCPAttribute(value string) (rtcpAttribute, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 1 && len(fields) != 4 {
+		return rtcpAttribute{}, fmt.Errorf("%w: %q", errSDPInvalidRTCPAttribute, value)
+	}
+
+	port, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return rtcpAttribute{}, fmt.Errorf("%w: %q", errSDPInvalidRTCPAttribute, value)
+	}
+
+	attribute := rtcpAttribute{port: int(port)}
+	if len(fields) == 4 {
+		attribute.networkType, attribute.addressType, attribute.address = fields[1], fields[2], fields[3]
+	}
+
+	return attribute, nil
+}
+
+func (a rtcpAttribute) String() string {
+	if a.address == "" {
+		return strconv.Itoa(a.port)
+	}
+
+	return fmt.Sprintf("%d %s %s %s", a.port, a.networkType, a.addressType, a.address)
+}
+
+// rtcpAttributeFromMedia returns the address RTCP is received on for the media section:
+// the a=rtcp attribute, completed with the connection of the section, or the port next to
+// the one of RTP when there is none, RFC 3605 section 2.
+func rtcpAttributeFromMedia(media *sdp.MediaDescription) (rtcpAttribute, error) {
+	attribute := rtcpAttribute{port: media.MediaName.Port.Value + 1}
+	if value, ok := media.Attribute(sdpAttributeRTCP); ok {
+		var err error
+		if attribute, err = parseRTCPAttribute(value); err != nil {
+			return rtcpAttribute{}, err
+		}
+	}
+
+	if attribute.address == "" {
+		attribute = attribute.withConnection(media)
+	}
+
+	return attribute, nil
+}
+
+func (a rtcpAttribute) withConnection(media *sdp.MediaDescription) rtcpAttribute {
+	if media.ConnectionInformation != nil && media.ConnectionInformation.Address != nil {
+		a.networkType = media.ConnectionInformation.NetworkType
+		a.addressType = media.ConnectionInformation.AddressType
+		a.address = media.ConnectionInformation.Address.Address
+	}
+
+	return a
+}
+
+// muxedRTCPAttribute returns the a=rtcp attribute of a local media section. RTCP is always
+// multiplexed on the single ICE component of the RTP candidates, the attribute repeats the
+// port and the connection of the section, which an endpoint falling back to non-multiplexed
+// RTCP would use, RFC 5761 section 5.1.3.
+func muxedRTCPAttribute(media *sdp.MediaDescription) rtcpAttribute {
+	return rtcpAttribute{port: media.MediaName.Port.Value}.withConnection(media)
+}
+
+// checkRemoteRTCP parses the a=rtcp attributes of the RTP media sections of a remote
+// description. A remote that doesn't multiplex RTCP expects it on the address of a=rtcp, which
+// is only reported: there is a single ICE component, RTCP is still sent and received on the
+// RTP candidates, so such a remote only interoperates if it accepts RTCP there. An a=rtcp
+// attribute that can't be parsed is reported and ignored, the description is still accepted.
+func (pc *PeerConnection) checkRemoteRTCP(desc *sdp.SessionDescription) {
+	for _, media := range desc.MediaDescriptions {
+		if media.MediaName.Media == mediaSectionApplication || media.MediaName.Port.Value == 0 {
+			continue
+		}
+
+		attribute, err := rtcpAttributeFromMedia(media)
+		if err != nil {
+			pc.log.Warnf("media section %q: %v", getMidValue(media), err)
+
+			continue
+		}
+
+		if _, muxed := media.Attribute(sdp.AttrKeyRTCPMux); !muxed && attribute.port != media.MediaName.Port.Value {
+			pc.log.Warnf(
+				"media section %q doesn't multiplex RTCP, its RTCP address %s is ignored, RTCP is sent on the RTP candidates",
+				getMidValue(media), attribute,
+			)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRTCPAttribute(t *testing.T) {
+	for _, value := range []string{"53021", "9 IN IP4 0.0.0.0", "53021 IN IP6 2001:db8::2"} {
+		attribute, err := parseRTCPAttribute(value)
+		assert.NoError(t, err)
+		assert.Equal(t, value, attribute.String())
+	}
+
+	for _, value := range []string{"", "port", "70000", "9 IN IP4"} {
+		_, err := parseRTCPAttribute(value)
+		assert.ErrorIs(t, err, errSDPInvalidRTCPAttribute, value)
+	}
+}
+
+func TestRTCPAttributeFromMedia(t *testing.T) {
+	media := sdp.NewJSEPMediaDescription("audio", nil)
+	media.MediaName.Port = sdp.RangedPort{Value: 49170}
+
+	// Without a=rtcp RTCP is on the next port
+	attribute, err := rtcpAttributeFromMedia(media)
+	assert.NoError(t, err)
+	assert.Equal(t, "49171 IN IP4 0.0.0.0", attribute.String())
+
+	media.WithValueAttribute(sdpAttributeRTCP, "53020")
+	attribute, err = rtcpAttributeFromMedia(media)
+	assert.NoError(t, err)
+	assert.Equal(t, "53020 IN IP4 0.0.0.0", attribute.String())
+}
+
+func TestPeerConnection_RTCPAttribute(t *testing.T) {
+	offerer, err := NewPeerConnection(Configuration{})
+	require.NoError(t, err)
+	_, err = offerer.AddTransceiverFromKind(RTPCodecTypeAudio)
+	require.NoError(t, err)
+	_, err = offerer.CreateDataChannel("data", nil)
+	require.NoError(t, err)
+
+	offer, err := offerer.CreateOffer(nil)
+	require.NoError(t, err)
+	parsed, err := offer.Unmarshal()
+	require.NoError(t, err)
+
+	value, ok := parsed.MediaDescriptions[0].Attribute(sdpAttributeRTCP)
+	assert.True(t, ok)
+	assert.Equal(t, "9 IN IP4 0.0.0.0", value)
+	_, ok = parsed.MediaDescriptions[1].Attribute(sdpAttributeRTCP)
+	assert.False(t, ok, "no RTCP for data channels")
+
+	// A remote that doesn't multiplex RTCP is still answered, on a single transport
+	nonMuxed := strings.Replace(offer.SDP, "a=rtcp:9 IN IP4 0.0.0.0\r\n", "a=rtcp:10 IN IP4 0.0.0.0\r\n", 1)
+	nonMuxed = strings.Replace(nonMuxed, "a=rtcp-mux\r\n", "", 1)
+
+	answerer, err := NewPeerConnection(Configuration{})
+	require.NoError(t, err)
+	require.NoError(t, answerer.SetRemoteDescription(SessionDescription{Type: SDPTypeOffer, SDP: nonMuxed}))
+	answer, err := answerer.CreateAnswer(nil)
+	require.NoError(t, err)
+	parsed, err = answer.Unmarshal()
+	require.NoError(t, err)
+
+	value, ok = parsed.MediaDescriptions[0].Attribute(sdpAttributeRTCP)
+	assert.True(t, ok)
+	assert.Equal(t, "9 IN IP4 0.0.0.0", value)
+	_, ok = parsed.MediaDescriptions[0].Attribute(sdp.AttrKeyRTCPMux)
+	assert.True(t, ok)
+
+	// An invalid a=rtcp is ignored
+	invalid := strings.Replace(offer.SDP, "a=rtcp:9 IN IP4 0.0.0.0\r\n", "a=rtcp:port\r\n", 1)
+	lenient, err := NewPeerConnection(Configuration{})
+	require.NoError(t, err)
+	assert.NoError(t, lenient.SetRemoteDescription(SessionDescription{Type: SDPTypeOffer, SDP: invalid}))
+
+	assert.NoError(t, offerer.Close())
+	assert.NoError(t, answerer.Close())
+	assert.NoError(t, lenient.Close())
+}
@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"github.com/pion/rtcp"
+)
+
+// SetRTCPDisabled stops the RTCP of the transceiver, for one-way broadcasts to trusted
+// receivers where it is only overhead. While disabled, no RTCP is sent for the SSRCs of its
+// sender and its receiver, neither the reports nor the feedback of the interceptors nor the
+// packets written with PeerConnection.WriteRTCP, and the RTCP received for them is discarded
+// before the interceptors, ReadRTCP of the RTPSender and the RTPReceiver then blocks until
+// they stop.
+//
+// Every feature relying on RTCP stops working for the transceiver: retransmissions, keyframe
+// requests, round trip time and loss statistics, and lip sync with the sender reports.
+// Transport-wide congestion control feedback and REMB are shared by all the tracks and are
+// still sent. The feedback negotiated in the session description doesn't change, use
+// SetRTCPFeedback to not negotiate it. The RTCP of the other transceivers isn't affected.
+func (t *RTPTransceiver) SetRTCPDisabled(disabled bool) {
+	t.rtcpDisabled.Store(disabled)
+
+	if transport := t.transport(); transport != nil {
+		transport.setRTCPDisabled(t, disabled)
+	}
+}
+
+// RTCPDisabled reports whether the RTCP of the transceiver is disabled, see SetRTCPDisabled.
+func (t *RTPTransceiver) RTCPDisabled() bool {
+	return t.rtcpDisabled.Load()
+}
+
+// transport returns the DTLSTransport of the sender or the receiver of the transceiver.
+func (t *RTPTransceiver) transport() *DTLSTransport {
+	if receiver := t.Receiver(); receiver != nil {
+		return receiver.transport
+	} else if sender := t.Sender(); sender != nil {
+		return sender.transport
+	}
+
+	return nil
+}
+
+// usesSSRC reports whether ssrc is one of the SSRCs sent or received by the transceiver.
+func (t *RTPTransceiver) usesSSRC(ssrc SSRC) bool {
+	if sender := t.Sender(); sender != nil {
+		sender.mu.RLock()
+		for _, encoding := range sender.trackEncodings {
+			if encoding.ssrc == ssrc || encoding.ssrcRTX == ssrc || encoding.ssrcFEC == ssrc {
+				sender.mu.RUnlock()
+
+				return true
+			}
+		}
+		sender.mu.RUnlock()
+	}
+
+	if receiver := t.Receiver(); receiver != nil {
+		receiver.mu.RLock()
+		defer receiver.mu.RUnlock()
+		for _, track := range receiver.tracks {
+			if track.streamInfo != nil && SSRC(track.streamInfo.SSRC) == ssrc {
+				return true
+			}
+			if track.repairStreamInfo != nil && SSRC(track.repairStreamInfo.SSRC) == ssrc {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (t *DTLSTransport) setRTCPDisabled(transceiver *RTPTransceiver, disabled bool) {
+	t.rtcpDisabledLock.Lock()
+	defer t.rtcpDisabledLock.Unlock()
+
+	if !disabled {
+		delete(t.rtcpDisabled, transceiver)
+
+		return
+	}
+	if t.rtcpDisabled == nil {
+		t.rtcpDisabled = map[*RTPTransceiver]struct{}{}
+	}
+	t.rtcpDisabled[transceiver] = struct{}{}
+}
+
+// isRTCPDisabled reports whether the RTCP of ssrc is disabled with
+// RTPTransceiver.SetRTCPDisabled.
+func (t *DTLSTransport) isRTCPDisabled(ssrc SSRC) bool {
+	t.rtcpDisabledLock.RLock()
+	defer t.rtcpDisabledLock.RUnlock()
+
+	for transceiver := range t.rtcpDisabled {
+		if transceiver.usesSSRC(ssrc) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readRTCP reads the RTCP of ssrc from read, the packets are discarded while its
+// RTCP is disabled.
+func (t *DTLSTransport) readRTCP(ssrc SSRC, in []byte, read func([]byte) (int, error)) (int, error) {
+	for {
+		n, err := read(in)
+		if err != nil || !t.isRTCPDisabled(ssrc) {
+			return n, err
+		}
+	}
+}
+
+// filterDisabledRTCP removes the packets, or the parts of the reports, about the SSRCs whose
+// RTCP is disabled. The transport-wide feedback is kept.
+func (t *DTLSTransport) filterDisabledRTCP(pkts []rtcp.Packet) []rtcp.Packet {
+	t.rtcpDisabledLock.RLock()
+	disabled := len(t.rtcpDisabled) != 0
+	t.rtcpDisabledLock.RUnlock()
+	if !disabled {
+		return pkts
+	}
+
+	filtered := pkts[:0:0]
+	for _, pkt := range pkts {
+		switch pkt := pkt.(type) {
+		case *rtcp.TransportLayerCC, *rtcp.ReceiverEstimatedMaximumBitrate:
+			filtered = append(filtered, pkt)
+		case *rtcp.SenderReport:
+			if !t.isRTCPDisabled(SSRC(pkt.SSRC)) {
+				filtered = append(filtered, pkt)
+			}
+		case *rtcp.ReceiverReport:
+			reports := []rtcp.ReceptionReport{}
+			for _, report := range pkt.Reports {
+				if !t.isRTCPDisabled(SSRC(report.SSRC)) {
+					reports = append(reports, report)
+				}
+			}
+			if len(reports) == len(pkt.Reports) {
+				filtered = append(filtered, pkt)
+			} else if len(reports) != 0 {
+				report := *pkt
+				report.Reports = reports
+				filtered = append(filtered, &report)
+			}
+		case *rtcp.SourceDescription:
+			chunks := []rtcp.SourceDescriptionChunk{}
+			for _, chunk := range pkt.Chunks {
+				if !t.isRTCPDisabled(SSRC(chunk.Source)) {
+					chunks = append(chunks, chunk)
+				}
+			}
+			if len(chunks) != 0 {
+				filtered = append(filtered, &rtcp.SourceDescription{Chunks: chunks})
+			}
+		default:
+			if !t.allRTCPDisabled(pkt.DestinationSSRC()) {
+				filtered = append(filtered, pkt)
+			}
+		}
+	}
+
+	return filtered
+}
+
+// allRTCPDisabled reports whether the RTCP of every SSRC of a packet is disabled.
+func (t *DTLSTransport) allRTCPDisabled(ssrcs []uint32) bool {
+	for _, ssrc := range ssrcs {
+		if !t.isRTCPDisabled(SSRC(ssrc)) {
+			return false
+		}
+	}
+
+	return len(ssrcs) != 0
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRTPTransceiver_SetRTCPDisabled(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	tracks := []*TrackLocalStaticSample{}
+	senders := []*RTPSender{}
+	for _, id := range []string{"disabled", "enabled"} {
+		track, trackErr := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, id, "pion")
+		require.NoError(t, trackErr)
+		sender, trackErr := pcOffer.AddTrack(track)
+		require.NoError(t, trackErr)
+		tracks = append(tracks, track)
+		senders = append(senders, sender)
+	}
+
+	disabled := pcOffer.GetTransceivers()[0]
+	disabled.SetRTCPDisabled(true)
+	assert.True(t, disabled.RTCPDisabled())
+	assert.False(t, pcOffer.GetTransceivers()[1].RTCPDisabled())
+
+	remoteTracks := make(chan *TrackRemote, 2)
+	pcAnswer.OnTrack(func(track *TrackRemote, _ *RTPReceiver) {
+		remoteTracks <- track
+	})
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	done := make(chan struct{})
+	go sendVideoUntilDone(t, done, tracks)
+	ssrcs := []uint32{uint32((<-remoteTracks).SSRC()), uint32((<-remoteTracks).SSRC())}
+	disabledSSRC := uint32(senders[0].GetParameters().Encodings[0].SSRC)
+	enabledSSRC := uint32(senders[1].GetParameters().Encodings[0].SSRC)
+	assert.ElementsMatch(t, []uint32{disabledSSRC, enabledSSRC}, ssrcs)
+
+	// Nothing about the disabled transceiver is sent, the transport-wide feedback is
+	filtered := pcOffer.dtlsTransport.filterDisabledRTCP([]rtcp.Packet{
+		&rtcp.SenderReport{SSRC: disabledSSRC},
+		&rtcp.SenderReport{SSRC: enabledSSRC},
+		&rtcp.ReceiverReport{SSRC: 1, Reports: []rtcp.ReceptionReport{{SSRC: disabledSSRC}, {SSRC: enabledSSRC}}},
+		&rtcp.PictureLossIndication{MediaSSRC: disabledSSRC},
+		&rtcp.TransportLayerCC{MediaSSRC: disabledSSRC},
+	})
+	assert.Equal(t, []rtcp.Packet{
+		&rtcp.SenderReport{SSRC: enabledSSRC},
+		&rtcp.ReceiverReport{SSRC: 1, Reports: []rtcp.ReceptionReport{{SSRC: enabledSSRC}}},
+		&rtcp.TransportLayerCC{MediaSSRC: disabledSSRC},
+	}, filtered)
+
+	// The RTCP received for the disabled transceiver is discarded
+	var disabledReceived atomic.Bool
+	disabledReadDone := make(chan struct{})
+	go func() {
+		defer close(disabledReadDone)
+		for {
+			if _, _, readErr := senders[0].ReadRTCP(); readErr != nil {
+				return
+			}
+			disabledReceived.Store(true)
+		}
+	}()
+
+	enabledReceived := make(chan struct{})
+	go func() {
+		for {
+			pkts, _, readErr := senders[1].ReadRTCP()
+			if readErr != nil {
+				return
+			}
+			for _, pkt := range pkts {
+				if _, ok := pkt.(*rtcp.PictureLossIndication); ok {
+					close(enabledReceived)
+
+					return
+				}
+			}
+		}
+	}()
+
+	func() {
+		for {
+			assert.NoError(t, pcAnswer.WriteRTCP([]rtcp.Packet{
+				&rtcp.PictureLossIndication{MediaSSRC: disabledSSRC},
+				&rtcp.PictureLossIndication{MediaSSRC: enabledSSRC},
+			}))
+			select {
+			case <-enabledReceived:
+				return
+			case <-time.After(50 * time.Millisecond):
+			}
+		}
+	}()
+
+	close(done)
+	assert.False(t, disabledReceived.Load())
+
+	// Stopping the transceiver forgets it
+	assert.NoError(t, disabled.Stop())
+	<-disabledReadDone
+	pcOffer.dtlsTransport.rtcpDisabledLock.RLock()
+	assert.Empty(t, pcOffer.dtlsTransport.rtcpDisabled)
+	pcOffer.dtlsTransport.rtcpDisabledLock.RUnlock()
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/pion/rtcp"
+)
+
+// rtcpInjectionQueueSize is the number of injected RTCP packets that can wait to be read.
+const rtcpInjectionQueueSize = 64
+
+// rtcpInjection merges the RTCP packets injected with RTPSender.InjectRTCP with the ones
+// received from the network, so both go through the interceptors of an encoding. The network
+// is read from a goroutine started by the first read, a nil rtcpInjection reads the network
+// directly.
+type rtcpInjection struct {
+	injected chan []byte
+	received chan rtcpInjectionRead
+	done     <-chan struct{}
+
+	startOnce sync.Once
+	err       error // set before received is closed
+}
+
+type rtcpInjectionRead struct {
+	pkt []byte
+	err error
+}
+
+func newRTCPInjection(done <-chan struct{}) *rtcpInjection {
+	return &rtcpInjection{
+		injected: make(chan []byte, rtcpInjectionQueueSize),
+		received: make(chan rtcpInjectionRead),
+		done:     done,
+	}
+}
+
+// read returns the next injected or received packet, read reads the network.
+func (i *rtcpInjection) read(in []byte, mtu uint, read func([]byte) (int, error)) (int, error) {
+	if i == nil {
+		return read(in)
+	}
+
+	i.startOnce.Do(func() {
+		go i.readNetwork(mtu, read)
+	})
+
+	select {
+	case pkt := <-i.injected:
+		return copy(in, pkt), nil
+	case result, ok := <-i.received:
+		if !ok {
+			return 0, i.err
+		}
+
+		return copy(in, result.pkt), result.err
+	}
+}
+
+func (i *rtcpInjection) readNetwork(mtu uint, read func([]byte) (int, error)) {
+	for {
+		pkt := make([]byte, mtu)
+		n, err := read(pkt)
+		if err != nil && !isTimeoutError(err) {
+			i.err = err
+			close(i.received)
+
+			return
+		}
+
+		select {
+		case i.received <- rtcpInjectionRead{pkt: pkt[:n], err: err}:
+		case <-i.done:
+			return
+		}
+	}
+}
+
+// isTimeoutError reports whether err is a read deadline, after which reading can continue.
+func isTimeoutError(err error) bool {
+	var netErr net.Error
+
+	return errors.Is(err, os.ErrDeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// InjectRTCP feeds pkts to the RTPSender as if they were received from the remote peer, for
+// testing. They go through the interceptors like the received RTCP and are then returned by
+// Read and ReadRTCP, so the reaction of the congestion control, the retransmissions or the
+// application to crafted receiver reports, NACKs or transport-wide feedback can be tested
+// deterministically without a lossy network. The packets are injected in the first encoding,
+// which is read by Read, and are read in order before the packets received since.
+//
+// It requires SettingEngine.EnableRTCPInjectionForTesting, and fails before Send is called
+// or after Stop. It blocks while the injected packets waiting to be read fill the queue.
+func (r *RTPSender) InjectRTCP(pkts []rtcp.Packet) error {
+	if !r.api.settingEngine.rtcpInjection {
+		return errRTPSenderRTCPInjectionDisabled
+	}
+
+	payload, err := rtcp.Marshal(pkts)
+	if err != nil {
+		return err
+	}
+
+	if r.hasStopped() {
+		return io.ErrClosedPipe
+	}
+	if !r.hasSent() {
+		return errRTPSenderRTCPInjectionNotSent
+	}
+
+	r.mu.RLock()
+	injection := r.trackEncodings[0].rtcpInjection
+	r.mu.RUnlock()
+
+	select {
+	case injection.injected <- payload:
+		return nil
+	case <-r.stopCalled:
+		return io.ErrClosedPipe
+	}
+}
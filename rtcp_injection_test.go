@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	mock_interceptor "github.com/pion/interceptor/pkg/mock"
+	"github.com/pion/rtcp"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRTPSender_InjectRTCP(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	// The interceptors see the injected packets like the received ones
+	interceptorReports := make(chan *rtcp.ReceiverReport, 16)
+	ir := &interceptor.Registry{}
+	ir.Add(&mock_interceptor.Factory{
+		NewInterceptorFn: func(string) (interceptor.Interceptor, error) {
+			return &mock_interceptor.Interceptor{
+				BindRTCPReaderFn: func(reader interceptor.RTCPReader) interceptor.RTCPReader {
+					return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+						n, attributes, err := reader.Read(b, a)
+						if err == nil {
+							if pkts, unmarshalErr := rtcp.Unmarshal(b[:n]); unmarshalErr == nil {
+								for _, pkt := range pkts {
+									if rr, ok := pkt.(*rtcp.ReceiverReport); ok && rr.SSRC == 1 {
+										interceptorReports <- rr
+									}
+								}
+							}
+						}
+
+						return n, attributes, err
+					})
+				},
+			}, nil
+		},
+	})
+
+	settingEngine := SettingEngine{}
+	settingEngine.EnableRTCPInjectionForTesting(true)
+	api := NewAPI(WithSettingEngine(settingEngine), WithInterceptorRegistry(ir))
+
+	pcOffer, err := api.NewPeerConnection(Configuration{})
+	require.NoError(t, err)
+	pcAnswer, err := NewPeerConnection(Configuration{})
+	require.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	require.NoError(t, err)
+	sender, err := pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, sender.InjectRTCP([]rtcp.Packet{&rtcp.PictureLossIndication{}}), errRTPSenderRTCPInjectionNotSent)
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	ssrc := uint32(sender.GetParameters().Encodings[0].SSRC)
+	injected := &rtcp.ReceiverReport{
+		SSRC:              1,
+		Reports:           []rtcp.ReceptionReport{{SSRC: ssrc, FractionLost: 128, TotalLost: 42, LastSenderReport: 7, Delay: 65536}},
+		ProfileExtensions: []byte{},
+	}
+	assert.NoError(t, sender.InjectRTCP([]rtcp.Packet{injected}))
+
+	func() {
+		for {
+			pkts, _, readErr := sender.ReadRTCP()
+			require.NoError(t, readErr)
+			for _, pkt := range pkts {
+				if rr, ok := pkt.(*rtcp.ReceiverReport); ok && rr.SSRC == 1 {
+					assert.Equal(t, injected, rr)
+
+					return
+				}
+			}
+		}
+	}()
+	assert.Equal(t, injected, <-interceptorReports)
+
+	closePairNow(t, pcOffer, pcAnswer)
+	assert.ErrorIs(t, sender.InjectRTCP([]rtcp.Packet{injected}), io.ErrClosedPipe)
+
+	// Injection must be enabled
+	pc, err := NewPeerConnection(Configuration{})
+	require.NoError(t, err)
+	sender, err = pc.AddTrack(track)
+	require.NoError(t, err)
+	assert.ErrorIs(t, sender.InjectRTCP([]rtcp.Packet{injected}), errRTPSenderRTCPInjectionDisabled)
+	assert.NoError(t, pc.Close())
+}
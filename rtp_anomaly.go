@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"sync"
+)
+
+// RTPAnomalyType is the kind of unexpected RTP header change reported by an RTPAnomaly.
+type RTPAnomalyType int
+
+const (
+	// RTPAnomalyTypeUnknown is the enum's zero-value.
+	RTPAnomalyTypeUnknown RTPAnomalyType = iota
+
+	// RTPAnomalyTypeSequenceJump means the sequence number moved, forwards or
+	// backwards, further than packet loss or reordering explain.
+	RTPAnomalyTypeSequenceJump
+
+	// RTPAnomalyTypeTimestampBackwards means the sequence number moved forwards
+	// while the RTP timestamp went backwards.
+	RTPAnomalyTypeTimestampBackwards
+
+	// RTPAnomalyTypeSSRCChange means a packet carried another SSRC than the
+	// previous packets of the track.
+	RTPAnomalyTypeSSRCChange
+)
+
+// This is done this way because of a linter.
+const (
+	rtpAnomalyTypeSequenceJumpStr       = "sequence-jump"
+	rtpAnomalyTypeTimestampBackwardsStr = "timestamp-backwards"
+	rtpAnomalyTypeSSRCChangeStr         = "ssrc-change"
+)
+
+func (t RTPAnomalyType) String() string {
+	switch t {
+	case RTPAnomalyTypeSequenceJump:
+		return rtpAnomalyTypeSequenceJumpStr
+	case RTPAnomalyTypeTimestampBackwards:
+		return rtpAnomalyTypeTimestampBackwardsStr
+	case RTPAnomalyTypeSSRCChange:
+		return rtpAnomalyTypeSSRCChangeStr
+	default:
+		return ErrUnknownType.Error()
+	}
+}
+
+// RTPAnomaly describes an unexpected change in the RTP headers of an incoming stream,
+// usually caused by a sender or encoder bug, or by injected packets. The previous
+// values are the ones of the last packet that was in order.
+type RTPAnomaly struct {
+	Type RTPAnomalyType
+
+	SSRC         SSRC
+	PreviousSSRC SSRC
+
+	SequenceNumber         uint16
+	PreviousSequenceNumber uint16
+
+	Timestamp         uint32
+	PreviousTimestamp uint32
+}
+
+// rtpAnomalyCounts holds the number of anomalies found per type.
+type rtpAnomalyCounts struct {
+	sequenceJumps       uint32
+	timestampsBackwards uint32
+	ssrcChanges         uint32
+}
+
+// rtpAnomalyDetector looks for anomalies in the headers of the packets of one track.
+// It keeps no per packet state besides the last in order packet, so checking a packet
+// doesn't allocate.
+type rtpAnomalyDetector struct {
+	mu sync.Mutex
+
+	started        bool
+	ssrc           SSRC
+	sequenceNumber uint16
+	timestamp      uint32
+
+	counts rtpAnomalyCounts
+}
+
+// check inspects the header of the marshaled RTP packet b and returns the anomaly found, if any.
+func (d *rtpAnomalyDetector) check(b []byte) (anomaly RTPAnomaly, found bool) {
+	if len(b) < rtpHeaderMinLength {
+		return anomaly, false
+	}
+
+	sequenceNumber := binary.BigEndian.Uint16(b[2:4])
+	timestamp := binary.BigEndian.Uint32(b[4:8])
+	ssrc := SSRC(binary.BigEndian.Uint32(b[8:12]))
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.started {
+		d.started = true
+		d.update(ssrc, sequenceNumber, timestamp)
+
+		return anomaly, false
+	}
+
+	anomaly = RTPAnomaly{
+		SSRC:                   ssrc,
+		PreviousSSRC:           d.ssrc,
+		SequenceNumber:         sequenceNumber,
+		PreviousSequenceNumber: d.sequenceNumber,
+		Timestamp:              timestamp,
+		PreviousTimestamp:      d.timestamp,
+	}
+
+	delta := int16(sequenceNumber - d.sequenceNumber)
+	switch {
+	case ssrc != d.ssrc:
+		anomaly.Type = RTPAnomalyTypeSSRCChange
+		d.counts.ssrcChanges++
+	case delta > rtpAnomalySequenceJumpThreshold || delta < -rtpAnomalySequenceJumpThreshold:
+		anomaly.Type = RTPAnomalyTypeSequenceJump
+		d.counts.sequenceJumps++
+	case delta <= 0:
+		// Duplicated, retransmitted or reordered packet, nothing to compare against.
+		return anomaly, false
+	case int32(timestamp-d.timestamp) < 0:
+		anomaly.Type = RTPAnomalyTypeTimestampBackwards
+		d.counts.timestampsBackwards++
+	default:
+		d.update(ssrc, sequenceNumber, timestamp)
+
+		return anomaly, false
+	}
+
+	// Follow the new values, so a stream that restarted is reported once.
+	d.update(ssrc, sequenceNumber, timestamp)
+
+	return anomaly, true
+}
+
+func (d *rtpAnomalyDetector) update(ssrc SSRC, sequenceNumber uint16, timestamp uint32) {
+	d.ssrc = ssrc
+	d.sequenceNumber = sequenceNumber
+	d.timestamp = timestamp
+}
+
+func (d *rtpAnomalyDetector) getCounts() rtpAnomalyCounts {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.counts
+}
+
+// OnAnomaly sets an event handler which is invoked when an unexpected change in the RTP
+// headers of the track is found: a large sequence number jump, a timestamp going backwards
+// or an SSRC change. Packets are checked when they are read, the totals are also reported by
+// the InboundRTPStreamStats of the track.
+func (t *TrackRemote) OnAnomaly(f func(RTPAnomaly)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onAnomalyHandler = f
+}
+
+func (t *TrackRemote) detectAnomaly(b []byte) {
+	anomaly, found := t.anomalyDetector.check(b)
+	if !found {
+		return
+	}
+
+	t.mu.RLock()
+	handler := t.onAnomalyHandler
+	t.mu.RUnlock()
+
+	if handler != nil {
+		go handler(anomaly)
+	}
+}
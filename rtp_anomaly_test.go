@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRTPAnomalyType_String(t *testing.T) {
+	testCases := []struct {
+		anomalyType    RTPAnomalyType
+		expectedString string
+	}{
+		{RTPAnomalyTypeUnknown, ErrUnknownType.Error()},
+		{RTPAnomalyTypeSequenceJump, "sequence-jump"},
+		{RTPAnomalyTypeTimestampBackwards, "timestamp-backwards"},
+		{RTPAnomalyTypeSSRCChange, "ssrc-change"},
+	}
+
+	for i, testCase := range testCases {
+		assert.Equal(t, testCase.expectedString, testCase.anomalyType.String(), "testCase: %d %v", i, testCase)
+	}
+}
+
+func TestRTPAnomalyDetector(t *testing.T) {
+	marshal := func(ssrc uint32, sequenceNumber uint16, timestamp uint32) []byte {
+		b, err := (&rtp.Packet{Header: rtp.Header{
+			Version:        2,
+			SSRC:           ssrc,
+			SequenceNumber: sequenceNumber,
+			Timestamp:      timestamp,
+		}}).Marshal()
+		require.NoError(t, err)
+
+		return b
+	}
+
+	detector := &rtpAnomalyDetector{}
+	for i, testCase := range []struct {
+		packet      []byte
+		anomalyType RTPAnomalyType
+	}{
+		{marshal(1, 65534, 1000), RTPAnomalyTypeUnknown},
+		{marshal(1, 65535, 2000), RTPAnomalyTypeUnknown},
+		{marshal(1, 10, 3000), RTPAnomalyTypeUnknown}, // wraparound and loss
+		{marshal(1, 8, 2500), RTPAnomalyTypeUnknown},  // reordered
+		{marshal(1, 10, 3000), RTPAnomalyTypeUnknown}, // duplicated
+		{marshal(1, 11, 2000), RTPAnomalyTypeTimestampBackwards},
+		{marshal(1, 12, 4294967000), RTPAnomalyTypeTimestampBackwards},
+		{marshal(1, 13, 100), RTPAnomalyTypeUnknown}, // timestamp wraparound
+		{marshal(1, 20000, 200), RTPAnomalyTypeSequenceJump},
+		{marshal(1, 20001, 300), RTPAnomalyTypeUnknown},
+		{marshal(1, 10000, 300), RTPAnomalyTypeSequenceJump},
+		{marshal(2, 10001, 400), RTPAnomalyTypeSSRCChange},
+		{marshal(2, 10002, 500), RTPAnomalyTypeUnknown},
+		{[]byte{0x80, 0x60}, RTPAnomalyTypeUnknown},
+	} {
+		anomaly, found := detector.check(testCase.packet)
+		assert.Equal(t, testCase.anomalyType != RTPAnomalyTypeUnknown, found, "testCase: %d", i)
+		assert.Equal(t, testCase.anomalyType, anomaly.Type, "testCase: %d", i)
+	}
+
+	assert.Equal(t, rtpAnomalyCounts{
+		sequenceJumps:       2,
+		timestampsBackwards: 2,
+		ssrcChanges:         1,
+	}, detector.getCounts())
+
+	packet := marshal(2, 10003, 600)
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		detector.check(packet)
+	}))
+}
+
+func TestTrackRemote_OnAnomaly(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	track, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	require.NoError(t, err)
+	_, err = pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	anomalies := make(chan RTPAnomaly, 2)
+	remoteTracks := make(chan *TrackRemote, 1)
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	pcAnswer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		trackRemote.OnAnomaly(func(anomaly RTPAnomaly) {
+			anomalies <- anomaly
+		})
+		remoteTracks <- trackRemote
+		onTrackFiredFunc()
+
+		for {
+			if _, _, readErr := trackRemote.ReadRTP(); readErr != nil {
+				return
+			}
+		}
+	})
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	writePacket := func(sequenceNumber uint16, timestamp uint32) {
+		assert.NoError(t, track.WriteRTP(&rtp.Packet{
+			Header:  rtp.Header{Version: 2, SequenceNumber: sequenceNumber, Timestamp: timestamp},
+			Payload: []byte{0x00},
+		}))
+	}
+
+	sequenceNumber := uint16(0)
+	for {
+		select {
+		case <-time.After(20 * time.Millisecond):
+			sequenceNumber++
+			writePacket(sequenceNumber, uint32(sequenceNumber)*3000)
+
+			continue
+		case <-onTrackFired.Done():
+		}
+
+		break
+	}
+
+	writePacket(sequenceNumber+5000, uint32(sequenceNumber+5000)*3000)
+	writePacket(sequenceNumber+5001, 0)
+
+	// Handlers run in their own goroutine, so they may be invoked out of order
+	found := map[RTPAnomalyType]RTPAnomaly{}
+	for i := 0; i < 2; i++ {
+		anomaly := <-anomalies
+		found[anomaly.Type] = anomaly
+	}
+	assert.Equal(t, sequenceNumber+5000, found[RTPAnomalyTypeSequenceJump].SequenceNumber)
+	assert.Equal(t, uint32(sequenceNumber+5000)*3000, found[RTPAnomalyTypeTimestampBackwards].PreviousTimestamp)
+
+	stats, ok := pcAnswer.GetStats().GetInboundRTPStreamStats(<-remoteTracks)
+	require.True(t, ok)
+	assert.Equal(t, StatsTypeInboundRTP, stats.Type)
+	assert.Equal(t, "video", stats.Kind)
+	assert.Equal(t, uint32(1), stats.SequenceJumps)
+	assert.Equal(t, uint32(1), stats.TimestampsBackwards)
+	assert.Zero(t, stats.SSRCChanges)
+
+	// The standard counters are filled too, the jump counts as lost packets
+	assert.NotZero(t, stats.PacketsReceived)
+	assert.Equal(t, uint64(stats.PacketsReceived), stats.BytesReceived)
+	assert.GreaterOrEqual(t, stats.HeaderBytesReceived, uint64(stats.PacketsReceived)*rtpHeaderMinLength)
+	assert.GreaterOrEqual(t, stats.PacketsLost, int32(4999))
+	assert.NotZero(t, stats.LastPacketReceivedTimestamp)
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
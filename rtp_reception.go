@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// rtpReceptionCounts holds the standard reception counters of an inbound RTP stream.
+type rtpReceptionCounts struct {
+	packetsReceived     uint32
+	packetsLost         int32
+	bytesReceived       uint64
+	headerBytesReceived uint64
+	lastPacketReceived  time.Time
+}
+
+// rtpReceptionCounter counts the packets of a track and estimates the packets lost from the
+// extended highest sequence number, RFC 3550 appendix A.3. It keeps the totals across SSRC
+// changes, the sequence numbers restart with the new SSRC.
+type rtpReceptionCounter struct {
+	mu sync.Mutex
+
+	started        bool
+	ssrc           SSRC
+	baseSequence   uint16
+	maxSequence    uint16
+	cycles         uint32
+	ssrcReceived   uint32 // packets received since the last SSRC change
+	previousLosses int32
+
+	counts rtpReceptionCounts
+}
+
+// update adds the marshaled RTP packet b, which arrived at now.
+func (c *rtpReceptionCounter) update(b []byte, now time.Time) {
+	headerSize, paddingSize, ok := rtpHeaderAndPaddingSize(b)
+	if !ok {
+		return
+	}
+
+	sequenceNumber := binary.BigEndian.Uint16(b[2:4])
+	ssrc := SSRC(binary.BigEndian.Uint32(b[8:12]))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.started || ssrc != c.ssrc {
+		c.previousLosses = c.counts.packetsLost
+		c.started = true
+		c.ssrc = ssrc
+		c.baseSequence = sequenceNumber
+		c.maxSequence = sequenceNumber
+		c.cycles = 0
+		c.ssrcReceived = 0
+	} else if delta := sequenceNumber - c.maxSequence; delta != 0 && delta < 1<<15 {
+		if sequenceNumber < c.maxSequence {
+			c.cycles += 1 << 16
+		}
+		c.maxSequence = sequenceNumber
+	}
+
+	c.ssrcReceived++
+	expected := c.cycles + uint32(c.maxSequence) - uint32(c.baseSequence) + 1
+	c.counts.packetsLost = c.previousLosses + int32(expected-c.ssrcReceived) //nolint:gosec // G115
+
+	c.counts.packetsReceived++
+	c.counts.headerBytesReceived += uint64(headerSize + paddingSize)    //nolint:gosec // G115
+	c.counts.bytesReceived += uint64(len(b) - headerSize - paddingSize) //nolint:gosec // G115
+	c.counts.lastPacketReceived = now
+}
+
+func (c *rtpReceptionCounter) getCounts() rtpReceptionCounts {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.counts
+}
+
+// rtpHeaderAndPaddingSize returns the size of the header, CSRCs and extension included, and
+// of the padding of the marshaled RTP packet b, without unmarshaling it.
+func rtpHeaderAndPaddingSize(b []byte) (headerSize, paddingSize int, ok bool) {
+	if len(b) < rtpHeaderMinLength {
+		return 0, 0, false
+	}
+
+	headerSize = rtpHeaderMinLength + int(b[0]&0x0f)*4
+	if b[0]&0x10 != 0 {
+		if len(b) < headerSize+4 {
+			return 0, 0, false
+		}
+		headerSize += 4 + int(binary.BigEndian.Uint16(b[headerSize+2:headerSize+4]))*4
+	}
+	if b[0]&0x20 != 0 && len(b) > headerSize {
+		paddingSize = int(b[len(b)-1])
+	}
+	if headerSize+paddingSize > len(b) {
+		return 0, 0, false
+	}
+
+	return headerSize, paddingSize, true
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRTPReceptionCounter(t *testing.T) {
+	marshal := func(ssrc uint32, sequenceNumber uint16) []byte {
+		b, err := (&rtp.Packet{
+			Header:  rtp.Header{Version: 2, SSRC: ssrc, SequenceNumber: sequenceNumber},
+			Payload: []byte{0x00, 0x01, 0x02},
+		}).Marshal()
+		require.NoError(t, err)
+
+		return b
+	}
+
+	counter := rtpReceptionCounter{}
+	now := time.Unix(1700000000, 0)
+
+	// Wraps around, 0xfffe and 2 are lost, 0 is reordered
+	for _, sequenceNumber := range []uint16{0xfffc, 0xfffd, 0xffff, 1, 0, 3} {
+		counter.update(marshal(1, sequenceNumber), now)
+	}
+	counts := counter.getCounts()
+	assert.Equal(t, uint32(6), counts.packetsReceived)
+	assert.Equal(t, int32(2), counts.packetsLost)
+	assert.Equal(t, uint64(6*3), counts.bytesReceived)
+	assert.Equal(t, uint64(6*12), counts.headerBytesReceived)
+	assert.Equal(t, now, counts.lastPacketReceived)
+
+	// A new SSRC keeps the totals, 11 is lost
+	counter.update(marshal(2, 10), now.Add(time.Second))
+	counter.update(marshal(2, 12), now.Add(time.Second))
+	counts = counter.getCounts()
+	assert.Equal(t, uint32(8), counts.packetsReceived)
+	assert.Equal(t, int32(3), counts.packetsLost)
+	assert.Equal(t, now.Add(time.Second), counts.lastPacketReceived)
+
+	// Too short packets are ignored
+	counter.update([]byte{0x80}, now)
+	assert.Equal(t, uint32(8), counter.getCounts().packetsReceived)
+}
+
+func TestRTPHeaderAndPaddingSize(t *testing.T) {
+	packet := &rtp.Packet{
+		Header: rtp.Header{
+			Version: 2,
+			CSRC:    []uint32{1, 2},
+		},
+		Payload:     []byte{0x00, 0x01, 0x02, 0x03},
+		PaddingSize: 4,
+	}
+	packet.Padding = true
+	require.NoError(t, packet.SetExtension(1, []byte{0xaa, 0xbb}))
+	b, err := packet.Marshal()
+	require.NoError(t, err)
+
+	headerSize, paddingSize, ok := rtpHeaderAndPaddingSize(b)
+	assert.True(t, ok)
+	assert.Equal(t, packet.MarshalSize()-len(packet.Payload)-4, headerSize)
+	assert.Equal(t, 4, paddingSize)
+
+	_, _, ok = rtpHeaderAndPaddingSize(b[:14])
+	assert.False(t, ok)
+}
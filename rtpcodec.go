@@ -143,6 +143,32 @@ func codecParametersFuzzySearch(
 	return RTPCodecParameters{}, codecMatchNone
 }
 
+// codecFmtpCompatible returns true if the fmtp of current can be replaced by the fmtp of updated
+// without changing the codec configuration. For codecs with dedicated fmtp rules (H264, VP9 and AV1)
+// the configuration parameters must match, for the others only the RTX apt parameter must be kept.
+func codecFmtpCompatible(current, updated RTPCodecParameters) bool {
+	if !strings.EqualFold(current.MimeType, updated.MimeType) ||
+		!fmtp.ClockRateEqual(current.MimeType, current.ClockRate, updated.ClockRate) ||
+		!fmtp.ChannelsEqual(current.MimeType, current.Channels, updated.Channels) {
+		return false
+	}
+
+	currentFmtp := fmtp.Parse(current.MimeType, current.ClockRate, current.Channels, current.SDPFmtpLine)
+	updatedFmtp := fmtp.Parse(updated.MimeType, updated.ClockRate, updated.Channels, updated.SDPFmtpLine)
+
+	switch {
+	case strings.EqualFold(current.MimeType, MimeTypeH264),
+		strings.EqualFold(current.MimeType, MimeTypeVP9),
+		strings.EqualFold(current.MimeType, MimeTypeAV1):
+		return updatedFmtp.Match(currentFmtp)
+	default:
+		currentApt, _ := currentFmtp.Parameter("apt")
+		updatedApt, _ := updatedFmtp.Parameter("apt")
+
+		return currentApt == updatedApt
+	}
+}
+
 // Given a CodecParameters find the RTX CodecParameters if one exists.
 func findRTXPayloadType(needle PayloadType, haystack []RTPCodecParameters) PayloadType {
 	aptStr := fmt.Sprintf("apt=%d", needle)
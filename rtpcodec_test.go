@@ -83,3 +83,35 @@ func TestFindFECPayloadType(t *testing.T) {
 		assert.Equal(t, test.ResultPayloadType, findFECPayloadType(test.Haystack))
 	}
 }
+
+func TestCodecFmtpCompatible(t *testing.T) {
+	codec := func(mimeType, sdpFmtpLine string) RTPCodecParameters {
+		return RTPCodecParameters{
+			RTPCodecCapability: RTPCodecCapability{MimeType: mimeType, ClockRate: 90000, SDPFmtpLine: sdpFmtpLine},
+		}
+	}
+
+	for _, test := range []struct {
+		Current, Updated RTPCodecParameters
+		Compatible       bool
+	}{
+		{codec(MimeTypeVP8, ""), codec(MimeTypeVP8, "max-fr=30;max-fs=3600"), true},
+		{codec(MimeTypeVP8, "max-fr=30"), codec(MimeTypeVP8, "max-fr=15"), true},
+		{codec(MimeTypeVP8, ""), codec(MimeTypeVP9, ""), false},
+		{codec(MimeTypeRTX, "apt=96"), codec(MimeTypeRTX, "apt=96;rtx-time=3000"), true},
+		{codec(MimeTypeRTX, "apt=96"), codec(MimeTypeRTX, "apt=97"), false},
+		{
+			codec(MimeTypeH264, "level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=42e01f"),
+			codec(MimeTypeH264, "level-asymmetry-allowed=0;packetization-mode=1;profile-level-id=42e01f"),
+			true,
+		},
+		{
+			codec(MimeTypeH264, "level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=42e01f"),
+			codec(MimeTypeH264, "level-asymmetry-allowed=1;packetization-mode=0;profile-level-id=42e01f"),
+			false,
+		},
+		{codec(MimeTypeVP9, "profile-id=0"), codec(MimeTypeVP9, "profile-id=2"), false},
+	} {
+		assert.Equal(t, test.Compatible, codecFmtpCompatible(test.Current, test.Updated))
+	}
+}
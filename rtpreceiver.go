@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pion/interceptor"
@@ -70,6 +71,8 @@ type RTPReceiver struct {
 	api *API
 
 	rtxPool sync.Pool
+
+	bufferPolicy atomic.Value // trackBufferConfig
 }
 
 // NewRTPReceiver constructs a new RTPReceiver.
@@ -231,6 +234,7 @@ func (r *RTPReceiver) startReceive(parameters RTPReceiveParameters) error { //no
 		if streams.rtpReadStream, streams.rtpInterceptor, streams.rtcpReadStream, streams.rtcpInterceptor, err = r.transport.streamsForSSRC(parameters.Encodings[i].SSRC, *streams.streamInfo); err != nil {
 			return err
 		}
+		r.applyBufferPolicy(parameters.Encodings[i].SSRC)
 
 		if rtxSsrc := parameters.Encodings[i].RTX.SSRC; rtxSsrc != 0 {
 			streamInfo := createStreamInfo("", rtxSsrc, 0, 0, 0, 0, 0, codec, globalParams.HeaderExtensions)
@@ -272,7 +276,19 @@ func (r *RTPReceiver) Receive(parameters RTPReceiveParameters) error {
 func (r *RTPReceiver) Read(b []byte) (n int, a interceptor.Attributes, err error) {
 	select {
 	case <-r.received:
-		return r.tracks[0].rtcpInterceptor.Read(b, a)
+		for {
+			r.mu.RLock()
+			rtcpReadStream, rtcpInterceptor := r.tracks[0].rtcpReadStream, r.tracks[0].rtcpInterceptor
+			r.mu.RUnlock()
+
+			n, a, err = rtcpInterceptor.Read(b, a)
+			if err != nil && r.rtcpStreamChanged(r.tracks[0].track, rtcpReadStream) {
+				// The track was moved to a new SSRC, see changeSSRC
+				continue
+			}
+
+			return n, a, err
+		}
 	case <-r.closed:
 		return 0, nil, io.ErrClosedPipe
 	}
@@ -342,6 +358,16 @@ func (r *RTPReceiver) haveReceived() bool {
 	}
 }
 
+// isStopped returns true once Stop has been called.
+func (r *RTPReceiver) isStopped() bool {
+	select {
+	case <-r.closed:
+		return true
+	default:
+		return false
+	}
+}
+
 // Stop irreversibly stops the RTPReceiver.
 func (r *RTPReceiver) Stop() error { //nolint:cyclop
 	r.mu.Lock()
@@ -403,19 +429,53 @@ func (r *RTPReceiver) streamsForTrack(t *TrackRemote) *trackStreams {
 	return nil
 }
 
+// shouldDrain returns true if reads should keep consuming the buffered
+// packets of a stopped RTPReceiver instead of returning io.EOF immediately.
+func (r *RTPReceiver) shouldDrain() bool {
+	return r.api.settingEngine.receiverDrainOnStop && r.haveReceived()
+}
+
 // readRTP should only be called by a track, this only exists so we can keep state in one place.
+//
+// Once the RTPReceiver is stopped io.EOF is returned immediately, unless
+// SettingEngine.EnableReceiverDrainOnStop is set. In that case the packets that
+// were buffered before Stop are returned first and io.EOF is returned once the
+// buffer is empty.
 func (r *RTPReceiver) readRTP(b []byte, reader *TrackRemote) (n int, a interceptor.Attributes, err error) {
 	select {
 	case <-r.received:
+		select {
+		case <-r.closed:
+			if !r.shouldDrain() {
+				return 0, nil, io.EOF
+			}
+		default:
+		}
 	case <-r.closed:
-		return 0, nil, io.EOF
+		if !r.shouldDrain() {
+			return 0, nil, io.EOF
+		}
 	}
 
-	if t := r.streamsForTrack(reader); t != nil {
-		return t.rtpInterceptor.Read(b, a)
-	}
+	for {
+		r.mu.RLock()
+		t := r.streamsForTrack(reader)
+		if t == nil {
+			r.mu.RUnlock()
+
+			return 0, nil, fmt.Errorf("%w: %d", errRTPReceiverWithSSRCTrackStreamNotFound, reader.SSRC())
+		}
+		rtpReadStream, rtpInterceptor := t.rtpReadStream, t.rtpInterceptor
+		r.mu.RUnlock()
+
+		n, a, err = rtpInterceptor.Read(b, a)
+		if err != nil && r.rtpStreamChanged(reader, rtpReadStream) {
+			// The track was moved to a new SSRC, see changeSSRC
+			continue
+		}
 
-	return 0, nil, fmt.Errorf("%w: %d", errRTPReceiverWithSSRCTrackStreamNotFound, reader.SSRC())
+		return n, a, err
+	}
 }
 
 // receiveForRid is the sibling of Receive expect for RIDs instead of SSRCs
@@ -446,6 +506,7 @@ func (r *RTPReceiver) receiveForRid(
 			r.tracks[i].rtpInterceptor = rtpInterceptor
 			r.tracks[i].rtcpReadStream = rtcpReadStream
 			r.tracks[i].rtcpInterceptor = rtcpInterceptor
+			r.applyBufferPolicy(SSRC(streamInfo.SSRC))
 
 			return r.tracks[i].track, nil
 		}
@@ -454,6 +515,26 @@ func (r *RTPReceiver) receiveForRid(
 	return nil, fmt.Errorf("%w: %s", errRTPReceiverForRIDTrackStreamNotFound, rid)
 }
 
+// runningGoroutines returns the number of RTX read goroutines started by receiveForRtx
+// that are still running.
+func (r *RTPReceiver) runningGoroutines() int {
+	if r.isStopped() {
+		return 0
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	running := 0
+	for i := range r.tracks {
+		if r.tracks[i].repairStreamChannel != nil {
+			running++
+		}
+	}
+
+	return running
+}
+
 // receiveForRtx starts a routine that processes the repair stream.
 //
 //nolint:cyclop
@@ -469,6 +550,14 @@ func (r *RTPReceiver) receiveForRtx(
 	var track *trackStreams
 	if ssrc != 0 && len(r.tracks) == 1 {
 		track = &r.tracks[0]
+	} else if ssrc != 0 {
+		for i := range r.tracks {
+			if r.tracks[i].track.RtxSSRC() == ssrc {
+				track = &r.tracks[i]
+
+				break
+			}
+		}
 	} else {
 		for i := range r.tracks {
 			if r.tracks[i].track.RID() == rsid {
@@ -492,6 +581,7 @@ func (r *RTPReceiver) receiveForRtx(
 	track.repairRtcpReadStream = rtcpReadStream
 	track.repairRtcpInterceptor = rtcpInterceptor
 	track.repairStreamChannel = make(chan rtxPacketWithAttributes, 50)
+	r.applyBufferPolicy(SSRC(streamInfo.SSRC))
 
 	go func() {
 		for {
@@ -611,3 +701,58 @@ func (r *RTPReceiver) readRTX(reader *TrackRemote) *rtxPacketWithAttributes {
 
 	return nil
 }
+
+func inboundRTPStreamStatsID(ssrc SSRC) string {
+	return fmt.Sprintf("InboundRTPStream-%d", ssrc)
+}
+
+func (r *RTPReceiver) collectStats(collector *statsReportCollector) {
+	var mid string
+	if tr := r.RTPTransceiver(); tr != nil {
+		mid = tr.Mid()
+	}
+
+	for _, track := range r.Tracks() {
+		ssrc := track.SSRC()
+		if ssrc == 0 {
+			continue
+		}
+
+		collector.Collecting()
+
+		counts := track.anomalyDetector.getCounts()
+		reception := track.reception.getCounts()
+		keyFrameRequests := r.transport.keyFrameRequests.getCounts(uint32(ssrc))
+		stats := InboundRTPStreamStats{
+			Mid:                 mid,
+			Timestamp:           statsTimestampNow(),
+			Type:                StatsTypeInboundRTP,
+			ID:                  inboundRTPStreamStatsID(ssrc),
+			SSRC:                ssrc,
+			Kind:                track.Kind().String(),
+			PacketsReceived:     reception.packetsReceived,
+			PacketsLost:         reception.packetsLost,
+			BytesReceived:       reception.bytesReceived,
+			HeaderBytesReceived: reception.headerBytesReceived,
+			FIRCount:            keyFrameRequests.fir,
+			PLICount:            keyFrameRequests.pli,
+			SequenceJumps:       counts.sequenceJumps,
+			TimestampsBackwards: counts.timestampsBackwards,
+			SSRCChanges:         counts.ssrcChanges,
+			PacketsDuplicated:   track.duplicateFilter.getDuplicates(),
+
+			KeyFrameRequestsSuppressed: keyFrameRequests.suppressed,
+		}
+		if !reception.lastPacketReceived.IsZero() {
+			stats.LastPacketReceivedTimestamp = statsTimestampFrom(reception.lastPacketReceived)
+		}
+		stats.EstimatedClockSkew, _ = track.EstimatedClockSkew()
+		jitter, clockRate := track.jitter.get()
+		stats.Jitter = jitterSeconds(jitter, clockRate)
+		stats.JitterMilliseconds = stats.Jitter * 1000
+		stats.JitterTimestampUnits = jitter
+		stats.PacketsDroppedByBuffer = r.transport.droppedPackets(ssrc)
+
+		collector.Collect(stats.ID, stats)
+	}
+}
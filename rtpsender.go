@@ -10,12 +10,14 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pion/interceptor"
 	"github.com/pion/randutil"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
+	"github.com/pion/sdp/v3"
 	"github.com/pion/webrtc/v4/internal/util"
 )
 
@@ -25,11 +27,68 @@ type trackEncoding struct {
 	srtpStream *srtpWriterFuture
 
 	rtcpInterceptor interceptor.RTCPReader
+	rtcpInjection   *rtcpInjection
 	streamInfo      interceptor.StreamInfo
 
 	context *baseTrackLocalContext
 
 	ssrc, ssrcRTX, ssrcFEC SSRC
+
+	// bitrate is the bitrate the simulcast layer needs, paused is set while it doesn't fit
+	bitrate int
+	paused  atomic.Bool
+
+	sent rtpTransmissionCounter
+}
+
+// rtpTransmissionCounts holds the standard transmission counters of an outbound RTP stream.
+type rtpTransmissionCounts struct {
+	packetsSent              uint32
+	bytesSent                uint64
+	headerBytesSent          uint64
+	retransmittedPacketsSent uint64
+	retransmittedBytesSent   uint64
+	fecPacketsSent           uint32
+	lastPacketSent           time.Time
+}
+
+// rtpTransmissionCounter counts the packets of an encoding written to SRTP, after the
+// interceptors, so retransmissions and FEC are included.
+type rtpTransmissionCounter struct {
+	mu     sync.Mutex
+	counts rtpTransmissionCounts
+}
+
+// update adds a packet of the encoding sent on ssrc, its RTX and FEC streams use ssrcRTX and ssrcFEC.
+func (c *rtpTransmissionCounter) update(header *rtp.Header, payload []byte, ssrcRTX, ssrcFEC SSRC) {
+	paddingSize := 0
+	if header.Padding && len(payload) != 0 && int(payload[len(payload)-1]) <= len(payload) {
+		paddingSize = int(payload[len(payload)-1])
+	}
+	payloadSize := uint64(len(payload) - paddingSize) //nolint:gosec // G115
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.counts.packetsSent++
+	c.counts.bytesSent += payloadSize
+	c.counts.headerBytesSent += uint64(header.MarshalSize() + paddingSize) //nolint:gosec // G115
+	c.counts.lastPacketSent = time.Now()
+
+	switch ssrc := SSRC(header.SSRC); {
+	case ssrcRTX != 0 && ssrc == ssrcRTX:
+		c.counts.retransmittedPacketsSent++
+		c.counts.retransmittedBytesSent += payloadSize
+	case ssrcFEC != 0 && ssrc == ssrcFEC:
+		c.counts.fecPacketsSent++
+	}
+}
+
+func (c *rtpTransmissionCounter) getCounts() rtpTransmissionCounts {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.counts
 }
 
 // RTPSender allows an application to control how a given Track is encoded and transmitted to a remote peer.
@@ -53,6 +112,20 @@ type RTPSender struct {
 
 	rtpTransceiver *RTPTransceiver
 
+	maxBitrate, targetBitrate    int
+	onTargetBitrateChangeHandler func(bitrate int)
+
+	onActiveEncodingsChangeHandler func(rids []string)
+	activeEncodingsEvents          [][]string
+	activeEncodingsEventsRunning   bool
+
+	// streamID is the MediaStream the track is announced in, the StreamID of the track if empty
+	streamID string
+
+	dtmf *DTMFSender
+
+	headerExtensionProfile atomic.Int32 // RTPHeaderExtensionProfile
+
 	mu                     sync.RWMutex
 	sendCalled, stopCalled chan struct{}
 }
@@ -65,9 +138,14 @@ func (api *API) NewRTPSender(track TrackLocal, transport *DTLSTransport) (*RTPSe
 		return nil, errRTPSenderDTLSTransportNil
 	}
 
-	id, err := randutil.GenerateCryptoRandomString(32, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
-	if err != nil {
-		return nil, err
+	id := ""
+	if random := api.settingEngine.random; random != nil {
+		id = random.alpha(32)
+	} else {
+		var err error
+		if id, err = randutil.GenerateCryptoRandomString(32, randomSourceAlphabet); err != nil {
+			return nil, err
+		}
 	}
 
 	r := &RTPSender{
@@ -80,6 +158,9 @@ func (api *API) NewRTPSender(track TrackLocal, transport *DTLSTransport) (*RTPSe
 	}
 
 	r.addEncoding(track)
+	if r.kind == RTPCodecTypeAudio {
+		r.dtmf = newDTMFSender(r)
+	}
 
 	return r, nil
 }
@@ -201,15 +282,15 @@ func (r *RTPSender) AddEncoding(track TrackLocal) error { //nolint:cyclop
 func (r *RTPSender) addEncoding(track TrackLocal) {
 	trackEncoding := &trackEncoding{
 		track: track,
-		ssrc:  SSRC(util.RandUint32()),
+		ssrc:  SSRC(r.api.settingEngine.random.uint32()),
 	}
 
 	if r.api.mediaEngine.isRTXEnabled(r.kind, []RTPTransceiverDirection{RTPTransceiverDirectionSendonly}) {
-		trackEncoding.ssrcRTX = SSRC(util.RandUint32())
+		trackEncoding.ssrcRTX = SSRC(r.api.settingEngine.random.uint32())
 	}
 
 	if r.api.mediaEngine.isFECEnabled(r.kind, []RTPTransceiverDirection{RTPTransceiverDirectionSendonly}) {
-		trackEncoding.ssrcFEC = SSRC(util.RandUint32())
+		trackEncoding.ssrcFEC = SSRC(r.api.settingEngine.random.uint32())
 	}
 
 	r.trackEncodings = append(r.trackEncodings, trackEncoding)
@@ -229,7 +310,8 @@ func (r *RTPSender) Track() TrackLocal {
 
 // ReplaceTrack replaces the track currently being used as the sender's source with a new TrackLocal.
 // The new track must be of the same media kind (audio, video, etc) and switching the track should not
-// require negotiation.
+// require negotiation. The metadata of the new track is exposed through its TrackLocalContext, the
+// stream attributes seen by interceptors keep the metadata of the track that was sent first.
 func (r *RTPSender) ReplaceTrack(track TrackLocal) error { //nolint:cyclop
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -278,6 +360,8 @@ func (r *RTPSender) ReplaceTrack(track TrackLocal) error { //nolint:cyclop
 		ssrcFEC:         context.SSRCForwardErrorCorrection(),
 		writeStream:     context.WriteStream(),
 		rtcpInterceptor: context.RTCPReader(),
+		metadata:        trackLocalMetadata(track),
+		random:          r.api.settingEngine.random,
 	})
 	if err != nil {
 		// Re-bind the original track
@@ -312,7 +396,11 @@ func (r *RTPSender) Send(parameters RTPSendParameters) error {
 
 	for idx := range r.trackEncodings {
 		trackEncoding := r.trackEncodings[idx]
-		srtpStream := &srtpWriterFuture{ssrc: parameters.Encodings[idx].SSRC, rtpSender: r}
+		srtpStream := &srtpWriterFuture{
+			ssrc:          parameters.Encodings[idx].SSRC,
+			rtpSender:     r,
+			absSendTimeID: headerExtensionID(parameters.HeaderExtensions, sdp.ABSSendTimeURI),
+		}
 		writeStream := &interceptorToTrackLocalWriter{}
 		rtpParameters := r.api.mediaEngine.getRTPParametersByKind(
 			trackEncoding.track.Kind(),
@@ -323,23 +411,42 @@ func (r *RTPSender) Send(parameters RTPSendParameters) error {
 		trackEncoding.ssrc = parameters.Encodings[idx].SSRC
 		trackEncoding.ssrcRTX = parameters.Encodings[idx].RTX.SSRC
 		trackEncoding.ssrcFEC = parameters.Encodings[idx].FEC.SSRC
+		if r.api.settingEngine.rtcpInjection {
+			trackEncoding.rtcpInjection = newRTCPInjection(r.stopCalled)
+		}
 		trackEncoding.rtcpInterceptor = r.api.interceptor.BindRTCPReader(
 			interceptor.RTCPReaderFunc(
 				func(in []byte, a interceptor.Attributes) (n int, attributes interceptor.Attributes, err error) {
-					n, err = trackEncoding.srtpStream.Read(in)
+					readNetwork := func(b []byte) (int, error) {
+						return r.transport.readRTCP(trackEncoding.ssrc, b, trackEncoding.srtpStream.Read)
+					}
+					n, err = trackEncoding.rtcpInjection.read(in, r.api.settingEngine.getReceiveMTU(), readNetwork)
 
 					return n, a, err
 				},
 			),
 		)
+		var trackWriter TrackLocalWriter = writeStream
+		if idx == 0 && r.dtmf != nil && hasTelephoneEvent(rtpParameters.Codecs) {
+			trackWriter = r.dtmf.bindWriter(writeStream)
+		}
+		if r.api.settingEngine.simulcastLayerPausing != nil && len(r.trackEncodings) > 1 {
+			trackWriter = &simulcastLayerWriter{
+				TrackLocalWriter: trackWriter,
+				paused:           &trackEncoding.paused,
+				codecs:           rtpParameters.Codecs,
+			}
+		}
 		trackEncoding.context = &baseTrackLocalContext{
 			id:              r.id,
 			params:          rtpParameters,
 			ssrc:            parameters.Encodings[idx].SSRC,
 			ssrcFEC:         parameters.Encodings[idx].FEC.SSRC,
 			ssrcRTX:         parameters.Encodings[idx].RTX.SSRC,
-			writeStream:     writeStream,
+			writeStream:     trackWriter,
 			rtcpInterceptor: trackEncoding.rtcpInterceptor,
+			metadata:        trackLocalMetadata(trackEncoding.track),
+			random:          r.api.settingEngine.random,
 		}
 
 		codec, err := trackEncoding.track.Bind(trackEncoding.context)
@@ -359,11 +466,19 @@ func (r *RTPSender) Send(parameters RTPSendParameters) error {
 			codec.RTPCodecCapability,
 			parameters.HeaderExtensions,
 		)
+		if metadata := trackEncoding.context.metadata; metadata != nil {
+			trackEncoding.streamInfo.Attributes.Set(AttributeTrackMetadata, metadata)
+		}
 
 		rtpInterceptor := r.api.interceptor.BindLocalStream(
 			&trackEncoding.streamInfo,
 			interceptor.RTPWriterFunc(func(header *rtp.Header, payload []byte, _ interceptor.Attributes) (int, error) {
-				return srtpStream.WriteRTP(header, payload)
+				n, err := srtpStream.WriteRTP(r.applyHeaderExtensionProfile(header), payload)
+				if err == nil {
+					trackEncoding.sent.update(header, payload, trackEncoding.ssrcRTX, trackEncoding.ssrcFEC)
+				}
+
+				return n, err
 			}),
 		)
 
@@ -524,3 +639,61 @@ func (r *RTPSender) configureRTXAndFEC() {
 		}
 	}
 }
+
+func outboundRTPStreamStatsID(ssrc SSRC) string {
+	return fmt.Sprintf("OutboundRTPStream-%d", ssrc)
+}
+
+// collectStats reports an OutboundRTPStreamStats per encoding, the target bitrate of the
+// sender is reported by the first one.
+func (r *RTPSender) collectStats(collector *statsReportCollector) {
+	r.mu.RLock()
+	tr := r.rtpTransceiver
+	kind := r.kind
+	targetBitrate := r.targetBitrate
+	trackEncodings := append([]*trackEncoding{}, r.trackEncodings...)
+	r.mu.RUnlock()
+
+	var mid string
+	if tr != nil {
+		mid = tr.Mid()
+	}
+
+	for i, trackEncoding := range trackEncodings {
+		if trackEncoding.ssrc == 0 {
+			continue
+		}
+
+		collector.Collecting()
+
+		var rid string
+		if trackEncoding.track != nil {
+			rid = trackEncoding.track.RID()
+		}
+		sent := trackEncoding.sent.getCounts()
+		stats := OutboundRTPStreamStats{
+			Mid:                      mid,
+			Rid:                      rid,
+			Timestamp:                statsTimestampNow(),
+			Type:                     StatsTypeOutboundRTP,
+			ID:                       outboundRTPStreamStatsID(trackEncoding.ssrc),
+			SSRC:                     trackEncoding.ssrc,
+			Kind:                     kind.String(),
+			PacketsSent:              sent.packetsSent,
+			BytesSent:                sent.bytesSent,
+			HeaderBytesSent:          sent.headerBytesSent,
+			RetransmittedPacketsSent: sent.retransmittedPacketsSent,
+			RetransmittedBytesSent:   sent.retransmittedBytesSent,
+			FECPacketsSent:           sent.fecPacketsSent,
+			Active:                   !trackEncoding.paused.Load(),
+		}
+		if !sent.lastPacketSent.IsZero() {
+			stats.LastPacketSentTimestamp = statsTimestampFrom(sent.lastPacketSent)
+		}
+		if i == 0 {
+			stats.TargetBitrate = float64(targetBitrate)
+		}
+
+		collector.Collect(stats.ID, stats)
+	}
+}
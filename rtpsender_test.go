@@ -15,6 +15,8 @@ import (
 	"time"
 
 	"github.com/pion/interceptor"
+	mock_interceptor "github.com/pion/interceptor/pkg/mock"
+	"github.com/pion/rtp"
 	"github.com/pion/transport/v3/test"
 	"github.com/pion/webrtc/v4/pkg/media"
 	"github.com/stretchr/testify/assert"
@@ -541,3 +543,102 @@ func (s *TrackLocalCheckRTCPReaderOnBind) Bind(ctx TrackLocalContext) (RTPCodecP
 
 	return p, err
 }
+
+type TrackLocalCheckMetadataOnBind struct {
+	*TrackLocalStaticSample
+	t        *testing.T
+	metadata chan interface{}
+}
+
+func (s *TrackLocalCheckMetadataOnBind) Bind(ctx TrackLocalContext) (RTPCodecParameters, error) {
+	metadataCtx, ok := ctx.(TrackLocalContextWithMetadata)
+	assert.True(s.t, ok)
+	s.metadata <- metadataCtx.Metadata()
+
+	return s.TrackLocalStaticSample.Bind(ctx)
+}
+
+func Test_RTPSender_TrackMetadata(t *testing.T) {
+	type tenant struct{ id string }
+
+	streamMetadata := make(chan interface{}, 1)
+	ir := &interceptor.Registry{}
+	ir.Add(&mock_interceptor.Factory{
+		NewInterceptorFn: func(_ string) (interceptor.Interceptor, error) {
+			return &mock_interceptor.Interceptor{
+				BindLocalStreamFn: func(info *interceptor.StreamInfo, writer interceptor.RTPWriter) interceptor.RTPWriter {
+					streamMetadata <- info.Attributes.Get(AttributeTrackMetadata)
+
+					return writer
+				},
+			}, nil
+		},
+	})
+
+	track, err := NewTrackLocalStaticSample(
+		RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion", WithMetadata(&tenant{id: "tenant-a"}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, &tenant{id: "tenant-a"}, track.Metadata())
+
+	peerConnection, err := NewAPI(WithInterceptorRegistry(ir)).NewPeerConnection(Configuration{})
+	assert.NoError(t, err)
+
+	contextMetadata := make(chan interface{}, 1)
+	rtpSender, err := peerConnection.AddTrack(&TrackLocalCheckMetadataOnBind{
+		TrackLocalStaticSample: track,
+		t:                      t,
+		metadata:               contextMetadata,
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, rtpSender.Send(rtpSender.GetParameters()))
+	assert.Equal(t, &tenant{id: "tenant-a"}, <-contextMetadata)
+	assert.Equal(t, &tenant{id: "tenant-a"}, <-streamMetadata)
+
+	assert.NoError(t, peerConnection.Close())
+}
+
+func Test_RTPSender_OutboundRTPStreamStats(t *testing.T) {
+	lim := test.TimeOut(time.Second * 10)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+	rtpSender, err := pcOffer.AddTrack(track)
+	assert.NoError(t, err)
+
+	stats, ok := pcOffer.GetStats().GetOutboundRTPStreamStats(rtpSender)
+	assert.True(t, ok)
+	assert.Zero(t, stats.PacketsSent)
+
+	connected := untilConnectionState(PeerConnectionStateConnected, pcOffer, pcAnswer)
+	assert.NoError(t, signalPair(pcOffer, pcAnswer))
+	connected.Wait()
+
+	// Packets are only counted once the track is bound
+	for sequenceNumber := uint16(0); stats.PacketsSent < 10; sequenceNumber++ {
+		assert.NoError(t, track.WriteRTP(&rtp.Packet{
+			Header:  rtp.Header{Version: 2, SequenceNumber: sequenceNumber},
+			Payload: []byte{0x00, 0x01, 0x02},
+		}))
+		stats, _ = pcOffer.GetStats().GetOutboundRTPStreamStats(rtpSender)
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.Equal(t, StatsTypeOutboundRTP, stats.Type)
+	assert.Equal(t, "video", stats.Kind)
+	assert.True(t, stats.Active)
+	assert.Equal(t, uint64(stats.PacketsSent)*3, stats.BytesSent)
+	assert.GreaterOrEqual(t, stats.HeaderBytesSent, uint64(stats.PacketsSent)*rtpHeaderMinLength)
+	assert.Zero(t, stats.RetransmittedPacketsSent)
+	assert.NotZero(t, stats.LastPacketSentTimestamp)
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
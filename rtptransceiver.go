@@ -12,6 +12,7 @@ import (
 	"sync/atomic"
 
 	"github.com/pion/rtp"
+	"github.com/pion/sdp/v3"
 )
 
 // RTPTransceiver represents a combination of an RTPSender and an RTPReceiver that share a common mid.
@@ -22,7 +23,21 @@ type RTPTransceiver struct {
 	direction        atomic.Value // RTPTransceiverDirection
 	currentDirection atomic.Value // RTPTransceiverDirection
 
-	codecs []RTPCodecParameters // User provided codecs via SetCodecPreferences
+	codecs       []RTPCodecParameters   // User provided codecs via SetCodecPreferences
+	fmtpOverride map[PayloadType]string // User provided fmtp via SetCodecFmtp
+	rtcpFeedback []RTCPFeedback         // User provided feedback via SetRTCPFeedback
+
+	// Set once SetRTCPFeedback was called, even to reset the feedback
+	rtcpFeedbackSet bool
+
+	// RIDs of the sender accepted and rejected by the last negotiation
+	simulcastRIDs simulcastRIDs
+
+	// Fired when the transceiver changes in a way that needs a renegotiation
+	negotiationNeededHandler func()
+
+	// Set with SetRTCPDisabled
+	rtcpDisabled atomic.Bool
 
 	kind RTPCodecType
 
@@ -65,8 +80,180 @@ func (t *RTPTransceiver) SetCodecPreferences(codecs []RTPCodecParameters) error
 	return nil
 }
 
+// SetCodecFmtp replaces the fmtp line of the codec with the given payload type, e.g. to change
+// max-fr of VP8 or level-asymmetry-allowed of H264. The new fmtp must not change the codec
+// configuration (H264 profile and packetization-mode, VP9 and AV1 profile, RTX apt), use
+// SetCodecPreferences to switch codecs instead. The change is applied by the remote on the
+// next negotiation, OnNegotiationNeeded is fired if the transceiver belongs to a PeerConnection.
+func (t *RTPTransceiver) SetCodecFmtp(payloadType PayloadType, sdpFmtpLine string) error {
+	var current *RTPCodecParameters
+	codecs := t.getCodecs()
+	for i := range codecs {
+		if codecs[i].PayloadType == payloadType {
+			current = &codecs[i]
+
+			break
+		}
+	}
+
+	if current == nil {
+		return fmt.Errorf("%w: %d", errRTPTransceiverCodecNotFound, payloadType)
+	}
+
+	updated := *current
+	updated.SDPFmtpLine = sdpFmtpLine
+	if !codecFmtpCompatible(*current, updated) {
+		return fmt.Errorf("%w: %s", errRTPTransceiverFmtpIncompatible, sdpFmtpLine)
+	}
+
+	t.mu.Lock()
+	if t.fmtpOverride == nil {
+		t.fmtpOverride = map[PayloadType]string{}
+	}
+	t.fmtpOverride[payloadType] = sdpFmtpLine
+	handler := t.negotiationNeededHandler
+	t.mu.Unlock()
+
+	if handler != nil {
+		handler()
+	}
+
+	return nil
+}
+
+// SetRTCPFeedback restricts the RTCP feedback negotiated for the codecs of this transceiver to
+// the given types, overriding the feedback registered in the MediaEngine. A feedback type is
+// kept only if it is registered for the codec and in feedback with the same parameter, so
+// passing an empty slice disables every feedback, and passing only transport-cc disables NACK.
+// If feedback is nil we reset to the feedback of the MediaEngine. The change is applied on the
+// next negotiation, OnNegotiationNeeded is fired if the transceiver belongs to a PeerConnection.
+func (t *RTPTransceiver) SetRTCPFeedback(feedback []RTCPFeedback) {
+	t.mu.Lock()
+	if feedback != nil {
+		feedback = append([]RTCPFeedback{}, feedback...)
+	}
+	t.rtcpFeedback = feedback
+	t.rtcpFeedbackSet = true
+	handler := t.negotiationNeededHandler
+	t.mu.Unlock()
+
+	if handler != nil {
+		handler()
+	}
+}
+
+// rtcpFeedbackChanged returns true if the feedback of the codecs in the given local media
+// section differs from the feedback set with SetRTCPFeedback.
+func (t *RTPTransceiver) rtcpFeedbackChanged(media *sdp.MediaDescription) bool {
+	t.mu.RLock()
+	rtcpFeedbackSet := t.rtcpFeedbackSet
+	t.mu.RUnlock()
+
+	if !rtcpFeedbackSet {
+		return false
+	}
+
+	localCodecs, err := codecsFromMediaDescription(media)
+	if err != nil {
+		return false
+	}
+
+	for _, codec := range t.getCodecs() {
+		for _, localCodec := range localCodecs {
+			if localCodec.PayloadType != codec.PayloadType {
+				continue
+			}
+
+			if len(localCodec.RTCPFeedback) != len(codec.RTCPFeedback) ||
+				len(rtcpFeedbackIntersection(localCodec.RTCPFeedback, codec.RTCPFeedback)) != len(codec.RTCPFeedback) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// fmtpChanged returns true if a fmtp set with SetCodecFmtp is not in the given local media section yet.
+func (t *RTPTransceiver) fmtpChanged(media *sdp.MediaDescription) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if len(t.fmtpOverride) == 0 {
+		return false
+	}
+
+	codecs, err := codecsFromMediaDescription(media)
+	if err != nil {
+		return false
+	}
+
+	for _, codec := range codecs {
+		if sdpFmtpLine, ok := t.fmtpOverride[codec.PayloadType]; ok && codec.SDPFmtpLine != sdpFmtpLine {
+			return true
+		}
+	}
+
+	return false
+}
+
+// updateFmtpFromMediaSection applies the fmtp changes of a remote offer to the codecs
+// set with SetCodecPreferences, changes that would modify the codec configuration are ignored.
+func (t *RTPTransceiver) updateFmtpFromMediaSection(media *sdp.MediaDescription) {
+	remoteCodecs, err := codecsFromMediaDescription(media)
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Copy before updating, the codecs are owned by the caller of SetCodecPreferences
+	codecs := append([]RTPCodecParameters{}, t.codecs...)
+	for i := range codecs {
+		for _, remoteCodec := range remoteCodecs {
+			if codecs[i].PayloadType == remoteCodec.PayloadType && codecFmtpCompatible(codecs[i], remoteCodec) {
+				codecs[i].SDPFmtpLine = remoteCodec.SDPFmtpLine
+			}
+		}
+	}
+	t.codecs = codecs
+}
+
+func (t *RTPTransceiver) setNegotiationNeededHandler(f func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.negotiationNeededHandler = f
+}
+
 // Codecs returns list of supported codecs.
 func (t *RTPTransceiver) getCodecs() []RTPCodecParameters {
+	codecs := t.getPreferredCodecs()
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if len(t.fmtpOverride) == 0 && t.rtcpFeedback == nil {
+		return codecs
+	}
+
+	overridden := make([]RTPCodecParameters, len(codecs))
+	copy(overridden, codecs)
+	for i := range overridden {
+		if sdpFmtpLine, ok := t.fmtpOverride[overridden[i].PayloadType]; ok {
+			overridden[i].SDPFmtpLine = sdpFmtpLine
+		}
+		if t.rtcpFeedback != nil {
+			overridden[i].RTCPFeedback = rtcpFeedbackIntersection(overridden[i].RTCPFeedback, t.rtcpFeedback)
+		}
+	}
+
+	return overridden
+}
+
+// getPreferredCodecs returns the codecs of the MediaEngine filtered by SetCodecPreferences.
+func (t *RTPTransceiver) getPreferredCodecs() []RTPCodecParameters {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
@@ -175,6 +362,11 @@ func (t *RTPTransceiver) Stop() error {
 	t.setDirection(RTPTransceiverDirectionInactive)
 	t.setCurrentDirection(RTPTransceiverDirectionInactive)
 
+	// The SSRCs of a stopped transceiver are no longer filtered
+	if transport := t.transport(); transport != nil {
+		transport.setRTCPDisabled(t, false)
+	}
+
 	return nil
 }
 
@@ -10,7 +10,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pion/sdp/v3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_RTPTransceiver_SetCodecPreferences(t *testing.T) {
@@ -135,3 +137,67 @@ func Test_RTPTransceiver_SetCodecPreferences_PayloadType(t *testing.T) {
 
 	closePairNow(t, offerPC, answerPC)
 }
+
+func Test_RTPTransceiver_SetRTCPFeedback(t *testing.T) {
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	withoutNack, err := pcOffer.AddTransceiverFromKind(RTPCodecTypeVideo)
+	require.NoError(t, err)
+	withDefaults, err := pcOffer.AddTransceiverFromKind(RTPCodecTypeVideo)
+	require.NoError(t, err)
+
+	withoutNack.SetRTCPFeedback([]RTCPFeedback{{Type: TypeRTCPFBTransportCC}, {Type: TypeRTCPFBGoogREMB}})
+	for _, codec := range withoutNack.Sender().GetParameters().Codecs {
+		for _, feedback := range codec.RTCPFeedback {
+			assert.Contains(t, []string{TypeRTCPFBTransportCC, TypeRTCPFBGoogREMB}, feedback.Type)
+		}
+	}
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	rtcpFeedback := func(media *sdp.MediaDescription) (feedback []string) {
+		for _, attr := range media.Attributes {
+			if attr.Key == "rtcp-fb" {
+				feedback = append(feedback, strings.SplitN(attr.Value, " ", 2)[1])
+			}
+		}
+
+		return feedback
+	}
+
+	offer := pcOffer.LocalDescription()
+	assert.Contains(t, rtcpFeedback(getByMid(withoutNack.Mid(), offer)), "transport-cc ")
+	assert.NotContains(t, rtcpFeedback(getByMid(withoutNack.Mid(), offer)), "nack ")
+	assert.NotContains(t, rtcpFeedback(getByMid(withoutNack.Mid(), offer)), "nack pli")
+	assert.Contains(t, rtcpFeedback(getByMid(withDefaults.Mid(), offer)), "transport-cc ")
+	assert.Contains(t, rtcpFeedback(getByMid(withDefaults.Mid(), offer)), "nack ")
+	assert.Contains(t, rtcpFeedback(getByMid(withDefaults.Mid(), offer)), "nack pli")
+	assert.False(t, pcOffer.checkNegotiationNeeded())
+
+	negotiationNeeded := make(chan struct{}, 1)
+	pcOffer.OnNegotiationNeeded(func() {
+		negotiationNeeded <- struct{}{}
+	})
+
+	// An empty list disables every feedback
+	withoutNack.SetRTCPFeedback([]RTCPFeedback{})
+	<-negotiationNeeded
+	assert.True(t, pcOffer.checkNegotiationNeeded())
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	assert.Empty(t, rtcpFeedback(getByMid(withoutNack.Mid(), pcOffer.LocalDescription())))
+	assert.False(t, pcOffer.checkNegotiationNeeded())
+
+	// nil restores the feedback of the MediaEngine
+	withoutNack.SetRTCPFeedback(nil)
+	<-negotiationNeeded
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	assert.Equal(
+		t,
+		rtcpFeedback(getByMid(withDefaults.Mid(), pcOffer.LocalDescription())),
+		rtcpFeedback(getByMid(withoutNack.Mid(), pcOffer.LocalDescription())),
+	)
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package webrtc
+
+// SCTPAssociationState indicates the state of the SCTP association of an SCTPTransport,
+// as defined by RFC 9260 section 4. Only the states the SCTPTransport observes itself are
+// reported, the handshake and shutdown chunks exchanged by pion/sctp aren't.
+type SCTPAssociationState int
+
+const (
+	// SCTPAssociationStateUnknown is the enum's zero-value, the association is not started.
+	SCTPAssociationStateUnknown SCTPAssociationState = iota
+
+	// SCTPAssociationStateEstablished indicates the handshake completed, data can be sent
+	// and received on the DataChannels.
+	SCTPAssociationStateEstablished
+
+	// SCTPAssociationStateShutdownPending indicates the SCTPTransport is being stopped,
+	// by the PeerConnection Close or GracefulClose.
+	SCTPAssociationStateShutdownPending
+
+	// SCTPAssociationStateClosed indicates there is no association anymore, it was
+	// aborted locally or closed by the remote.
+	SCTPAssociationStateClosed
+)
+
+// This is done this way because of a linter.
+const (
+	sctpAssociationStateEstablishedStr     = "established"
+	sctpAssociationStateShutdownPendingStr = "shutdown-pending"
+	sctpAssociationStateClosedStr          = "closed"
+)
+
+func newSCTPAssociationState(raw string) SCTPAssociationState {
+	switch raw {
+	case sctpAssociationStateEstablishedStr:
+		return SCTPAssociationStateEstablished
+	case sctpAssociationStateShutdownPendingStr:
+		return SCTPAssociationStateShutdownPending
+	case sctpAssociationStateClosedStr:
+		return SCTPAssociationStateClosed
+	default:
+		return SCTPAssociationStateUnknown
+	}
+}
+
+func (s SCTPAssociationState) String() string {
+	switch s {
+	case SCTPAssociationStateEstablished:
+		return sctpAssociationStateEstablishedStr
+	case SCTPAssociationStateShutdownPending:
+		return sctpAssociationStateShutdownPendingStr
+	case SCTPAssociationStateClosed:
+		return sctpAssociationStateClosedStr
+	default:
+		return ErrUnknownType.Error()
+	}
+}
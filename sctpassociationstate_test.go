@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package webrtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSCTPAssociationState(t *testing.T) {
+	testCases := []struct {
+		associationStateString   string
+		expectedAssociationState SCTPAssociationState
+	}{
+		{ErrUnknownType.Error(), SCTPAssociationStateUnknown},
+		{"established", SCTPAssociationStateEstablished},
+		{"shutdown-pending", SCTPAssociationStateShutdownPending},
+		{"closed", SCTPAssociationStateClosed},
+	}
+
+	for i, testCase := range testCases {
+		assert.Equal(t,
+			testCase.expectedAssociationState,
+			newSCTPAssociationState(testCase.associationStateString),
+			"testCase: %d %v", i, testCase,
+		)
+	}
+}
+
+func TestSCTPAssociationState_String(t *testing.T) {
+	testCases := []struct {
+		associationState SCTPAssociationState
+		expectedString   string
+	}{
+		{SCTPAssociationStateUnknown, ErrUnknownType.Error()},
+		{SCTPAssociationStateEstablished, "established"},
+		{SCTPAssociationStateShutdownPending, "shutdown-pending"},
+		{SCTPAssociationStateClosed, "closed"},
+	}
+
+	for i, testCase := range testCases {
+		assert.Equal(t,
+			testCase.expectedString,
+			testCase.associationState.String(),
+			"testCase: %d %v", i, testCase,
+		)
+	}
+}
@@ -42,6 +42,11 @@ type SCTPTransport struct {
 	onErrorHandler func(error)
 	onCloseHandler func(error)
 
+	associationState                SCTPAssociationState
+	onAssociationStateChangeHandler func(SCTPAssociationState)
+	associationStateEvents          []SCTPAssociationState
+	associationStateEventsRunning   bool
+
 	sctpAssociation            *sctp.Association
 	onDataChannelHandler       func(*DataChannel)
 	onDataChannelOpenedHandler func(*DataChannel)
@@ -129,6 +134,7 @@ func (r *SCTPTransport) Start(capabilities SCTPCapabilities) error {
 	r.lock.Lock()
 	r.sctpAssociation = sctpAssociation
 	r.state = SCTPTransportStateConnected
+	r.setAssociationState(SCTPAssociationStateEstablished)
 	dataChannels := append([]*DataChannel{}, r.dataChannels...)
 	r.lock.Unlock()
 
@@ -162,10 +168,12 @@ func (r *SCTPTransport) Stop() error {
 		return nil
 	}
 
+	r.setAssociationState(SCTPAssociationStateShutdownPending)
 	r.sctpAssociation.Abort("")
 
 	r.sctpAssociation = nil
 	r.state = SCTPTransportStateClosed
+	r.setAssociationState(SCTPAssociationStateClosed)
 
 	return nil
 }
@@ -191,6 +199,10 @@ ACCEPT:
 			LoggerFactory: r.api.settingEngine.LoggerFactory,
 		}, dataChannels...)
 		if err != nil {
+			r.lock.Lock()
+			r.setAssociationState(SCTPAssociationStateClosed)
+			r.lock.Unlock()
+
 			if !errors.Is(err, io.EOF) {
 				r.log.Errorf("Failed to accept data channel: %v", err)
 				r.onError(err)
@@ -303,6 +315,59 @@ func (r *SCTPTransport) onClose(err error) {
 	}
 }
 
+// OnAssociationStateChange sets an event handler which is invoked when the state of the
+// SCTP association changes. The handler is called once per change, in order.
+func (r *SCTPTransport) OnAssociationStateChange(f func(SCTPAssociationState)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.onAssociationStateChangeHandler = f
+}
+
+// AssociationState returns the current state of the SCTP association.
+func (r *SCTPTransport) AssociationState() SCTPAssociationState {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.associationState
+}
+
+// setAssociationState must be called with the lock held.
+func (r *SCTPTransport) setAssociationState(state SCTPAssociationState) {
+	if r.associationState == state {
+		return
+	}
+	r.associationState = state
+
+	if r.onAssociationStateChangeHandler == nil {
+		return
+	}
+	r.associationStateEvents = append(r.associationStateEvents, state)
+	if !r.associationStateEventsRunning {
+		r.associationStateEventsRunning = true
+		go r.fireAssociationStateEvents()
+	}
+}
+
+func (r *SCTPTransport) fireAssociationStateEvents() {
+	for {
+		r.lock.Lock()
+		if len(r.associationStateEvents) == 0 {
+			r.associationStateEventsRunning = false
+			r.lock.Unlock()
+
+			return
+		}
+		state := r.associationStateEvents[0]
+		r.associationStateEvents = r.associationStateEvents[1:]
+		handler := r.onAssociationStateChangeHandler
+		r.lock.Unlock()
+
+		if handler != nil {
+			handler(state)
+		}
+	}
+}
+
 // OnDataChannel sets an event handler which is invoked when a data
 // channel message arrives from a remote peer.
 func (r *SCTPTransport) OnDataChannel(f func(*DataChannel)) {
@@ -367,7 +432,8 @@ func (r *SCTPTransport) MaxChannels() uint16 {
 	return *r.maxChannels
 }
 
-// State returns the current state of the SCTPTransport.
+// State returns the current state of the SCTPTransport. See AssociationState for the
+// state of the SCTP association itself.
 func (r *SCTPTransport) State() SCTPTransportState {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
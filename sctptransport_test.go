@@ -121,6 +121,58 @@ func TestSCTPTransportOnClose(t *testing.T) {
 	}
 }
 
+func TestSCTPTransportOnAssociationStateChange(t *testing.T) {
+	offerPC, answerPC, err := newPair()
+	require.NoError(t, err)
+
+	_, err = offerPC.CreateDataChannel(expectedLabel, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, SCTPAssociationStateUnknown, offerPC.SCTP().AssociationState())
+
+	offerStates := make(chan SCTPAssociationState, 4)
+	offerPC.SCTP().OnAssociationStateChange(func(state SCTPAssociationState) {
+		offerStates <- state
+	})
+	answerStates := make(chan SCTPAssociationState, 4)
+	answerPC.SCTP().OnAssociationStateChange(func(state SCTPAssociationState) {
+		answerStates <- state
+	})
+
+	nextState := func(states chan SCTPAssociationState) SCTPAssociationState {
+		select {
+		case state := <-states:
+			return state
+		case <-time.After(5 * time.Second):
+			assert.Fail(t, "timed out waiting for an association state")
+
+			return SCTPAssociationStateUnknown
+		}
+	}
+
+	require.NoError(t, signalPair(offerPC, answerPC))
+	assert.Equal(t, SCTPAssociationStateEstablished, nextState(offerStates))
+	assert.Equal(t, SCTPAssociationStateEstablished, nextState(answerStates))
+	assert.Equal(t, SCTPAssociationStateEstablished, offerPC.SCTP().AssociationState())
+
+	require.NoError(t, offerPC.Close())
+	assert.Equal(t, SCTPAssociationStateShutdownPending, nextState(offerStates))
+	assert.Equal(t, SCTPAssociationStateClosed, nextState(offerStates))
+	assert.Equal(t, SCTPAssociationStateClosed, offerPC.SCTP().AssociationState())
+
+	// The remote closes too, either from the abort or from the end of the DTLS transport
+	for state := nextState(answerStates); state != SCTPAssociationStateClosed; state = nextState(answerStates) {
+		assert.Equal(t, SCTPAssociationStateShutdownPending, state)
+	}
+
+	require.NoError(t, answerPC.Close())
+	select {
+	case state := <-offerStates:
+		assert.Failf(t, "unexpected association state", "%s", state)
+	default:
+	}
+}
+
 func TestSCTPTransportOutOfBandNegotiatedDataChannelDetach(t *testing.T) { //nolint:cyclop
 	// nolint:varnamelen
 	const N = 10
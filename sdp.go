@@ -30,6 +30,10 @@ type trackDetails struct {
 	ssrcs      []SSRC
 	repairSsrc *SSRC
 	rids       []string
+
+	// repairSsrcs holds the RTX SSRC of each entry in ssrcs when the
+	// track was declared with a legacy `a=ssrc-group:SIM`.
+	repairSsrcs []SSRC
 }
 
 func trackDetailsForSSRC(trackDetails []trackDetails, ssrc SSRC) *trackDetails {
@@ -87,10 +91,12 @@ func filterTrackWithSSRC(incomingTracks []trackDetails, ssrc SSRC) []trackDetail
 func trackDetailsFromSDP(
 	log logging.LeveledLogger,
 	s *sdp.SessionDescription,
+	parseSimulcastGroups bool,
 ) (incomingTracks []trackDetails) {
 	for _, media := range s.MediaDescriptions {
 		tracksInMediaSection := []trackDetails{}
 		rtxRepairFlows := map[uint64]uint64{}
+		simulcastGroups := [][]SSRC{}
 
 		// Plan B can have multiple tracks in a single media section
 		streamID := ""
@@ -147,6 +153,22 @@ func trackDetailsFromSDP(
 							}
 						}
 					}
+				} else if split[0] == sdpSemanticTokenSimulcast && parseSimulcastGroups {
+					// Lines like `a=ssrc-group:SIM 1111 2222 3333` declare the SSRCs of a legacy
+					// simulcast track, ordered from the lowest to the highest layer
+					group := make([]SSRC, 0, len(split)-1)
+					for _, value := range split[1:] {
+						ssrc, err := strconv.ParseUint(value, 10, 32)
+						if err != nil {
+							log.Warnf("Failed to parse SSRC: %v", err)
+
+							break
+						}
+						group = append(group, SSRC(ssrc))
+					}
+					if len(group) == len(split)-1 && len(group) > 1 {
+						simulcastGroups = append(simulcastGroups, group)
+					}
 				}
 
 			// Handle `a=msid:<stream_id> <track_label>` for Unified plan. The first value is the same as MediaStream.id
@@ -207,6 +229,10 @@ func trackDetailsFromSDP(
 			}
 		}
 
+		for _, group := range simulcastGroups {
+			tracksInMediaSection = mergeSimulcastGroup(tracksInMediaSection, group)
+		}
+
 		if rids := getRids(media); len(rids) != 0 && trackID != "" && streamID != "" {
 			simulcastTrack := trackDetails{
 				mid:      midValue,
@@ -228,6 +254,49 @@ func trackDetailsFromSDP(
 	return incomingTracks
 }
 
+// mergeSimulcastGroup folds the tracks of every SSRC in a SIM group into the
+// track of the first SSRC, keeping the RTX SSRC of each layer.
+func mergeSimulcastGroup(tracks []trackDetails, group []SSRC) []trackDetails {
+	base := trackDetailsForSSRC(tracks, group[0])
+	if base == nil {
+		return tracks
+	}
+
+	merged := *base
+	merged.ssrcs = make([]SSRC, 0, len(group))
+	merged.repairSsrcs = make([]SSRC, 0, len(group))
+	for _, ssrc := range group {
+		layer := trackDetailsForSSRC(tracks, ssrc)
+		if layer == nil {
+			continue
+		}
+
+		var repairSsrc SSRC
+		if layer.repairSsrc != nil {
+			repairSsrc = *layer.repairSsrc
+		}
+		merged.ssrcs = append(merged.ssrcs, ssrc)
+		merged.repairSsrcs = append(merged.repairSsrcs, repairSsrc)
+	}
+	merged.repairSsrc = nil
+
+	inGroup := map[SSRC]bool{}
+	for _, ssrc := range group {
+		inGroup[ssrc] = true
+	}
+
+	filtered := []trackDetails{}
+	for i := range tracks {
+		if tracks[i].ssrcs[0] == group[0] {
+			filtered = append(filtered, merged)
+		} else if !inGroup[tracks[i].ssrcs[0]] {
+			filtered = append(filtered, tracks[i])
+		}
+	}
+
+	return filtered
+}
+
 func trackDetailsToRTPReceiveParameters(trackDetails *trackDetails) RTPReceiveParameters {
 	encodingSize := len(trackDetails.ssrcs)
 	if len(trackDetails.rids) >= encodingSize {
@@ -243,7 +312,9 @@ func trackDetailsToRTPReceiveParameters(trackDetails *trackDetails) RTPReceivePa
 			encodings[i].SSRC = trackDetails.ssrcs[i]
 		}
 
-		if trackDetails.repairSsrc != nil {
+		if len(trackDetails.repairSsrcs) > i {
+			encodings[i].RTX.SSRC = trackDetails.repairSsrcs[i]
+		} else if trackDetails.repairSsrc != nil {
 			encodings[i].RTX.SSRC = *trackDetails.repairSsrc
 		}
 	}
@@ -398,6 +469,18 @@ func populateLocalCandidates(
 		}
 	}
 
+	// Without BUNDLE, every accepted media section carries the transport, see populateSDP
+	if extractBundleID(parsed) == "" {
+		for i, mediaDescr := range parsed.MediaDescriptions {
+			if i == 0 || mediaDescr.MediaName.Port.Value == 0 {
+				continue
+			}
+			if err = addCandidatesToMediaDescriptions(candidates, mediaDescr, iceGatheringState); err != nil {
+				return sessionDescription
+			}
+		}
+	}
+
 	sdp, err := parsed.Marshal()
 	if err != nil {
 		return sessionDescription
@@ -427,7 +510,19 @@ func addSenderSDP(
 			continue
 		}
 
+		streamID := sender.mediaStreamID(track)
 		sendParameters := sender.GetParameters()
+		if len(sendParameters.Encodings) > 1 && sender.api.settingEngine.simulcastSSRCGroups {
+			ssrcs := make([]string, 0, len(sendParameters.Encodings))
+			for _, encoding := range sendParameters.Encodings {
+				ssrcs = append(ssrcs, strconv.FormatUint(uint64(encoding.SSRC), 10))
+			}
+			media = media.WithValueAttribute(
+				sdp.AttrKeySSRCGroup,
+				sdpSemanticTokenSimulcast+" "+strings.Join(ssrcs, " "),
+			)
+		}
+
 		for _, encoding := range sendParameters.Encodings {
 			if encoding.RTX.SSRC != 0 {
 				media = media.WithValueAttribute("ssrc-group", fmt.Sprintf("FID %d %d", encoding.SSRC, encoding.RTX.SSRC))
@@ -438,8 +533,8 @@ func addSenderSDP(
 
 			media = media.WithMediaSource(
 				uint32(encoding.SSRC),
-				track.StreamID(), /* cname */
-				track.StreamID(), /* streamLabel */
+				streamID, /* cname */
+				streamID, /* streamLabel */
 				track.ID(),
 			)
 
@@ -447,21 +542,21 @@ func addSenderSDP(
 				if encoding.RTX.SSRC != 0 {
 					media = media.WithMediaSource(
 						uint32(encoding.RTX.SSRC),
-						track.StreamID(), /* cname */
-						track.StreamID(), /* streamLabel */
+						streamID, /* cname */
+						streamID, /* streamLabel */
 						track.ID(),
 					)
 				}
 				if encoding.FEC.SSRC != 0 {
 					media = media.WithMediaSource(
 						uint32(encoding.FEC.SSRC),
-						track.StreamID(), /* cname */
-						track.StreamID(), /* streamLabel */
+						streamID, /* cname */
+						streamID, /* streamLabel */
 						track.ID(),
 					)
 				}
 
-				media = media.WithPropertyAttribute("msid:" + track.StreamID() + " " + track.ID())
+				media = media.WithPropertyAttribute("msid:" + streamID + " " + track.ID())
 			}
 		}
 
@@ -508,6 +603,7 @@ func addTransceiverSDP(
 		WithICECredentials(iceParams.UsernameFragment, iceParams.Password).
 		WithPropertyAttribute(sdp.AttrKeyRTCPMux).
 		WithPropertyAttribute(sdp.AttrKeyRTCPRsize)
+	media.WithValueAttribute(sdpAttributeRTCP, muxedRTCPAttribute(media).String())
 
 	codecs := transceiver.getCodecs()
 	for _, codec := range codecs {
@@ -619,6 +715,10 @@ type mediaSection struct {
 	data            bool
 	matchExtensions map[string]int
 	rids            []*simulcastRid
+
+	// bundleOnly is set for the answer to a bundle-only media section, it is rejected
+	// unless the remote bundled it.
+	bundleOnly bool
 }
 
 func bundleMatchFromRemote(matchBundleGroup *string) func(mid string) bool {
@@ -670,6 +770,11 @@ func populateSDP(
 	bundleCount := 0
 
 	bundleMatch := bundleMatchFromRemote(matchBundleGroup)
+	// A remote that doesn't bundle at all still gets its media sections, they share the single
+	// transport negotiated from the first one and are demultiplexed by SSRC or MID extension.
+	// The remote expects a transport in every section, they all carry the candidates, see
+	// checkNonBundledTransports.
+	remoteBundles := matchBundleGroup == nil || strings.TrimSpace(*matchBundleGroup) != ""
 	appendBundle := func(midValue string) {
 		bundleValue += " " + midValue
 		bundleCount++
@@ -683,7 +788,7 @@ func populateSDP(
 		}
 
 		shouldAddID := true
-		shouldAddCandidates := i == 0
+		shouldAddCandidates := i == 0 || !remoteBundles
 		if section.data {
 			if err = addDataMediaSection(
 				descr,
@@ -720,7 +825,7 @@ func populateSDP(
 		if shouldAddID {
 			if bundleMatch(section.id) {
 				appendBundle(section.id)
-			} else {
+			} else if remoteBundles || section.bundleOnly {
 				descr.MediaDescriptions[len(descr.MediaDescriptions)-1].MediaName.Port = sdp.RangedPort{Value: 0}
 			}
 		}
@@ -759,7 +864,7 @@ func getMidValue(media *sdp.MediaDescription) string {
 }
 
 // SessionDescription contains a MediaSection with Multiple SSRCs, it is Plan-B.
-func descriptionIsPlanB(desc *SessionDescription, log logging.LeveledLogger) bool {
+func descriptionIsPlanB(desc *SessionDescription, log logging.LeveledLogger, parseSimulcastGroups bool) bool {
 	if desc == nil || desc.parsed == nil {
 		return false
 	}
@@ -767,7 +872,7 @@ func descriptionIsPlanB(desc *SessionDescription, log logging.LeveledLogger) boo
 	// Store all MIDs that already contain a track
 	midWithTrack := map[string]bool{}
 
-	for _, trackDetail := range trackDetailsFromSDP(log, desc.parsed) {
+	for _, trackDetail := range trackDetailsFromSDP(log, desc.parsed, parseSimulcastGroups) {
 		if _, ok := midWithTrack[trackDetail.mid]; ok {
 			return true
 		}
@@ -805,6 +910,8 @@ func getPeerDirection(media *sdp.MediaDescription) RTPTransceiverDirection {
 	return RTPTransceiverDirectionUnknown
 }
 
+// extractBundleID returns the mid of the media section carrying the transport of the BUNDLE
+// group, the first one of the group that isn't bundle-only.
 func extractBundleID(desc *sdp.SessionDescription) string {
 	groupAttribute, _ := desc.Attribute(sdp.AttrKeyGroup)
 
@@ -820,9 +927,34 @@ func extractBundleID(desc *sdp.SessionDescription) string {
 		return ""
 	}
 
+	for _, bundleID := range bundleIDs[1:] {
+		bundleOnly := false
+		for _, media := range desc.MediaDescriptions {
+			if getMidValue(media) == bundleID {
+				bundleOnly = isBundleOnly(media)
+
+				break
+			}
+		}
+		if bundleID != "" && !bundleOnly {
+			return bundleID
+		}
+	}
+
 	return bundleIDs[1]
 }
 
+// isBundleOnly reports whether the media section is a bundle-only one of RFC 8843 section 6:
+// its port is 0 and it has no transport of its own, it is only usable within a BUNDLE group.
+func isBundleOnly(media *sdp.MediaDescription) bool {
+	if media.MediaName.Port.Value != 0 {
+		return false
+	}
+	_, ok := media.Attribute(sdpAttributeBundleOnly)
+
+	return ok
+}
+
 func extractFingerprint(desc *sdp.SessionDescription) (string, string, error) { //nolint:gocognit,cyclop
 	fingerprint := ""
 
@@ -962,6 +1094,29 @@ func extractICEDetails(
 	return details, nil
 }
 
+// checkNonBundledTransports returns an error if desc doesn't bundle its media sections and
+// they don't share the ICE credentials ufrag, Pion has a single ICE transport and can't run
+// one per media section.
+func checkNonBundledTransports(desc *sdp.SessionDescription, ufrag string) error {
+	if extractBundleID(desc) != "" {
+		return nil
+	}
+	if _, ok := desc.Attribute("ice-ufrag"); ok {
+		return nil
+	}
+
+	for _, media := range desc.MediaDescriptions {
+		if media.MediaName.Port.Value == 0 {
+			continue
+		}
+		if mediaUfrag, ok := media.Attribute("ice-ufrag"); ok && mediaUfrag != ufrag {
+			return fmt.Errorf("%w: %s", errSDPNonBundledTransports, getMidValue(media))
+		}
+	}
+
+	return nil
+}
+
 // Select the first media section or the first bundle section
 // Currently Pion uses the first media section to gather candidates.
 // https://github.com/pion/webrtc/pull/2950
@@ -982,7 +1137,7 @@ func selectCandidateMediaSection(sessionDescription *sdp.SessionDescription) (
 					SDPMLineIndex:    uint16(mLineIndex), //nolint:gosec // G115
 				}, true
 			}
-		} else {
+		} else if !isBundleOnly(mediaDescr) {
 			// For not-bundled, take ICE details from the first media section
 			return &identifiedMediaDescription{
 				MediaDescription: mediaDescr,
@@ -10,6 +10,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -229,6 +230,34 @@ func TestExtractICEDetails(t *testing.T) {
 		assert.Equal(t, details.Password, defaultPwd)
 	})
 
+	t.Run("ice details skip bundle-only media section", func(t *testing.T) {
+		descr := &sdp.SessionDescription{
+			Attributes: []sdp.Attribute{
+				{Key: "group", Value: "BUNDLE 1 0"},
+			},
+			MediaDescriptions: []*sdp.MediaDescription{
+				{
+					Attributes: []sdp.Attribute{
+						{Key: "mid", Value: "0"},
+						{Key: "ice-ufrag", Value: defaultUfrag},
+						{Key: "ice-pwd", Value: defaultPwd},
+					},
+				},
+				{
+					Attributes: []sdp.Attribute{
+						{Key: "mid", Value: "1"},
+						{Key: "bundle-only"},
+					},
+				},
+			},
+		}
+
+		details, err := extractICEDetails(descr, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, details.Ufrag, defaultUfrag)
+		assert.Equal(t, details.Password, defaultPwd)
+	})
+
 	t.Run("ice details from first media section", func(t *testing.T) {
 		descr := &sdp.SessionDescription{
 			MediaDescriptions: []*sdp.MediaDescription{
@@ -422,7 +451,7 @@ func TestTrackDetailsFromSDP(t *testing.T) {
 			},
 		}
 
-		tracks := trackDetailsFromSDP(nil, descr)
+		tracks := trackDetailsFromSDP(nil, descr, false)
 		assert.Equal(t, 3, len(tracks))
 		if trackDetail := trackDetailsForSSRC(tracks, 1000); trackDetail != nil {
 			assert.Fail(t, "got the unknown track ssrc:1000 which should have been skipped")
@@ -477,7 +506,7 @@ func TestTrackDetailsFromSDP(t *testing.T) {
 				},
 			},
 		}
-		assert.Equal(t, 0, len(trackDetailsFromSDP(nil, descr)))
+		assert.Equal(t, 0, len(trackDetailsFromSDP(nil, descr, false)))
 	})
 
 	t.Run("ssrc-group after ssrc", func(t *testing.T) {
@@ -510,11 +539,66 @@ func TestTrackDetailsFromSDP(t *testing.T) {
 			},
 		}
 
-		tracks := trackDetailsFromSDP(nil, descr)
+		tracks := trackDetailsFromSDP(nil, descr, false)
 		assert.Equal(t, 2, len(tracks))
 		assert.Equal(t, SSRC(4000), *tracks[0].repairSsrc)
 		assert.Equal(t, SSRC(6000), *tracks[1].repairSsrc)
 	})
+
+	t.Run("legacy simulcast ssrc-group", func(t *testing.T) {
+		// Chrome style SDP with SIM and FID groups declared before the ssrc lines
+		descr := &sdp.SessionDescription{
+			MediaDescriptions: []*sdp.MediaDescription{
+				{
+					MediaName: sdp.MediaName{
+						Media: "video",
+					},
+					Attributes: []sdp.Attribute{
+						{Key: "mid", Value: "video"},
+						{Key: "sendrecv"},
+						{Key: "ssrc-group", Value: "SIM 1000 2000 3000"},
+						{Key: "ssrc-group", Value: "FID 1000 1001"},
+						{Key: "ssrc-group", Value: "FID 2000 2001"},
+						{Key: "ssrc-group", Value: "FID 3000 3001"},
+						{Key: "ssrc", Value: "1000 cname:sZzn6FCxFHDwH8rE"},
+						{Key: "ssrc", Value: "1000 msid:video_stream_id video_trk_id"},
+						{Key: "ssrc", Value: "1001 cname:sZzn6FCxFHDwH8rE"},
+						{Key: "ssrc", Value: "1001 msid:video_stream_id video_trk_id"},
+						{Key: "ssrc", Value: "2000 cname:sZzn6FCxFHDwH8rE"},
+						{Key: "ssrc", Value: "2000 msid:video_stream_id video_trk_id"},
+						{Key: "ssrc", Value: "2001 cname:sZzn6FCxFHDwH8rE"},
+						{Key: "ssrc", Value: "2001 msid:video_stream_id video_trk_id"},
+						{Key: "ssrc", Value: "3000 cname:sZzn6FCxFHDwH8rE"},
+						{Key: "ssrc", Value: "3000 msid:video_stream_id video_trk_id"},
+						{Key: "ssrc", Value: "3001 cname:sZzn6FCxFHDwH8rE"},
+						{Key: "ssrc", Value: "3001 msid:video_stream_id video_trk_id"},
+					},
+				},
+			},
+		}
+
+		tracks := trackDetailsFromSDP(nil, descr, false)
+		assert.Equal(t, 3, len(tracks))
+		for i, ssrc := range []SSRC{1000, 2000, 3000} {
+			assert.Equal(t, []SSRC{ssrc}, tracks[i].ssrcs)
+			assert.Equal(t, ssrc+1, *tracks[i].repairSsrc)
+		}
+
+		tracks = trackDetailsFromSDP(nil, descr, true)
+		assert.Equal(t, 1, len(tracks))
+		assert.Equal(t, "video", tracks[0].mid)
+		assert.Equal(t, "video_stream_id", tracks[0].streamID)
+		assert.Equal(t, "video_trk_id", tracks[0].id)
+		assert.Equal(t, []SSRC{1000, 2000, 3000}, tracks[0].ssrcs)
+		assert.Equal(t, []SSRC{1001, 2001, 3001}, tracks[0].repairSsrcs)
+
+		params := trackDetailsToRTPReceiveParameters(&tracks[0])
+		assert.Equal(t, 3, len(params.Encodings))
+		for i, ssrc := range []SSRC{1000, 2000, 3000} {
+			assert.Equal(t, ssrc, params.Encodings[i].SSRC)
+			assert.Equal(t, ssrc+1, params.Encodings[i].RTX.SSRC)
+		}
+	})
 }
 
 func TestHaveApplicationMediaSection(t *testing.T) {
@@ -1235,13 +1319,13 @@ a=sendrecv
 		t.Run(testCase.name, func(t *testing.T) {
 			checkRTXSupport := func(s *sdp.SessionDescription) {
 				// RTX is never enabled for audio
-				assert.Nil(t, trackDetailsFromSDP(nil, s)[0].repairSsrc)
+				assert.Nil(t, trackDetailsFromSDP(nil, s, false)[0].repairSsrc)
 
 				// RTX is conditionally enabled for video
 				if testCase.rtxExpected {
-					assert.NotNil(t, trackDetailsFromSDP(nil, s)[1].repairSsrc)
+					assert.NotNil(t, trackDetailsFromSDP(nil, s, false)[1].repairSsrc)
 				} else {
-					assert.Nil(t, trackDetailsFromSDP(nil, s)[1].repairSsrc)
+					assert.Nil(t, trackDetailsFromSDP(nil, s, false)[1].repairSsrc)
 				}
 			}
 
@@ -1311,3 +1395,50 @@ a=sendrecv
 		})
 	}
 }
+
+func Test_SSRC_Groups_Simulcast(t *testing.T) {
+	defer test.CheckRoutines(t)()
+
+	for _, enabled := range []bool{true, false} {
+		settingEngine := SettingEngine{}
+		settingEngine.EnableSimulcastSSRCGroups(enabled)
+
+		peerConnection, err := NewAPI(WithSettingEngine(settingEngine)).NewPeerConnection(Configuration{})
+		assert.NoError(t, err)
+
+		var sender *RTPSender
+		for i, rid := range []string{"a", "b", "c"} {
+			track, err := NewTrackLocalStaticRTP(
+				RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion", WithRTPStreamID(rid),
+			)
+			assert.NoError(t, err)
+
+			if i == 0 {
+				sender, err = peerConnection.AddTrack(track)
+			} else {
+				err = sender.AddEncoding(track)
+			}
+			assert.NoError(t, err)
+		}
+
+		offer, err := peerConnection.CreateOffer(nil)
+		assert.NoError(t, err)
+
+		encodings := sender.GetParameters().Encodings
+		expected := fmt.Sprintf("SIM %d %d %d", encodings[0].SSRC, encodings[1].SSRC, encodings[2].SSRC)
+		simulcastGroups := []string{}
+		for _, attr := range offer.parsed.MediaDescriptions[0].Attributes {
+			if attr.Key == sdp.AttrKeySSRCGroup && strings.HasPrefix(attr.Value, sdpSemanticTokenSimulcast) {
+				simulcastGroups = append(simulcastGroups, attr.Value)
+			}
+		}
+
+		if enabled {
+			assert.Equal(t, []string{expected}, simulcastGroups)
+		} else {
+			assert.Empty(t, simulcastGroups)
+		}
+
+		assert.NoError(t, peerConnection.Close())
+	}
+}
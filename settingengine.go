@@ -44,6 +44,7 @@ type SettingEngine struct {
 		ICEPrflxAcceptanceMinWait *time.Duration
 		ICERelayAcceptanceMinWait *time.Duration
 		ICESTUNGatherTimeout      *time.Duration
+		ICEGatherTimeout          *time.Duration
 	}
 	candidates struct {
 		ICELite                  bool
@@ -101,11 +102,29 @@ type SettingEngine struct {
 	disableMediaEngineCopy                    bool
 	disableMediaEngineMultipleCodecs          bool
 	srtpProtectionProfiles                    []dtls.SRTPProtectionProfile
+	srtpAuthTagLength                         uint
 	receiveMTU                                uint
 	iceMaxBindingRequests                     *uint16
+	iceRole                                   ICERole
 	fireOnTrackBeforeFirstRTP                 bool
 	disableCloseByDTLS                        bool
 	dataChannelBlockWrite                     bool
+	receiverDrainOnStop                       bool
+	simulcastSSRCGroups                       bool
+	dscp                                      *DSCP
+	bitrateAllocationPolicy                   BitrateAllocationPolicy
+	keyFrameRequestInterval                   time.Duration
+	maxSRTPPacketSize                         uint
+	maxSRTCPPacketSize                        uint
+	ssrcChangeRecovery                        bool
+	duplicatePacketWindow                     uint16
+	clockSkewEstimationWindow                 time.Duration
+	structuredLogger                          StructuredLogger
+	rtcpInjection                             bool
+	random                                    *randomSource
+	earlyICECandidateBufferSize               int
+	simulcastLayerPausing                     *SimulcastLayerPausing
+	iceCandidatePairChecksPollInterval        time.Duration
 }
 
 func (e *SettingEngine) getSCTPMaxMessageSize() uint32 {
@@ -125,6 +144,21 @@ func (e *SettingEngine) getReceiveMTU() uint {
 	return receiveMTU
 }
 
+// getMuxBufferSize returns the size of the buffer the datagrams are read into, the receive MTU.
+// It is raised to one byte more than a larger maximum packet size, so that a larger packet is
+// detected and counted instead of being truncated.
+func (e *SettingEngine) getMuxBufferSize() uint {
+	size := e.getReceiveMTU()
+	if e.maxSRTPPacketSize+1 > size {
+		size = e.maxSRTPPacketSize + 1
+	}
+	if e.maxSRTCPPacketSize+1 > size {
+		size = e.maxSRTCPPacketSize + 1
+	}
+
+	return size
+}
+
 // DetachDataChannels enables detaching data channels. When enabled
 // data channels have to be detached in the OnOpen callback using the
 // DataChannel.Detach method.
@@ -144,6 +178,27 @@ func (e *SettingEngine) SetSRTPProtectionProfiles(profiles ...dtls.SRTPProtectio
 	e.srtpProtectionProfiles = profiles
 }
 
+// SetSRTPAuthenticationTagLength sets the length in bits of the authentication tag of the
+// SRTP packets, 80 or 32, where the negotiated protection profile permits it. The tag length
+// is part of the AES-CM and NULL protection profiles, the profiles offered, the default ones
+// or those of SetSRTPProtectionProfiles, are replaced by their variant with the tag length.
+// With 32 bits they are also preferred over the AEAD profiles, whose tags are 128 bits, which
+// are kept as a fallback. A remote choosing a profile with another tag length fails the
+// DTLS transport.
+//
+// A 32 bits tag saves 6 bytes per packet at the cost of security: a forged packet is accepted
+// with a probability of 2^-32 instead of 2^-80, which RFC 3711 deems acceptable for media
+// only. SRTCP keeps an 80 bits tag whatever the profile.
+func (e *SettingEngine) SetSRTPAuthenticationTagLength(bits uint) error {
+	if bits != srtpAuthTagLength80 && bits != srtpAuthTagLength32 {
+		return errSettingEngineSRTPAuthTagLength
+	}
+
+	e.srtpAuthTagLength = bits
+
+	return nil
+}
+
 // SetICETimeouts sets the behavior around ICE Timeouts
 //
 // disconnectedTimeout:
@@ -190,6 +245,17 @@ func (e *SettingEngine) SetSTUNGatherTimeout(t time.Duration) {
 	e.timeout.ICESTUNGatherTimeout = &t
 }
 
+// SetICEGatherTimeout bounds the time the gathering of the candidates can take. The host,
+// server reflexive and relay candidates of every ICE server are gathered in parallel and each
+// candidate is emitted as soon as it is found, the timeout is then the time left to every
+// server to answer. Once it elapsed the gathering completes, OnICECandidate is called with nil
+// and GatheringCompletePromise is resolved, even if slow or unreachable servers are still
+// gathering, their candidates found later are not emitted. The per-request timeout of the
+// STUN servers is set with SetSTUNGatherTimeout.
+func (e *SettingEngine) SetICEGatherTimeout(t time.Duration) {
+	e.timeout.ICEGatherTimeout = &t
+}
+
 // SetEphemeralUDPPortRange limits the pool of ephemeral ports that
 // ICE UDP connections can allocate from. This affects both host candidates,
 // and the local address of server reflexive candidates.
@@ -291,6 +357,28 @@ func (e *SettingEngine) SetAnsweringDTLSRole(role DTLSRole) error {
 	return nil
 }
 
+// SetICERole forces the ICE role of the PeerConnection, whatever side offers and whether
+// either agent is lite. By default the offerer is controlling, unless only one of the agents
+// is lite, which is then controlled, see RFC 8445 section 6.1.1. ICERoleUnknown restores the
+// default. The role in use is returned by ICETransport.Role, the DTLS role still follows
+// the session descriptions.
+//
+// Forcing the role is meant for debugging and for deployments where both sides are known to
+// agree on it. When the remote takes the same role, the connectivity checks of both agents
+// conflict: pion/ice discards the checks of a remote claiming its role instead of switching
+// roles with the tie-breaker, so the connection fails. Such conflicts are reported by
+// PeerConnection.OnICERoleConflict when pion/ice hands the checks to the binding request
+// handler.
+func (e *SettingEngine) SetICERole(role ICERole) error {
+	if role != ICERoleUnknown && role != ICERoleControlling && role != ICERoleControlled {
+		return errSettingEngineSetICERole
+	}
+
+	e.iceRole = role
+
+	return nil
+}
+
 // SetNet sets the Net instance that is passed to pion/ice
 //
 // Net is an network interface layer for Pion, allowing users to replace
@@ -413,6 +501,25 @@ func (e *SettingEngine) SetReceiveMTU(receiveMTU uint) {
 	e.receiveMTU = receiveMTU
 }
 
+// SetMaxSRTPPacketSize sets the maximum size of the SRTP packets sent and received, after
+// encryption, so including the authentication tag. A received packet that is larger is
+// dropped, logged and counted by the OversizedPacketsReceived of the TransportStats, writing
+// a larger packet fails with ErrPacketTooLarge and is counted by OversizedPacketsSent.
+// Leave this 0 for the default, no maximum: packets of any size are sent, and received packets
+// larger than the receive MTU are truncated by the read buffers, as they always were.
+// The read buffers are enlarged to fit a larger size. The MTU of the path is usually lower:
+// packets larger than it are fragmented by IP, or lost.
+func (e *SettingEngine) SetMaxSRTPPacketSize(size uint) {
+	e.maxSRTPPacketSize = size
+}
+
+// SetMaxSRTCPPacketSize sets the maximum size of the SRTCP packets sent and received, after
+// encryption. A compound RTCP packet counts as a single packet. Leave this 0 for the default,
+// no maximum. See SetMaxSRTPPacketSize for how larger packets are handled.
+func (e *SettingEngine) SetMaxSRTCPPacketSize(size uint) {
+	e.maxSRTCPPacketSize = size
+}
+
 // SetDTLSRetransmissionInterval sets the retranmission interval for DTLS.
 func (e *SettingEngine) SetDTLSRetransmissionInterval(interval time.Duration) {
 	e.dtls.retransmissionInterval = interval
@@ -551,3 +658,162 @@ func (e *SettingEngine) SetFireOnTrackBeforeFirstRTP(fireOnTrackBeforeFirstRTP b
 func (e *SettingEngine) DisableCloseByDTLS(isEnabled bool) {
 	e.disableCloseByDTLS = isEnabled
 }
+
+// EnableReceiverDrainOnStop sets if packets buffered by an RTPReceiver can still be read
+// after it is stopped. When enabled, TrackRemote.Read and TrackRemote.ReadRTP return the
+// remaining buffered packets and then io.EOF once the buffer is empty. When disabled (the default)
+// io.EOF is returned immediately and any buffered packets are discarded.
+func (e *SettingEngine) EnableReceiverDrainOnStop(isEnabled bool) {
+	e.receiverDrainOnStop = isEnabled
+}
+
+// SetStructuredLogger sets a logging sink that receives the messages of every subsystem
+// with key/value fields: the subsystem that logged it and the PeerConnection it belongs
+// to, see StructuredLogger. When set it is used instead of LoggerFactory, which keeps
+// being used as before otherwise.
+func (e *SettingEngine) SetStructuredLogger(logger StructuredLogger) {
+	e.structuredLogger = logger
+}
+
+// EnableSSRCChangeRecovery sets if a remote track keeps being delivered when the remote
+// moves it to a new SSRC without renegotiating, e.g. after an encoder restart. An
+// undeclared SSRC whose MID matches a receiver with a single track without RID replaces
+// the SSRC of that track: TrackRemote reads continue with the new packets and
+// TrackRemote.OnSSRCChange is fired. RTX streams of the track are not rebound.
+//
+// Only the remote, which authenticates every packet with the SRTP keys, can trigger a
+// change, but it lets it replace the stream of a track that was declared in the SDP
+// without any signaling. A remote sending on both SSRCs makes the track flip between
+// them, each change drops the packets read to identify the new stream. It is disabled
+// by default.
+func (e *SettingEngine) EnableSSRCChangeRecovery(isEnabled bool) {
+	e.ssrcChangeRecovery = isEnabled
+}
+
+// SetDuplicatePacketWindow drops the incoming RTP packets whose sequence number was already
+// read on the same track among the last window sequence numbers, so that TrackRemote.Read
+// never returns the same packet twice. The window is rounded up to a power of two, at least
+// 64. Duplicates are found after RTX packets are unwrapped, so a retransmission of a packet
+// that was already received is dropped as well, they are counted in the PacketsDuplicated of
+// the InboundRTPStreamStats of the track. Packets older than the window are always returned.
+// Duplicates of the media stream itself are already dropped by SRTP replay protection, unless
+// it is disabled or its window is smaller.
+//
+// It is disabled by default, 0 disables it: forwarders usually relay packets as they come and
+// leave deduplication to the final receiver.
+func (e *SettingEngine) SetDuplicatePacketWindow(window uint16) {
+	e.duplicatePacketWindow = window
+}
+
+// SetClockSkewEstimationWindow enables the estimation of the clock skew of the sender of
+// each remote track over the given window, see TrackRemote.EstimatedClockSkew. A first
+// estimate is available after a quarter of the window. Longer windows are more accurate and
+// follow changes of the skew slower, a few minutes suit long sessions. It is disabled by
+// default, 0 disables it.
+func (e *SettingEngine) SetClockSkewEstimationWindow(window time.Duration) {
+	e.clockSkewEstimationWindow = window
+}
+
+// EnableSimulcastSSRCGroups sets if legacy `a=ssrc-group:SIM` lines are emitted and parsed.
+// When enabled, senders with multiple encodings announce them in a SIM group, and remote
+// media sections that carry a SIM group are received as a single simulcast track with one
+// encoding per SSRC instead of as separate Plan-B tracks.
+func (e *SettingEngine) EnableSimulcastSSRCGroups(isEnabled bool) {
+	e.simulcastSSRCGroups = isEnabled
+}
+
+// SetDSCP sets the DSCP value used to mark all the outbound packets of the PeerConnections,
+// e.g. DSCPExpeditedForwarding for a PeerConnection that only sends audio. Media and data are
+// bundled on the sockets of a single ICE transport, and the ICE agent owns the write path, so
+// packets can't be marked per traffic type: audio, video, data and the ICE and DTLS traffic all
+// carry this value. Use separate PeerConnections to mark traffic types differently.
+//
+// The marking is applied to the UDP sockets the ICE agent creates through the configured Net.
+// Sockets of a UDPMux or TCPMux provided by the user are not marked. Setting the IPv4 ToS is
+// supported on Linux, macOS and the BSDs, it fails with a logged warning on Windows where QoS
+// policies must be used instead. Networks may also rewrite or drop the markings.
+func (e *SettingEngine) SetDSCP(dscp DSCP) {
+	e.dscp = &dscp
+}
+
+// SetBitrateAllocationPolicy sets how PeerConnection.AllocateTargetBitrates splits the available
+// bitrate between the sending tracks. BitrateAllocationPolicyFair is used by default, use
+// BitrateAllocationPolicyAudioFirst to protect audio when the bandwidth drops. Max bitrates set
+// with RTPSender.SetMaxBitrate are honored by both policies.
+func (e *SettingEngine) SetBitrateAllocationPolicy(policy BitrateAllocationPolicy) {
+	e.bitrateAllocationPolicy = policy
+}
+
+// SetKeyFrameRequestInterval sets the minimum interval between two keyframe requests, PLI or
+// FIR, sent for the same media SSRC, e.g. 500ms. The requests that come earlier are dropped,
+// whether they are written by the application, with TrackRemote.RequestKeyFrame or
+// PeerConnection.WriteRTCP, or by an interceptor: the write sends the other packets and returns
+// ErrKeyFrameRequestSuppressed, and the dropped requests are counted by the
+// KeyFrameRequestsSuppressed of the InboundRTPStreamStats. This prevents keyframe storms that
+// crush the bitrate of the sender under loss. Keyframe requests are not limited by default, an
+// interval of 0 or less disables the limit.
+func (e *SettingEngine) SetKeyFrameRequestInterval(interval time.Duration) {
+	e.keyFrameRequestInterval = interval
+}
+
+// EnableRTCPInjectionForTesting allows RTPSender.InjectRTCP to feed crafted RTCP packets to the
+// senders as if they were received, to test how they react to loss or round trip times. It is
+// meant for tests only: while enabled the RTCP of every encoding is read from an extra goroutine.
+func (e *SettingEngine) EnableRTCPInjectionForTesting(enable bool) {
+	e.rtcpInjection = enable
+}
+
+// SetRandomSource sets the reader the random values of the PeerConnections that don't need to
+// be unpredictable are read from: the SSRCs, the first RTP sequence numbers and timestamps, and
+// the identifiers of the senders, of the streams and of the tracks of AddTransceiverFromKind.
+// Reading a seeded generator makes them reproducible in tests. Every PeerConnection seeds its
+// own generator from the reader when it is created: its values depend on the order the
+// PeerConnections are created in and on the order of its own senders and tracks, not on what
+// the other PeerConnections generate. MIDs are already sequential.
+// The package generator is used again if the reader fails, leave it nil for the default.
+//
+// Cryptographic material is still generated with crypto/rand: the certificates, the DTLS
+// randoms and keys, the SRTP keys and the ICE credentials, SetICECredentials sets fixed ones.
+func (e *SettingEngine) SetRandomSource(reader io.Reader) {
+	if reader == nil {
+		e.random = nil
+
+		return
+	}
+	e.random = &randomSource{reader: reader}
+}
+
+// SetSimulcastLayerPausing enables pausing the simulcast layers of the RTPSenders that their
+// target bitrate, allocated by PeerConnection.AllocateTargetBitrates, can't carry. The bitrate
+// every layer needs is declared with RTPSender.SetEncodingBitrate, see it for how the layers
+// are paused and resumed. Pausing is disabled by default, the target bitrate is then only
+// reported to the application.
+//
+// Layers are only paused and resumed by AllocateTargetBitrates: the bandwidth estimate of the
+// congestion controller isn't fed to it, the application must call it with the estimate, for
+// instance from the OnTargetBitrateChange handler of a cc.BandwidthEstimator. The paused layers
+// are reported by RTPSender.ActiveEncodings, OnActiveEncodingsChange and the Active member of
+// the OutboundRTPStreamStats, GetParameters doesn't reflect them.
+func (e *SettingEngine) SetSimulcastLayerPausing(pausing SimulcastLayerPausing) {
+	e.simulcastLayerPausing = &pausing
+}
+
+// SetICECandidatePairChecksPollInterval sets how often the connectivity checks of the candidate
+// pairs are polled for ICETransport.OnCandidatePairChecksSample: the checks between two polls
+// are merged in one sample, and the states a pair goes through between them are missed. Every
+// poll runs on the loop of the ICE agent, the checks are only polled while a handler is set.
+// The default interval is 250ms, leave this 0 for the default.
+func (e *SettingEngine) SetICECandidatePairChecksPollInterval(interval time.Duration) {
+	e.iceCandidatePairChecksPollInterval = interval
+}
+
+// SetEarlyICECandidateBufferSize sets how many candidates PeerConnection.AddICECandidate buffers
+// when it is called before the remote description is set, a common race when the candidates are
+// trickled on another signaling channel than the description. The buffered candidates are added
+// in order once SetRemoteDescription applied the description, those of another ICE generation
+// are then dropped like the late ones. Once the buffer is full AddICECandidate returns
+// ErrEarlyICECandidateBufferFull. By default the buffer size is 0 and AddICECandidate fails
+// with ErrNoRemoteDescription before the remote description, as in the WebRTC specification.
+func (e *SettingEngine) SetEarlyICECandidateBufferSize(size int) {
+	e.earlyICECandidateBufferSize = size
+}
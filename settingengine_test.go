@@ -8,16 +8,22 @@ package webrtc
 
 import (
 	"context"
+	"io"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/pion/datachannel"
+	"github.com/pion/dtls/v3"
 	"github.com/pion/dtls/v3/pkg/crypto/elliptic"
 	"github.com/pion/dtls/v3/pkg/protocol/handshake"
 	"github.com/pion/ice/v4"
+	"github.com/pion/srtp/v3"
 	"github.com/pion/stun/v3"
+	"github.com/pion/transport/v3/packetio"
 	"github.com/pion/transport/v3/test"
+	"github.com/pion/webrtc/v4/pkg/media"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -464,3 +470,153 @@ func TestEnableDataChannelBlockWrite(t *testing.T) {
 	assert.ErrorIs(t, err, context.DeadlineExceeded)
 	closePairNow(t, offer, answer)
 }
+
+func TestEnableReceiverDrainOnStop(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	var buffersLock sync.Mutex
+	buffers := map[uint32]*packetio.Buffer{}
+
+	s := SettingEngine{}
+	s.EnableReceiverDrainOnStop(true)
+	s.BufferFactory = func(packetType packetio.BufferPacketType, ssrc uint32) io.ReadWriteCloser {
+		buffer := packetio.NewBuffer()
+		buffer.SetLimitSize(1000 * 1000)
+		if packetType == packetio.RTPBufferPacket {
+			buffersLock.Lock()
+			buffers[ssrc] = buffer
+			buffersLock.Unlock()
+		}
+
+		return buffer
+	}
+
+	offer, answer, err := NewAPI(WithSettingEngine(s)).newPair(Configuration{})
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+
+	_, err = offer.AddTrack(track)
+	assert.NoError(t, err)
+
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	remoteTracks := make(chan *TrackRemote, 1)
+	answer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		onTrackFiredFunc()
+		remoteTracks <- trackRemote
+	})
+
+	assert.NoError(t, signalPair(offer, answer))
+	sendVideoUntilDone(t, onTrackFired.Done(), []*TrackLocalStaticSample{track})
+
+	trackRemote := <-remoteTracks
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, track.WriteSample(media.Sample{Data: []byte{0x00}, Duration: time.Millisecond * 20}))
+	}
+
+	// Wait until the packets are buffered, then stop the receiver before reading them
+	assert.Eventually(t, func() bool {
+		buffersLock.Lock()
+		defer buffersLock.Unlock()
+
+		buffer, ok := buffers[uint32(trackRemote.SSRC())]
+
+		return ok && buffer.Count() >= 5
+	}, time.Second*5, time.Millisecond*10)
+	assert.NoError(t, trackRemote.receiver.Stop())
+
+	drained := 0
+	for {
+		if _, _, err = trackRemote.ReadRTP(); err != nil {
+			break
+		}
+		drained++
+	}
+	assert.ErrorIs(t, err, io.EOF)
+	assert.GreaterOrEqual(t, drained, 5)
+
+	closePairNow(t, offer, answer)
+}
+
+func TestSetSRTPAuthenticationTagLength(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	s := SettingEngine{}
+	assert.ErrorIs(t, s.SetSRTPAuthenticationTagLength(64), errSettingEngineSRTPAuthTagLength)
+	assert.NoError(t, s.SetSRTPAuthenticationTagLength(32))
+
+	assert.Equal(t, []dtls.SRTPProtectionProfile{
+		dtls.SRTP_AES128_CM_HMAC_SHA1_32, dtls.SRTP_AEAD_AES_256_GCM, dtls.SRTP_AEAD_AES_128_GCM,
+	}, srtpProfilesWithAuthTagLength(defaultSrtpProtectionProfiles(), 32))
+	assert.Equal(t, []dtls.SRTPProtectionProfile{
+		dtls.SRTP_AEAD_AES_128_GCM, dtls.SRTP_AES128_CM_HMAC_SHA1_80, dtls.SRTP_NULL_HMAC_SHA1_80,
+	}, srtpProfilesWithAuthTagLength([]dtls.SRTPProtectionProfile{
+		dtls.SRTP_AEAD_AES_128_GCM, dtls.SRTP_AES128_CM_HMAC_SHA1_32,
+		dtls.SRTP_AES128_CM_HMAC_SHA1_80, dtls.SRTP_NULL_HMAC_SHA1_32,
+	}, 80))
+
+	offer, answer, err := NewAPI(WithSettingEngine(s)).newPair(Configuration{})
+	assert.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	assert.NoError(t, err)
+	_, err = offer.AddTrack(track)
+	assert.NoError(t, err)
+
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	answer.OnTrack(func(*TrackRemote, *RTPReceiver) {
+		onTrackFiredFunc()
+	})
+
+	assert.NoError(t, signalPair(offer, answer))
+	sendVideoUntilDone(t, onTrackFired.Done(), []*TrackLocalStaticSample{track})
+
+	offer.dtlsTransport.lock.RLock()
+	assert.Equal(t, srtp.ProtectionProfileAes128CmHmacSha1_32, offer.dtlsTransport.srtpProtectionProfile)
+	offer.dtlsTransport.lock.RUnlock()
+
+	closePairNow(t, offer, answer)
+}
+
+func TestSettingEngine_SetICERole(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	settingEngine := SettingEngine{}
+	assert.ErrorIs(t, settingEngine.SetICERole(ICERole(42)), errSettingEngineSetICERole)
+
+	newPeerConnection := func(role ICERole) *PeerConnection {
+		settingEngine := SettingEngine{}
+		assert.NoError(t, settingEngine.SetICERole(role))
+		pc, err := NewAPI(WithSettingEngine(settingEngine)).NewPeerConnection(Configuration{})
+		assert.NoError(t, err)
+		_, err = pc.CreateDataChannel("data", nil)
+		assert.NoError(t, err)
+
+		return pc
+	}
+
+	// The offerer is controlled and the answerer controlling
+	pcOffer, pcAnswer := newPeerConnection(ICERoleControlled), newPeerConnection(ICERoleControlling)
+
+	connected := untilConnectionState(PeerConnectionStateConnected, pcOffer, pcAnswer)
+	assert.NoError(t, signalPair(pcOffer, pcAnswer))
+	connected.Wait()
+
+	assert.Equal(t, ICERoleControlled, pcOffer.SCTP().Transport().ICETransport().Role())
+	assert.Equal(t, ICERoleControlling, pcAnswer.SCTP().Transport().ICETransport().Role())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
@@ -0,0 +1,235 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pion/rtp"
+)
+
+// SimulcastLayerPausing configures how the simulcast layers of the RTPSenders are paused when
+// their target bitrate drops, see SettingEngine.SetSimulcastLayerPausing.
+type SimulcastLayerPausing struct {
+	// Hysteresis is the fraction by which the target bitrate must exceed what a paused layer
+	// needs, with the layers below it, before it resumes, 0.2 resumes it at 120% of what it
+	// needs. It keeps a layer needing about the estimated bandwidth from flapping, 0 resumes
+	// layers as soon as they fit.
+	Hysteresis float64
+}
+
+// SetEncodingBitrate sets the bitrate, in bits per second, the simulcast layer rid needs. With
+// SettingEngine.SetSimulcastLayerPausing, the layers are taken from the one needing the least
+// bitrate to the one needing the most: the first one always stays active, the next ones stay
+// active as long as the target bitrate of the RTPSender covers what they and the layers before
+// them need. The others are paused, the highest first, as setting the active member of the
+// RTCRtpEncodingParameters to false would: the packets written to them are dropped before the
+// interceptors, and their sequence numbers stay contiguous when they resume. Resuming waits
+// for the hysteresis of the policy.
+//
+// As the stream has no gap, the remote doesn't notice the paused layer and doesn't request a
+// keyframe: the packets of a resumed VP8, VP9, AV1, H264 or H265 layer are dropped until the
+// first packet of a keyframe is written, the application should have its encoder produce one
+// when OnActiveEncodingsChange reports the layer. Pausing only applies to RTPSenders with
+// several encodings.
+//
+// A layer without a bitrate needs nothing and is paused only when a layer needing less is.
+// The bitrates take effect at the next PeerConnection.AllocateTargetBitrates.
+func (r *RTPSender) SetEncodingBitrate(rid string, bitrate int) error {
+	if bitrate < 0 {
+		return fmt.Errorf("%w: %d", errRTPSenderInvalidLayerBitrate, bitrate)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, encoding := range r.trackEncodings {
+		if encoding.track != nil && encoding.track.RID() == rid {
+			encoding.bitrate = bitrate
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", errRTPSenderNoTrackForRID, rid)
+}
+
+// ActiveEncodings returns the RIDs of the encodings that are not paused for the lack of
+// bitrate, see SetEncodingBitrate. Without simulcast the only encoding has an empty RID.
+func (r *RTPSender) ActiveEncodings() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.activeEncodings()
+}
+
+// OnActiveEncodingsChange sets an event handler which is invoked when simulcast layers of
+// this RTPSender are paused or resumed, with the RIDs of the active ones. The changes are
+// reported in order, from a single goroutine at a time.
+func (r *RTPSender) OnActiveEncodingsChange(f func(rids []string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onActiveEncodingsChangeHandler = f
+}
+
+func (r *RTPSender) activeEncodings() []string {
+	rids := []string{}
+	for _, encoding := range r.trackEncodings {
+		if encoding.paused.Load() {
+			continue
+		}
+
+		var rid string
+		if encoding.track != nil {
+			rid = encoding.track.RID()
+		}
+		rids = append(rids, rid)
+	}
+
+	return rids
+}
+
+// pauseSimulcastLayers pauses the simulcast layers target doesn't cover and resumes the ones
+// it covers again, when SettingEngine.SetSimulcastLayerPausing is set.
+func (r *RTPSender) pauseSimulcastLayers(target int) {
+	pausing := r.api.settingEngine.simulcastLayerPausing
+	if pausing == nil {
+		return
+	}
+
+	r.mu.Lock()
+	if len(r.trackEncodings) < 2 {
+		r.mu.Unlock()
+
+		return
+	}
+
+	layers := append([]*trackEncoding{}, r.trackEncodings...)
+	sort.SliceStable(layers, func(i, j int) bool {
+		return layers[i].bitrate < layers[j].bitrate
+	})
+
+	var changed bool
+	needed, fits := 0, true
+	for i, layer := range layers {
+		needed += layer.bitrate
+		paused := layer.paused.Load()
+		if i != 0 && fits {
+			threshold := float64(needed)
+			if paused {
+				threshold *= 1 + pausing.Hysteresis
+			}
+			fits = float64(target) >= threshold
+		}
+
+		if paused == fits {
+			layer.paused.Store(!fits)
+			changed = true
+		}
+	}
+
+	if changed && r.onActiveEncodingsChangeHandler != nil {
+		r.activeEncodingsEvents = append(r.activeEncodingsEvents, r.activeEncodings())
+		if !r.activeEncodingsEventsRunning {
+			r.activeEncodingsEventsRunning = true
+			go r.fireActiveEncodingsEvents()
+		}
+	}
+	r.mu.Unlock()
+}
+
+func (r *RTPSender) fireActiveEncodingsEvents() {
+	for {
+		r.mu.Lock()
+		if len(r.activeEncodingsEvents) == 0 {
+			r.activeEncodingsEventsRunning = false
+			r.mu.Unlock()
+
+			return
+		}
+		rids := r.activeEncodingsEvents[0]
+		r.activeEncodingsEvents = r.activeEncodingsEvents[1:]
+		handler := r.onActiveEncodingsChangeHandler
+		r.mu.Unlock()
+
+		if handler != nil {
+			handler(rids)
+		}
+	}
+}
+
+// simulcastLayerWriter drops the packets of a paused simulcast layer, and those of a resumed
+// one until its next keyframe, and shifts the sequence numbers of the next ones so that the
+// stream has no gap when it resumes.
+type simulcastLayerWriter struct {
+	TrackLocalWriter
+
+	paused *atomic.Bool
+	codecs []RTPCodecParameters
+
+	waitKeyFrame atomic.Bool
+	dropped      atomic.Uint32
+}
+
+func (w *simulcastLayerWriter) WriteRTP(header *rtp.Header, payload []byte) (int, error) {
+	if w.paused.Load() {
+		w.waitKeyFrame.Store(true)
+		w.dropped.Add(1)
+
+		return 0, nil
+	}
+
+	if w.waitKeyFrame.Load() {
+		mimeType := w.mimeType(PayloadType(header.PayloadType))
+		if detectsKeyFrames(mimeType) && !isFirstKeyFrameStart(&rtp.Packet{Header: *header, Payload: payload}, mimeType) {
+			w.dropped.Add(1)
+
+			return 0, nil
+		}
+		w.waitKeyFrame.Store(false)
+	}
+
+	// The header is shared by the bindings of the track
+	shifted := *header
+	shifted.SequenceNumber -= uint16(w.dropped.Load())
+
+	return w.TrackLocalWriter.WriteRTP(&shifted, payload)
+}
+
+// mimeType returns the MIME type of the codec of payloadType, empty if it isn't negotiated.
+func (w *simulcastLayerWriter) mimeType(payloadType PayloadType) string {
+	for _, codec := range w.codecs {
+		if codec.PayloadType == payloadType {
+			return codec.MimeType
+		}
+	}
+
+	return ""
+}
+
+// detectsKeyFrames reports whether isFirstKeyFrameStart detects the keyframes of mimeType.
+func detectsKeyFrames(mimeType string) bool {
+	for _, detected := range []string{MimeTypeVP8, MimeTypeVP9, MimeTypeAV1, MimeTypeH264, MimeTypeH265} {
+		if strings.EqualFold(mimeType, detected) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *simulcastLayerWriter) Write(b []byte) (int, error) {
+	packet := &rtp.Packet{}
+	if err := packet.Unmarshal(b); err != nil {
+		return 0, err
+	}
+
+	return w.WriteRTP(&packet.Header, packet.Payload)
+}
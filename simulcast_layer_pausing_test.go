@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRTPSender_SimulcastLayerPausing(t *testing.T) {
+	newSimulcastSender := func(t *testing.T, s SettingEngine) (*PeerConnection, *RTPSender) {
+		t.Helper()
+
+		pc, err := NewAPI(WithSettingEngine(s)).NewPeerConnection(Configuration{})
+		require.NoError(t, err)
+
+		var sender *RTPSender
+		for _, rid := range []string{"q", "h", "f"} {
+			track, trackErr := NewTrackLocalStaticRTP(
+				RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion", WithRTPStreamID(rid),
+			)
+			require.NoError(t, trackErr)
+
+			if sender == nil {
+				sender, err = pc.AddTrack(track)
+				require.NoError(t, err)
+			} else {
+				require.NoError(t, sender.AddEncoding(track))
+			}
+		}
+
+		require.NoError(t, sender.SetEncodingBitrate("q", 150000))
+		require.NoError(t, sender.SetEncodingBitrate("h", 500000))
+		require.NoError(t, sender.SetEncodingBitrate("f", 1500000))
+
+		return pc, sender
+	}
+
+	t.Run("pausing and resuming", func(t *testing.T) {
+		s := SettingEngine{}
+		s.SetSimulcastLayerPausing(SimulcastLayerPausing{Hysteresis: 0.2})
+		pc, sender := newSimulcastSender(t, s)
+
+		changes := make(chan []string, 1)
+		sender.OnActiveEncodingsChange(func(rids []string) {
+			changes <- rids
+		})
+
+		pc.AllocateTargetBitrates(3000000)
+		assert.Equal(t, []string{"q", "h", "f"}, sender.ActiveEncodings())
+
+		// The highest layer is paused first
+		pc.AllocateTargetBitrates(1000000)
+		assert.Equal(t, []string{"q", "h"}, <-changes)
+
+		// The lowest layer is never paused
+		pc.AllocateTargetBitrates(0)
+		assert.Equal(t, []string{"q"}, <-changes)
+
+		// A paused layer resumes once the bitrate exceeds what it needs by the hysteresis
+		pc.AllocateTargetBitrates(700000)
+		assert.Equal(t, []string{"q"}, sender.ActiveEncodings())
+		pc.AllocateTargetBitrates(800000)
+		assert.Equal(t, []string{"q", "h"}, <-changes)
+
+		// An active layer stays active until it doesn't fit anymore
+		pc.AllocateTargetBitrates(650000)
+		assert.Equal(t, []string{"q", "h"}, sender.ActiveEncodings())
+
+		pc.AllocateTargetBitrates(2600000)
+		assert.Equal(t, []string{"q", "h", "f"}, <-changes)
+
+		assert.NoError(t, pc.Close())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		pc, sender := newSimulcastSender(t, SettingEngine{})
+
+		pc.AllocateTargetBitrates(0)
+		assert.Equal(t, []string{"q", "h", "f"}, sender.ActiveEncodings())
+
+		assert.NoError(t, pc.Close())
+	})
+
+	t.Run("invalid bitrate", func(t *testing.T) {
+		pc, sender := newSimulcastSender(t, SettingEngine{})
+
+		assert.ErrorIs(t, sender.SetEncodingBitrate("q", -1), errRTPSenderInvalidLayerBitrate)
+		assert.ErrorIs(t, sender.SetEncodingBitrate("x", 1000), errRTPSenderNoTrackForRID)
+
+		assert.NoError(t, pc.Close())
+	})
+}
+
+type sequenceNumberWriter struct {
+	TrackLocalWriter
+
+	sequenceNumbers []uint16
+}
+
+func (w *sequenceNumberWriter) WriteRTP(header *rtp.Header, _ []byte) (int, error) {
+	w.sequenceNumbers = append(w.sequenceNumbers, header.SequenceNumber)
+
+	return 0, nil
+}
+
+func TestSimulcastLayerWriter(t *testing.T) {
+	var paused atomic.Bool
+	capture := &sequenceNumberWriter{}
+	writer := &simulcastLayerWriter{TrackLocalWriter: capture, paused: &paused}
+
+	write := func(sequenceNumber uint16) {
+		header := &rtp.Header{SequenceNumber: sequenceNumber}
+		_, err := writer.WriteRTP(header, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, sequenceNumber, header.SequenceNumber, "the header of the track is not modified")
+	}
+
+	write(65534)
+	paused.Store(true)
+	write(65535)
+	write(0)
+	paused.Store(false)
+	write(1)
+
+	raw, err := (&rtp.Packet{Header: rtp.Header{Version: 2, SequenceNumber: 2}}).Marshal()
+	require.NoError(t, err)
+	_, err = writer.Write(raw)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []uint16{65534, 65535, 0}, capture.sequenceNumbers)
+}
+
+func TestSimulcastLayerWriter_WaitKeyFrame(t *testing.T) {
+	var paused atomic.Bool
+	capture := &sequenceNumberWriter{}
+	writer := &simulcastLayerWriter{
+		TrackLocalWriter: capture,
+		paused:           &paused,
+		codecs: []RTPCodecParameters{{
+			RTPCodecCapability: RTPCodecCapability{MimeType: MimeTypeVP8, ClockRate: 90000},
+			PayloadType:        96,
+		}},
+	}
+
+	var (
+		keyFrame   = []byte{0x10, 0x00}
+		interFrame = []byte{0x10, 0x01}
+	)
+	write := func(sequenceNumber uint16, payload []byte) {
+		_, err := writer.WriteRTP(&rtp.Header{PayloadType: 96, SequenceNumber: sequenceNumber}, payload)
+		assert.NoError(t, err)
+	}
+
+	// Before the layer is paused the packets aren't checked
+	write(10, interFrame)
+	paused.Store(true)
+	write(11, interFrame)
+	paused.Store(false)
+
+	// The resumed layer is dropped until its next keyframe
+	write(12, interFrame)
+	write(13, keyFrame)
+	write(14, interFrame)
+
+	assert.Equal(t, []uint16{10, 11, 12}, capture.sequenceNumbers)
+}
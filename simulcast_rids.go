@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+
+	"github.com/pion/sdp/v3"
+)
+
+// simulcastRIDs holds the RIDs of the encodings of a sender, split by the negotiation.
+type simulcastRIDs struct {
+	accepted []string
+	rejected []string
+}
+
+// SimulcastRIDs returns the RIDs of the encodings of the sender that the remote peer accepted
+// to receive in the last negotiation, and the ones it rejected. Packets written to the track
+// of a rejected RID are still sent, but the remote ignores them, so encoding that layer is
+// wasted. Both are empty until a negotiation completes and when the sender has a single
+// encoding.
+//
+// The RIDs accepted are the ones listed in the receive direction of the a=simulcast attribute
+// of the remote media section, alternatives included, or its a=rid recv lines when it has
+// no a=simulcast. Paused RIDs, prefixed by "~", are accepted. If the remote media section
+// has neither, the remote doesn't support simulcast and every RID is rejected.
+func (t *RTPTransceiver) SimulcastRIDs() (accepted, rejected []string) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return append([]string{}, t.simulcastRIDs.accepted...), append([]string{}, t.simulcastRIDs.rejected...)
+}
+
+func (t *RTPTransceiver) setSimulcastRIDs(rids simulcastRIDs) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.simulcastRIDs = rids
+}
+
+// setRTPTransceiverSimulcastRIDs splits the RIDs of the senders of currentTransceivers by
+// the receive RIDs of the media sections of remote, the offer or answer of the remote peer.
+func setRTPTransceiverSimulcastRIDs(remote *SessionDescription, currentTransceivers []*RTPTransceiver) {
+	if remote == nil || remote.parsed == nil {
+		return
+	}
+
+	for _, transceiver := range currentTransceivers {
+		var rids simulcastRIDs
+
+		sender := transceiver.Sender()
+		if sender == nil {
+			transceiver.setSimulcastRIDs(rids)
+
+			continue
+		}
+		encodings := sender.GetParameters().Encodings
+		if len(encodings) < 2 {
+			transceiver.setSimulcastRIDs(rids)
+
+			continue
+		}
+
+		var remoteRIDs map[string]bool
+		if mid := transceiver.Mid(); mid != "" {
+			for _, media := range remote.parsed.MediaDescriptions {
+				if getMidValue(media) == mid {
+					remoteRIDs = getRecvSimulcastRIDs(media)
+
+					break
+				}
+			}
+		}
+
+		for _, encoding := range encodings {
+			if remoteRIDs[encoding.RID] {
+				rids.accepted = append(rids.accepted, encoding.RID)
+			} else {
+				rids.rejected = append(rids.rejected, encoding.RID)
+			}
+		}
+		transceiver.setSimulcastRIDs(rids)
+	}
+}
+
+// getRecvSimulcastRIDs returns the RIDs the media section of a remote description receives,
+// from a value like "a=simulcast:recv 1,2;~3" or else from its "a=rid:1 recv" lines.
+func getRecvSimulcastRIDs(media *sdp.MediaDescription) map[string]bool {
+	rids := map[string]bool{}
+	if value, ok := media.Attribute(sdpAttributeSimulcast); ok {
+		// The value holds a list of RIDs per direction, "send 1;2 recv 3"
+		fields := strings.Fields(value)
+		for i := 0; i+1 < len(fields); i += 2 {
+			if fields[i] != sdpSimulcastDirectionRecv {
+				continue
+			}
+			for _, alternatives := range strings.Split(fields[i+1], ";") {
+				for _, rid := range strings.Split(alternatives, ",") {
+					if rid = strings.TrimPrefix(rid, "~"); rid != "" {
+						rids[rid] = true
+					}
+				}
+			}
+		}
+
+		return rids
+	}
+
+	for _, attr := range media.Attributes {
+		if attr.Key != sdpAttributeRid {
+			continue
+		}
+		if split := strings.Fields(attr.Value); len(split) >= 2 && split[1] == sdpSimulcastDirectionRecv {
+			rids[split[0]] = true
+		}
+	}
+
+	return rids
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRecvSimulcastRIDs(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		attributes []sdp.Attribute
+		rids       map[string]bool
+	}{
+		{
+			name:       "SimulcastAttribute",
+			attributes: []sdp.Attribute{{Key: "simulcast", Value: "send x recv a,b;~c"}, {Key: "rid", Value: "d recv"}},
+			rids:       map[string]bool{"a": true, "b": true, "c": true},
+		},
+		{
+			name:       "RIDLines",
+			attributes: []sdp.Attribute{{Key: "rid", Value: "a recv"}, {Key: "rid", Value: "b send"}},
+			rids:       map[string]bool{"a": true},
+		},
+		{
+			name:       "NoSimulcast",
+			attributes: []sdp.Attribute{{Key: "sendrecv"}},
+			rids:       map[string]bool{},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.rids, getRecvSimulcastRIDs(&sdp.MediaDescription{Attributes: test.attributes}))
+		})
+	}
+}
+
+func TestRTPTransceiver_SimulcastRIDs(t *testing.T) {
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	rids := []string{"a", "b", "c"}
+	var sender *RTPSender
+	for _, rid := range rids {
+		track, trackErr := NewTrackLocalStaticRTP(
+			RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion", WithRTPStreamID(rid),
+		)
+		require.NoError(t, trackErr)
+		if sender == nil {
+			sender, err = pcOffer.AddTrack(track)
+		} else {
+			err = sender.AddEncoding(track)
+		}
+		require.NoError(t, err)
+	}
+
+	transceiver := pcOffer.GetTransceivers()[0]
+	accepted, rejected := transceiver.SimulcastRIDs()
+	assert.Empty(t, accepted)
+	assert.Empty(t, rejected)
+
+	offer, err := pcOffer.CreateOffer(nil)
+	require.NoError(t, err)
+	require.NoError(t, pcOffer.SetLocalDescription(offer))
+	require.NoError(t, pcAnswer.SetRemoteDescription(offer))
+	answer, err := pcAnswer.CreateAnswer(nil)
+	require.NoError(t, err)
+	require.NoError(t, pcAnswer.SetLocalDescription(answer))
+
+	// The answerer only accepts two layers
+	require.Contains(t, answer.SDP, "a=simulcast:recv a;b;c\r\n")
+	answer.SDP = strings.Replace(answer.SDP, "a=rid:c recv\r\n", "", 1)
+	answer.SDP = strings.Replace(answer.SDP, "a=simulcast:recv a;b;c\r\n", "a=simulcast:recv a;~b\r\n", 1)
+	require.NoError(t, pcOffer.SetRemoteDescription(answer))
+
+	accepted, rejected = transceiver.SimulcastRIDs()
+	assert.Equal(t, []string{"a", "b"}, accepted)
+	assert.Equal(t, []string{"c"}, rejected)
+
+	accepted, rejected = pcAnswer.GetTransceivers()[0].SimulcastRIDs()
+	assert.Empty(t, accepted)
+	assert.Empty(t, rejected)
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
@@ -25,6 +25,9 @@ type srtpWriterFuture struct {
 	rtpWriteStream atomic.Value // *srtp.WriteStreamSRTP
 	mu             sync.Mutex
 	closed         bool
+
+	// ID of the negotiated abs-send-time extension, 0 if it isn't sent
+	absSendTimeID uint8
 }
 
 func (s *srtpWriterFuture) init(returnWhenNoSRTP bool) error { //nolint:cyclop
@@ -119,7 +122,7 @@ func (s *srtpWriterFuture) SetReadDeadline(t time.Time) error {
 
 func (s *srtpWriterFuture) WriteRTP(header *rtp.Header, payload []byte) (int, error) {
 	if value, ok := s.rtpWriteStream.Load().(*srtp.WriteStreamSRTP); ok {
-		return value.WriteRTP(header, payload)
+		return value.WriteRTP(s.withAbsSendTime(header), payload)
 	}
 
 	if err := s.init(true); err != nil || s.rtpWriteStream.Load() == nil {
@@ -140,3 +143,23 @@ func (s *srtpWriterFuture) Write(b []byte) (int, error) {
 
 	return s.Write(b)
 }
+
+// withAbsSendTime returns a copy of header with the abs-send-time extension set to now, if
+// it was negotiated. The header of the caller is left unchanged.
+func (s *srtpWriterFuture) withAbsSendTime(header *rtp.Header) *rtp.Header {
+	if s.absSendTimeID == 0 {
+		return header
+	}
+
+	payload, err := rtp.NewAbsSendTimeExtension(time.Now()).Marshal()
+	if err != nil {
+		return header
+	}
+
+	stamped := header.Clone()
+	if err = stamped.SetExtension(s.absSendTimeID, payload); err != nil {
+		return header
+	}
+
+	return &stamped
+}
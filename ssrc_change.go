@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/srtp/v3"
+	"github.com/pion/webrtc/v4/internal/util"
+)
+
+// OnSSRCChange sets an event handler which is invoked when the remote moved the track to
+// a new SSRC without renegotiating, and the track was rebound to it. It is only used when
+// SettingEngine.EnableSSRCChangeRecovery is set, reads of the track continue with the
+// packets of the new SSRC.
+func (t *TrackRemote) OnSSRCChange(f func(oldSSRC, newSSRC SSRC)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onSSRCChangeHandler = f
+}
+
+// ssrcChangeTrack returns the track an undeclared SSRC received for mid can be moved to.
+// Only a receiver that already receives a single track without RID can be rebound, it
+// returns nil if SSRC change recovery isn't enabled.
+func (pc *PeerConnection) ssrcChangeTrack(mid string) (*RTPReceiver, *TrackRemote) {
+	if !pc.api.settingEngine.ssrcChangeRecovery || mid == "" {
+		return nil, nil
+	}
+
+	for _, t := range pc.GetTransceivers() {
+		receiver := t.Receiver()
+		if t.Mid() != mid || receiver == nil || !receiver.haveReceived() || receiver.isStopped() {
+			continue
+		}
+
+		tracks := receiver.Tracks()
+		if len(tracks) != 1 || tracks[0].RID() != "" || tracks[0].SSRC() == 0 {
+			return nil, nil
+		}
+
+		return receiver, tracks[0]
+	}
+
+	return nil, nil
+}
+
+// rebindSSRC opens the streams of ssrc and moves track to them.
+func (pc *PeerConnection) rebindSSRC(ssrc SSRC, receiver *RTPReceiver, track *TrackRemote) error {
+	codec := track.Codec()
+	streamInfo := createStreamInfo(
+		"",
+		ssrc,
+		0, 0,
+		codec.PayloadType,
+		0, 0,
+		codec.RTPCodecCapability,
+		receiver.GetParameters().HeaderExtensions,
+	)
+	rtpReadStream, rtpInterceptor, rtcpReadStream, rtcpInterceptor, err := pc.dtlsTransport.streamsForSSRC(
+		ssrc,
+		*streamInfo,
+	)
+	if err != nil {
+		return err
+	}
+
+	return receiver.changeSSRC(track, ssrc, streamInfo, rtpReadStream, rtpInterceptor, rtcpReadStream, rtcpInterceptor)
+}
+
+// changeSSRC moves track to the streams of a new SSRC. The streams of the old SSRC are
+// closed, readers blocked on them retry with the new ones.
+func (r *RTPReceiver) changeSSRC(
+	track *TrackRemote,
+	ssrc SSRC,
+	streamInfo *interceptor.StreamInfo,
+	rtpReadStream *srtp.ReadStreamSRTP,
+	rtpInterceptor interceptor.RTPReader,
+	rtcpReadStream *srtp.ReadStreamSRTCP,
+	rtcpInterceptor interceptor.RTCPReader,
+) error {
+	r.mu.Lock()
+	streams := r.streamsForTrack(track)
+	if streams == nil {
+		r.mu.Unlock()
+		r.api.interceptor.UnbindRemoteStream(streamInfo)
+
+		return fmt.Errorf("%w: %d", errRTPReceiverWithSSRCTrackStreamNotFound, track.SSRC())
+	}
+
+	oldStreamInfo, oldRTPReadStream, oldRTCPReadStream := streams.streamInfo, streams.rtpReadStream, streams.rtcpReadStream
+	streams.streamInfo = streamInfo
+	streams.rtpReadStream, streams.rtpInterceptor = rtpReadStream, rtpInterceptor
+	streams.rtcpReadStream, streams.rtcpInterceptor = rtcpReadStream, rtcpInterceptor
+
+	track.mu.Lock()
+	oldSSRC := track.ssrc
+	track.ssrc = ssrc
+	handler := track.onSSRCChangeHandler
+	track.mu.Unlock()
+	r.mu.Unlock()
+
+	errs := []error{}
+	if oldRTPReadStream != nil {
+		errs = append(errs, oldRTPReadStream.Close())
+	}
+	if oldRTCPReadStream != nil {
+		errs = append(errs, oldRTCPReadStream.Close())
+	}
+	if oldStreamInfo != nil {
+		r.api.interceptor.UnbindRemoteStream(oldStreamInfo)
+	}
+
+	if handler != nil {
+		go handler(oldSSRC, ssrc)
+	}
+
+	return util.FlattenErrs(errs)
+}
+
+// rtpStreamChanged reports whether track was moved to another RTP stream than rtpReadStream.
+func (r *RTPReceiver) rtpStreamChanged(track *TrackRemote, rtpReadStream *srtp.ReadStreamSRTP) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	streams := r.streamsForTrack(track)
+
+	return streams != nil && streams.rtpReadStream != rtpReadStream && !r.isStopped()
+}
+
+// rtcpStreamChanged reports whether track was moved to another RTCP stream than rtcpReadStream.
+func (r *RTPReceiver) rtcpStreamChanged(track *TrackRemote, rtcpReadStream *srtp.ReadStreamSRTCP) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	streams := r.streamsForTrack(track)
+
+	return streams != nil && streams.rtcpReadStream != rtcpReadStream && !r.isStopped()
+}
+
+// isRepairCodec reports whether codec carries RTX or FEC packets, whose streams are not
+// moved to a track.
+func isRepairCodec(codec RTPCodecParameters) bool {
+	mimeType := strings.ToLower(codec.MimeType)
+
+	return mimeType == MimeTypeRTX || strings.Contains(mimeType, MimeTypeFlexFEC)
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/sdp/v3"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRepairCodec(t *testing.T) {
+	assert.True(t, isRepairCodec(RTPCodecParameters{RTPCodecCapability: RTPCodecCapability{MimeType: MimeTypeRTX}}))
+	assert.True(t, isRepairCodec(RTPCodecParameters{RTPCodecCapability: RTPCodecCapability{MimeType: MimeTypeFlexFEC03}}))
+	assert.False(t, isRepairCodec(RTPCodecParameters{RTPCodecCapability: RTPCodecCapability{MimeType: MimeTypeVP8}}))
+}
+
+func TestTrackRemote_SSRCChange(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	mediaEngine := &MediaEngine{}
+	require.NoError(t, mediaEngine.RegisterDefaultCodecs())
+	require.NoError(t, mediaEngine.RegisterHeaderExtension(RTPHeaderExtensionCapability{URI: sdp.SDESMidURI}, RTPCodecTypeVideo))
+	settingEngine := SettingEngine{}
+	settingEngine.EnableSSRCChangeRecovery(true)
+	pcOffer, pcAnswer, err := NewAPI(WithMediaEngine(mediaEngine), WithSettingEngine(settingEngine)).newPair(Configuration{})
+	require.NoError(t, err)
+
+	track, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	require.NoError(t, err)
+	sender, err := pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	// With a second media section the new SSRC is matched by its MID
+	_, err = pcOffer.CreateDataChannel("data", nil)
+	require.NoError(t, err)
+
+	remoteTracks := make(chan *TrackRemote, 1)
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	pcAnswer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		remoteTracks <- trackRemote
+		onTrackFiredFunc()
+	})
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	var midID uint8
+	for _, extension := range sender.GetParameters().HeaderExtensions {
+		if extension.URI == sdp.SDESMidURI {
+			midID = uint8(extension.ID) //nolint:gosec // G115
+		}
+	}
+	require.NotZero(t, midID)
+	mid := pcOffer.GetTransceivers()[0].Mid()
+
+	newPacket := func(sequenceNumber uint16) *rtp.Packet {
+		pkt := &rtp.Packet{Header: rtp.Header{Version: 2, SequenceNumber: sequenceNumber}, Payload: []byte{0x00}}
+		assert.NoError(t, pkt.Header.SetExtension(midID, []byte(mid)))
+
+		return pkt
+	}
+
+	for sequenceNumber := uint16(0); onTrackFired.Err() == nil; sequenceNumber++ {
+		time.Sleep(20 * time.Millisecond)
+		assert.NoError(t, track.WriteRTP(newPacket(sequenceNumber)))
+	}
+	remoteTrack := <-remoteTracks
+	oldSSRC := remoteTrack.SSRC()
+
+	ssrcChanges := make(chan [2]SSRC, 1)
+	remoteTrack.OnSSRCChange(func(oldSSRC, newSSRC SSRC) {
+		ssrcChanges <- [2]SSRC{oldSSRC, newSSRC}
+	})
+
+	newSSRC := oldSSRC + 1
+	readSSRCs := make(chan SSRC)
+	go func() {
+		defer close(readSSRCs)
+		for {
+			pkt, _, readErr := remoteTrack.ReadRTP()
+			if readErr != nil {
+				return
+			}
+			readSSRCs <- SSRC(pkt.SSRC)
+		}
+	}()
+
+	// The encoder restarts with a new SSRC, without renegotiation
+	srtpSession, err := pcOffer.dtlsTransport.getSRTPSession()
+	require.NoError(t, err)
+	writeStream, err := srtpSession.OpenWriteStream()
+	require.NoError(t, err)
+
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	for sequenceNumber := uint16(0); ; sequenceNumber++ {
+		pkt := newPacket(sequenceNumber)
+		pkt.SSRC = uint32(newSSRC)
+		pkt.PayloadType = uint8(sender.GetParameters().Codecs[0].PayloadType)
+		_, err = writeStream.WriteRTP(&pkt.Header, pkt.Payload)
+		require.NoError(t, err)
+
+		select {
+		case ssrc := <-readSSRCs:
+			if ssrc != newSSRC {
+				continue
+			}
+		case <-ticker.C:
+			continue
+		}
+
+		break
+	}
+
+	assert.Equal(t, [2]SSRC{oldSSRC, newSSRC}, <-ssrcChanges)
+	assert.Equal(t, newSSRC, remoteTrack.SSRC())
+
+	closePairNow(t, pcOffer, pcAnswer)
+	for ssrc := range readSSRCs {
+		assert.Equal(t, newSSRC, ssrc)
+	}
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+// SSRCPurpose describes what an SSRC carries.
+type SSRCPurpose int
+
+const (
+	// SSRCPurposeUnknown is the enum's zero-value.
+	SSRCPurposeUnknown SSRCPurpose = iota
+
+	// SSRCPurposeMedia is an SSRC carrying the media of a track.
+	SSRCPurposeMedia
+
+	// SSRCPurposeRTX is an SSRC carrying RTX retransmissions (RFC 4588) of a media SSRC.
+	SSRCPurposeRTX
+
+	// SSRCPurposeFEC is an SSRC carrying forward error correction of a media SSRC.
+	SSRCPurposeFEC
+)
+
+func (p SSRCPurpose) String() string {
+	switch p {
+	case SSRCPurposeMedia:
+		return "media"
+	case SSRCPurposeRTX:
+		return "rtx"
+	case SSRCPurposeFEC:
+		return "fec"
+	default:
+		return ErrUnknownType.Error()
+	}
+}
+
+// SSRCMapEntry describes an SSRC in use by a PeerConnection.
+type SSRCMapEntry struct {
+	SSRC    SSRC
+	Purpose SSRCPurpose
+
+	// MediaSSRC is the SSRC protected by a RTX or FEC SSRC. It equals SSRC for media SSRCs.
+	MediaSSRC SSRC
+
+	// Direction is RTPTransceiverDirectionSendonly for SSRCs sent by the PeerConnection and
+	// RTPTransceiverDirectionRecvonly for SSRCs received from the remote.
+	Direction RTPTransceiverDirection
+
+	Kind     RTPCodecType
+	Mid      string
+	RID      string
+	TrackID  string
+	StreamID string
+}
+
+// SSRCMap returns all SSRCs currently sent and received by the PeerConnection together with
+// the track, mid and rid they belong to. Received simulcast layers are only included once their
+// SSRC has been learned from the first packet.
+func (pc *PeerConnection) SSRCMap() []SSRCMapEntry {
+	entries := []SSRCMapEntry{}
+	add := func(entry SSRCMapEntry, rtxSSRC, fecSSRC SSRC) {
+		if entry.SSRC == 0 {
+			return
+		}
+
+		entry.Purpose = SSRCPurposeMedia
+		entry.MediaSSRC = entry.SSRC
+		entries = append(entries, entry)
+
+		for _, protection := range []struct {
+			ssrc    SSRC
+			purpose SSRCPurpose
+		}{{rtxSSRC, SSRCPurposeRTX}, {fecSSRC, SSRCPurposeFEC}} {
+			if protection.ssrc != 0 {
+				protected := entry
+				protected.SSRC = protection.ssrc
+				protected.Purpose = protection.purpose
+				entries = append(entries, protected)
+			}
+		}
+	}
+
+	for _, transceiver := range pc.GetTransceivers() {
+		mid := transceiver.Mid()
+
+		if sender := transceiver.Sender(); sender != nil {
+			if track := sender.Track(); track != nil {
+				for _, encoding := range sender.GetParameters().Encodings {
+					add(SSRCMapEntry{
+						SSRC:      encoding.SSRC,
+						Direction: RTPTransceiverDirectionSendonly,
+						Kind:      sender.kind,
+						Mid:       mid,
+						RID:       encoding.RID,
+						TrackID:   track.ID(),
+						StreamID:  track.StreamID(),
+					}, encoding.RTX.SSRC, encoding.FEC.SSRC)
+				}
+			}
+		}
+
+		if receiver := transceiver.Receiver(); receiver != nil && !receiver.isStopped() {
+			for _, track := range receiver.Tracks() {
+				add(SSRCMapEntry{
+					SSRC:      track.SSRC(),
+					Direction: RTPTransceiverDirectionRecvonly,
+					Kind:      track.Kind(),
+					Mid:       mid,
+					RID:       track.RID(),
+					TrackID:   track.ID(),
+					StreamID:  track.StreamID(),
+				}, track.RtxSSRC(), 0)
+			}
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSRCPurpose_String(t *testing.T) {
+	testCases := []struct {
+		purpose        SSRCPurpose
+		expectedString string
+	}{
+		{SSRCPurposeUnknown, ErrUnknownType.Error()},
+		{SSRCPurposeMedia, "media"},
+		{SSRCPurposeRTX, "rtx"},
+		{SSRCPurposeFEC, "fec"},
+	}
+
+	for i, testCase := range testCases {
+		assert.Equal(t, testCase.expectedString, testCase.purpose.String(), "testCase: %d %v", i, testCase)
+	}
+}
+
+func TestPeerConnection_SSRCMap(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	assert.Empty(t, pcOffer.SSRCMap())
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	require.NoError(t, err)
+	sender, err := pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	encoding := sender.GetParameters().Encodings[0]
+	require.NotZero(t, encoding.RTX.SSRC)
+
+	sent := pcOffer.SSRCMap()
+	assert.Equal(t, []SSRCMapEntry{
+		{
+			SSRC: encoding.SSRC, Purpose: SSRCPurposeMedia, MediaSSRC: encoding.SSRC,
+			Direction: RTPTransceiverDirectionSendonly, Kind: RTPCodecTypeVideo, TrackID: "video", StreamID: "pion",
+		},
+		{
+			SSRC: encoding.RTX.SSRC, Purpose: SSRCPurposeRTX, MediaSSRC: encoding.SSRC,
+			Direction: RTPTransceiverDirectionSendonly, Kind: RTPCodecTypeVideo, TrackID: "video", StreamID: "pion",
+		},
+	}, sent)
+
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	pcAnswer.OnTrack(func(*TrackRemote, *RTPReceiver) {
+		onTrackFiredFunc()
+	})
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	sendVideoUntilDone(t, onTrackFired.Done(), []*TrackLocalStaticSample{track})
+
+	// Once negotiated both sides agree on the mid
+	sent = pcOffer.SSRCMap()
+	received := pcAnswer.SSRCMap()
+	require.Len(t, received, 2)
+	for i := range sent {
+		assert.Equal(t, "0", sent[i].Mid)
+		assert.Equal(t, RTPTransceiverDirectionRecvonly, received[i].Direction)
+
+		received[i].Direction = sent[i].Direction
+		assert.Equal(t, sent[i], received[i])
+	}
+
+	// Removed tracks are no longer reported
+	require.NoError(t, pcOffer.RemoveTrack(sender))
+	assert.Empty(t, pcOffer.SSRCMap())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
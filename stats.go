@@ -435,9 +435,17 @@ type InboundRTPStreamStats struct {
 	// because of how this is estimated, it can be negative if more packets are received than sent.
 	PacketsLost int32 `json:"packetsLost"`
 
-	// Jitter is the packet jitter measured in seconds for this SSRC
+	// Jitter is the interarrival jitter of the packets of this SSRC, in seconds.
 	Jitter float64 `json:"jitter"`
 
+	// JitterMilliseconds is Jitter in milliseconds.
+	// This is not part of the WebRTC statistics specification.
+	JitterMilliseconds float64 `json:"jitterMilliseconds"`
+
+	// JitterTimestampUnits is the interarrival jitter in RTP timestamp units of the codec, like
+	// in the RTCP receiver reports. This is not part of the WebRTC statistics specification.
+	JitterTimestampUnits float64 `json:"jitterTimestampUnits"`
+
 	// PacketsDiscarded is the cumulative number of RTP packets discarded by the jitter
 	// buffer due to late or early-arrival, i.e., these packets are not played out.
 	// RTP packets discarded due to packet duplication are not reported in this metric.
@@ -594,6 +602,34 @@ type InboundRTPStreamStats struct {
 	// (for definition of freeze see freezeCount), in seconds. Does not exist for audio.
 	TotalFreezesDuration float64 `json:"totalFreezesDuration"`
 
+	// SequenceJumps is the number of times the sequence number moved further than packet
+	// loss or reordering explain. This is not part of the WebRTC statistics specification.
+	SequenceJumps uint32 `json:"sequenceJumps"`
+
+	// TimestampsBackwards is the number of times the RTP timestamp went backwards while
+	// the sequence number moved forwards. This is not part of the WebRTC statistics
+	// specification.
+	TimestampsBackwards uint32 `json:"timestampsBackwards"`
+
+	// SSRCChanges is the number of times the SSRC of the packets changed in the middle of
+	// the stream. This is not part of the WebRTC statistics specification.
+	SSRCChanges uint32 `json:"ssrcChanges"`
+
+	// KeyFrameRequestsSuppressed is the number of PLI and FIR packets that were not sent
+	// because a keyframe was requested too recently, see SettingEngine.SetKeyFrameRequestInterval.
+	// This is not part of the WebRTC statistics specification.
+	KeyFrameRequestsSuppressed uint32 `json:"keyFrameRequestsSuppressed"`
+
+	// EstimatedClockSkew is the estimated drift of the clock of the sender relative to the
+	// local clock, in parts per million, see TrackRemote.EstimatedClockSkew. It is 0 until
+	// it is estimated. This is not part of the WebRTC statistics specification.
+	EstimatedClockSkew float64 `json:"estimatedClockSkew"`
+
+	// PacketsDroppedByBuffer is the number of packets dropped because the receive buffer of the
+	// stream was full, with the policy set by RTPReceiver.SetBufferPolicy. This is not part of
+	// the WebRTC statistics specification.
+	PacketsDroppedByBuffer uint64 `json:"packetsDroppedByBuffer"`
+
 	// PowerEfficientDecoder indicates whether the decoder currently used is considered power efficient
 	// by the user agent. Does not exist for audio.
 	PowerEfficientDecoder bool `json:"powerEfficientDecoder"`
@@ -1994,6 +2030,14 @@ type TransportStats struct {
 	// transport, as defined in the "Profile" column of the IANA DTLS-SRTP protection
 	// profile registry.
 	SRTPCipher string `json:"srtpCipher"`
+
+	// OversizedPacketsReceived is the number of SRTP and SRTCP packets dropped on receipt
+	// because they are larger than the maximum packet size of the SettingEngine.
+	OversizedPacketsReceived uint32 `json:"oversizedPacketsReceived"`
+
+	// OversizedPacketsSent is the number of SRTP and SRTCP packets that couldn't be sent
+	// because they are larger than the maximum packet size of the SettingEngine.
+	OversizedPacketsSent uint32 `json:"oversizedPacketsSent"`
 }
 
 func (s TransportStats) statsMarker() {}
@@ -2030,6 +2074,32 @@ func toStatsICECandidatePairState(state ice.CandidatePairState) (StatsICECandida
 	}
 }
 
+// iceCandidatePriorityPreferences splits a candidate priority into the values it was computed
+// from, see RFC 8445 section 5.1.2.1.
+func iceCandidatePriorityPreferences(priority uint32) (typePreference uint8, localPreference, componentID uint16) {
+	return uint8(priority >> 24), uint16(priority >> 8), 256 - uint16(priority&0xff) //nolint:gosec // G115
+}
+
+// iceCandidatePairPriority computes the priority of a pair from the priorities of its local
+// and remote candidates, see RFC 8445 section 6.1.2.3. It returns 0 if role isn't known.
+func iceCandidatePairPriority(role ICERole, local, remote uint32) uint64 {
+	var controlling, controlled uint64
+	switch role {
+	case ICERoleControlling:
+		controlling, controlled = uint64(local), uint64(remote)
+	case ICERoleControlled:
+		controlling, controlled = uint64(remote), uint64(local)
+	default:
+		return 0
+	}
+
+	if controlling > controlled {
+		return (1<<32)*controlled + 2*controlling + 1
+	}
+
+	return (1<<32)*controlling + 2*controlled
+}
+
 func toICECandidatePairStats(candidatePairStats ice.CandidatePairStats) (ICECandidatePairStats, error) {
 	state, err := toStatsICECandidatePairState(candidatePairStats.State)
 	if err != nil {
@@ -2134,6 +2204,11 @@ type ICECandidatePairStats struct {
 	// if it is the highest-priority one amongst those whose nominated flag is set
 	Nominated bool `json:"nominated"`
 
+	// Priority is the priority of the pair computed from the priorities of its candidates
+	// as defined in RFC 8445 section 6.1.2.3, with the role of the local ICE agent. It is
+	// 0 until the role is known.
+	Priority uint64 `json:"priority"`
+
 	// PacketsSent represents the total number of packets sent on this candidate pair.
 	PacketsSent uint32 `json:"packetsSent"`
 
@@ -2321,6 +2396,17 @@ type ICECandidateStats struct {
 	// Priority is the "Priority" field of the ICECandidate.
 	Priority int32 `json:"priority"`
 
+	// TypePreference is the type preference Priority was computed from, as defined in
+	// RFC 8445 section 5.1.2.1: Priority = 2^24*TypePreference + 2^8*LocalPreference +
+	// (256 - ComponentID).
+	TypePreference uint8 `json:"typePreference"`
+
+	// LocalPreference is the local preference Priority was computed from.
+	LocalPreference uint16 `json:"localPreference"`
+
+	// ComponentID is the ID of the component the candidate is for, 1 for RTP.
+	ComponentID uint16 `json:"componentId"`
+
 	// URL is the URL of the TURN or STUN server indicated in the that translated
 	// this IP address. It is the URL address surfaced in an PeerConnectionICEEvent.
 	URL string `json:"url"`
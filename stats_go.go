@@ -101,3 +101,40 @@ func (r StatsReport) GetCodecStats(c *RTPCodecParameters) (CodecStats, bool) {
 
 	return codecStats, true
 }
+
+// GetInboundRTPStreamStats is a helper method to return the associated stats for a given TrackRemote.
+func (r StatsReport) GetInboundRTPStreamStats(track *TrackRemote) (InboundRTPStreamStats, bool) {
+	statsID := inboundRTPStreamStatsID(track.SSRC())
+	stats, ok := r[statsID]
+	if !ok {
+		return InboundRTPStreamStats{}, false
+	}
+
+	inboundStats, ok := stats.(InboundRTPStreamStats)
+	if !ok {
+		return InboundRTPStreamStats{}, false
+	}
+
+	return inboundStats, true
+}
+
+// GetOutboundRTPStreamStats is a helper method to return the associated stats for a given RTPSender.
+func (r StatsReport) GetOutboundRTPStreamStats(sender *RTPSender) (OutboundRTPStreamStats, bool) {
+	encodings := sender.GetParameters().Encodings
+	if len(encodings) == 0 {
+		return OutboundRTPStreamStats{}, false
+	}
+
+	statsID := outboundRTPStreamStatsID(encodings[0].SSRC)
+	stats, ok := r[statsID]
+	if !ok {
+		return OutboundRTPStreamStats{}, false
+	}
+
+	outboundStats, ok := stats.(OutboundRTPStreamStats)
+	if !ok {
+		return OutboundRTPStreamStats{}, false
+	}
+
+	return outboundStats, true
+}
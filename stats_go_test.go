@@ -111,6 +111,8 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
 		PacketsReceived:                6,
 		PacketsLost:                    7,
 		Jitter:                         8,
+		JitterMilliseconds:             0.5,
+		JitterTimestampUnits:           720,
 		PacketsDiscarded:               9,
 		PacketsRepaired:                10,
 		BurstPacketsLost:               11,
@@ -147,7 +149,14 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
 		TotalPausesDuration:   48.123,
 		FreezeCount:           49,
 		TotalFreezesDuration:  49.321,
+		SequenceJumps:         50,
+		TimestampsBackwards:   51,
+		SSRCChanges:           52,
 		PowerEfficientDecoder: true,
+
+		KeyFrameRequestsSuppressed: 53,
+		EstimatedClockSkew:         54.5,
+		PacketsDroppedByBuffer:     55,
 	}
 	inboundRTPStreamStatsJSON := `
 {
@@ -184,6 +193,8 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
   "packetsReceived": 6,
   "packetsLost": 7,
   "jitter": 8,
+  "jitterMilliseconds": 0.5,
+  "jitterTimestampUnits": 720,
   "packetsDiscarded": 9,
   "packetsRepaired": 10,
   "burstPacketsLost": 11,
@@ -220,6 +231,12 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
   "totalPausesDuration": 48.123,
   "freezeCount": 49,
   "totalFreezesDuration": 49.321,
+  "sequenceJumps": 50,
+  "timestampsBackwards": 51,
+  "ssrcChanges": 52,
+  "keyFrameRequestsSuppressed": 53,
+  "estimatedClockSkew": 54.5,
+  "packetsDroppedByBuffer": 55,
   "powerEfficientDecoder": true
 }
 `
@@ -825,9 +842,11 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
 		//nolint:lll
 		LocalCertificateID: "CFF4:4F:C4:C7:F3:31:6C:B9:D5:AD:19:64:05:9F:2F:E9:00:70:56:1E:BA:92:29:3A:08:CE:1B:27:CF:2D:AB:24",
 		//nolint:lll
-		RemoteCertificateID: "CF62:AF:88:F7:F3:0F:D6:C4:93:91:1E:AD:52:F0:A4:12:04:F9:48:E7:06:16:BA:A3:86:26:8F:1E:38:1C:48:49",
-		DTLSCipher:          "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
-		SRTPCipher:          "AES_CM_128_HMAC_SHA1_80",
+		RemoteCertificateID:      "CF62:AF:88:F7:F3:0F:D6:C4:93:91:1E:AD:52:F0:A4:12:04:F9:48:E7:06:16:BA:A3:86:26:8F:1E:38:1C:48:49",
+		DTLSCipher:               "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+		SRTPCipher:               "AES_CM_128_HMAC_SHA1_80",
+		OversizedPacketsReceived: 1,
+		OversizedPacketsSent:     2,
 	}
 	//nolint:lll
 	transportStatsJSON := `
@@ -847,7 +866,9 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
   "localCertificateId": "CFF4:4F:C4:C7:F3:31:6C:B9:D5:AD:19:64:05:9F:2F:E9:00:70:56:1E:BA:92:29:3A:08:CE:1B:27:CF:2D:AB:24",
   "remoteCertificateId": "CF62:AF:88:F7:F3:0F:D6:C4:93:91:1E:AD:52:F0:A4:12:04:F9:48:E7:06:16:BA:A3:86:26:8F:1E:38:1C:48:49",
   "dtlsCipher": "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
-  "srtpCipher": "AES_CM_128_HMAC_SHA1_80"
+  "srtpCipher": "AES_CM_128_HMAC_SHA1_80",
+  "oversizedPacketsReceived": 1,
+  "oversizedPacketsSent": 2
 }
 `
 	iceCandidatePairStats := ICECandidatePairStats{
@@ -859,6 +880,7 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
 		RemoteCandidateID:             "ILlMJOnBv",
 		State:                         "waiting",
 		Nominated:                     true,
+		Priority:                      9114756780654476799,
 		PacketsSent:                   1,
 		PacketsReceived:               2,
 		BytesSent:                     3,
@@ -897,6 +919,7 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
   "remoteCandidateId": "ILlMJOnBv",
   "state": "waiting",
   "nominated": true,
+  "priority": 9114756780654476799,
   "packetsSent": 1,
   "packetsReceived": 2,
   "bytesSent": 3,
@@ -927,19 +950,22 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
 }
 `
 	localIceCandidateStats := ICECandidateStats{
-		Timestamp:     1688978831527.718,
-		Type:          StatsTypeLocalCandidate,
-		ID:            "ILO8S8KYr",
-		TransportID:   "T01",
-		NetworkType:   "wifi",
-		IP:            "192.168.0.36",
-		Port:          65400,
-		Protocol:      "udp",
-		CandidateType: ICECandidateTypeHost,
-		Priority:      2122260223,
-		URL:           "example.com",
-		RelayProtocol: "tcp",
-		Deleted:       true,
+		Timestamp:       1688978831527.718,
+		Type:            StatsTypeLocalCandidate,
+		ID:              "ILO8S8KYr",
+		TransportID:     "T01",
+		NetworkType:     "wifi",
+		IP:              "192.168.0.36",
+		Port:            65400,
+		Protocol:        "udp",
+		CandidateType:   ICECandidateTypeHost,
+		Priority:        2122260223,
+		TypePreference:  126,
+		LocalPreference: 32542,
+		ComponentID:     1,
+		URL:             "example.com",
+		RelayProtocol:   "tcp",
+		Deleted:         true,
 	}
 	localIceCandidateStatsJSON := `
 {
@@ -953,24 +979,30 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
   "protocol": "udp",
   "candidateType": "host",
   "priority": 2122260223,
+  "typePreference": 126,
+  "localPreference": 32542,
+  "componentId": 1,
   "url": "example.com",
   "relayProtocol": "tcp",
   "deleted": true
 }
 `
 	remoteIceCandidateStats := ICECandidateStats{
-		Timestamp:     1689668364374.181,
-		Type:          StatsTypeRemoteCandidate,
-		ID:            "IGPGeswsH",
-		TransportID:   "T01",
-		IP:            "10.213.237.226",
-		Port:          50618,
-		Protocol:      "udp",
-		CandidateType: ICECandidateTypeHost,
-		Priority:      2122194687,
-		URL:           "example.com",
-		RelayProtocol: "tcp",
-		Deleted:       true,
+		Timestamp:       1689668364374.181,
+		Type:            StatsTypeRemoteCandidate,
+		ID:              "IGPGeswsH",
+		TransportID:     "T01",
+		IP:              "10.213.237.226",
+		Port:            50618,
+		Protocol:        "udp",
+		CandidateType:   ICECandidateTypeHost,
+		Priority:        2122194687,
+		TypePreference:  126,
+		LocalPreference: 32286,
+		ComponentID:     1,
+		URL:             "example.com",
+		RelayProtocol:   "tcp",
+		Deleted:         true,
 	}
 	remoteIceCandidateStatsJSON := `
 {
@@ -983,6 +1015,9 @@ func getStatsSamples() []statSample { //nolint:cyclop,maintidx
   "protocol": "udp",
   "candidateType": "host",
   "priority": 2122194687,
+  "typePreference": 126,
+  "localPreference": 32286,
+  "componentId": 1,
   "url": "example.com",
   "relayProtocol": "tcp",
   "deleted": true
@@ -1276,6 +1311,21 @@ func findCandidatePairStats(t *testing.T, report StatsReport) []ICECandidatePair
 	return result
 }
 
+func assertCandidatePairPriorities(t *testing.T, report StatsReport, role ICERole) {
+	t.Helper()
+
+	for _, pair := range findCandidatePairStats(t, report) {
+		local, ok := report[pair.LocalCandidateID].(ICECandidateStats)
+		require.True(t, ok)
+		remote, ok := report[pair.RemoteCandidateID].(ICECandidateStats)
+		require.True(t, ok)
+
+		priority := iceCandidatePairPriority(role, uint32(local.Priority), uint32(remote.Priority)) //nolint:gosec // G115
+		assert.NotZero(t, pair.Priority)
+		assert.Equal(t, priority, pair.Priority)
+	}
+}
+
 func signalPairForStats(pcOffer *PeerConnection, pcAnswer *PeerConnection) error {
 	offerChan := make(chan SessionDescription)
 	pcOffer.OnICECandidate(func(candidate *ICECandidate) {
@@ -1319,6 +1369,18 @@ func signalPairForStats(pcOffer *PeerConnection, pcAnswer *PeerConnection) error
 	}
 }
 
+func TestICECandidatePriority(t *testing.T) {
+	typePreference, localPreference, componentID := iceCandidatePriorityPreferences(2122260223)
+	assert.Equal(t, uint8(126), typePreference)
+	assert.Equal(t, uint16(32542), localPreference)
+	assert.Equal(t, uint16(1), componentID)
+
+	assert.Equal(t, uint64(0), iceCandidatePairPriority(ICERoleUnknown, 2, 1))
+	assert.Equal(t, uint64(1<<32+2*2+1), iceCandidatePairPriority(ICERoleControlling, 2, 1))
+	assert.Equal(t, uint64(1<<32+2*2), iceCandidatePairPriority(ICERoleControlled, 2, 1))
+	assert.Equal(t, uint64(5<<32+2*5), iceCandidatePairPriority(ICERoleControlling, 5, 5))
+}
+
 func TestStatsConvertState(t *testing.T) {
 	testCases := []struct {
 		ice   ice.CandidatePairState
@@ -1437,6 +1499,8 @@ func TestPeerConnection_GetStats(t *testing.T) {
 	assert.NotEmpty(t, findLocalCandidateStats(reportPCAnswer))
 	assert.NotEmpty(t, findRemoteCandidateStats(reportPCAnswer))
 	assert.NotEmpty(t, findCandidatePairStats(t, reportPCAnswer))
+	assertCandidatePairPriorities(t, reportPCOffer, offerPC.iceTransport.Role())
+	assertCandidatePairPriorities(t, reportPCAnswer, answerPC.iceTransport.Role())
 	assert.NoError(t, err)
 	for i := range offerPC.api.mediaEngine.videoCodecs {
 		codecStat := getCodecStats(t, reportPCOffer, &(offerPC.api.mediaEngine.videoCodecs[i]))
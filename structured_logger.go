@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"fmt"
+
+	"github.com/pion/logging"
+)
+
+// Keys of the fields passed to a StructuredLogger with each message.
+const (
+	// LogFieldSubsystem is the key of the subsystem that logged the message, the scope
+	// of the logger such as "pc", "ice", "dtls" or "sctp".
+	LogFieldSubsystem = "subsystem"
+
+	// LogFieldConnection is the key of the identifier of the PeerConnection the message
+	// belongs to, the ID of its PeerConnectionStats. It is absent for objects created
+	// directly from an API.
+	LogFieldConnection = "connection"
+)
+
+// StructuredLogger is a logging sink that receives the messages of every subsystem with
+// key/value fields, it can be set with SettingEngine.SetStructuredLogger. keyvals holds
+// alternating keys and values, see LogFieldSubsystem and LogFieldConnection, which maps
+// directly to slog.Logger.Log or zap.SugaredLogger.Logw.
+type StructuredLogger interface {
+	// Enabled reports whether messages of level are logged, others are not formatted.
+	Enabled(level logging.LogLevel) bool
+	Log(level logging.LogLevel, msg string, keyvals ...interface{})
+}
+
+// structuredLoggerFactory is a logging.LoggerFactory creating loggers that forward to
+// a StructuredLogger.
+type structuredLoggerFactory struct {
+	logger       StructuredLogger
+	connectionID string
+}
+
+func newStructuredLoggerFactory(logger StructuredLogger, connectionID string) *structuredLoggerFactory {
+	return &structuredLoggerFactory{logger: logger, connectionID: connectionID}
+}
+
+func (f *structuredLoggerFactory) NewLogger(scope string) logging.LeveledLogger {
+	keyvals := []interface{}{LogFieldSubsystem, scope}
+	if f.connectionID != "" {
+		keyvals = append(keyvals, LogFieldConnection, f.connectionID)
+	}
+
+	return &structuredLeveledLogger{logger: f.logger, keyvals: keyvals[:len(keyvals):len(keyvals)]}
+}
+
+// structuredLeveledLogger is a logging.LeveledLogger for a single subsystem.
+type structuredLeveledLogger struct {
+	logger  StructuredLogger
+	keyvals []interface{}
+}
+
+func (l *structuredLeveledLogger) log(level logging.LogLevel, msg string) {
+	if l.logger.Enabled(level) {
+		l.logger.Log(level, msg, l.keyvals...)
+	}
+}
+
+func (l *structuredLeveledLogger) logf(level logging.LogLevel, format string, args ...interface{}) {
+	if l.logger.Enabled(level) {
+		l.logger.Log(level, fmt.Sprintf(format, args...), l.keyvals...)
+	}
+}
+
+func (l *structuredLeveledLogger) Trace(msg string) {
+	l.log(logging.LogLevelTrace, msg)
+}
+
+func (l *structuredLeveledLogger) Tracef(format string, args ...interface{}) {
+	l.logf(logging.LogLevelTrace, format, args...)
+}
+
+func (l *structuredLeveledLogger) Debug(msg string) {
+	l.log(logging.LogLevelDebug, msg)
+}
+
+func (l *structuredLeveledLogger) Debugf(format string, args ...interface{}) {
+	l.logf(logging.LogLevelDebug, format, args...)
+}
+
+func (l *structuredLeveledLogger) Info(msg string) {
+	l.log(logging.LogLevelInfo, msg)
+}
+
+func (l *structuredLeveledLogger) Infof(format string, args ...interface{}) {
+	l.logf(logging.LogLevelInfo, format, args...)
+}
+
+func (l *structuredLeveledLogger) Warn(msg string) {
+	l.log(logging.LogLevelWarn, msg)
+}
+
+func (l *structuredLeveledLogger) Warnf(format string, args ...interface{}) {
+	l.logf(logging.LogLevelWarn, format, args...)
+}
+
+func (l *structuredLeveledLogger) Error(msg string) {
+	l.log(logging.LogLevelError, msg)
+}
+
+func (l *structuredLeveledLogger) Errorf(format string, args ...interface{}) {
+	l.logf(logging.LogLevelError, format, args...)
+}
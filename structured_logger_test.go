@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/logging"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedLog struct {
+	level   logging.LogLevel
+	msg     string
+	keyvals []interface{}
+}
+
+type recordingStructuredLogger struct {
+	mu    sync.Mutex
+	level logging.LogLevel
+	logs  []recordedLog
+}
+
+func (l *recordingStructuredLogger) Enabled(level logging.LogLevel) bool {
+	return level <= l.level
+}
+
+func (l *recordingStructuredLogger) Log(level logging.LogLevel, msg string, keyvals ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.logs = append(l.logs, recordedLog{level, msg, keyvals})
+}
+
+func (l *recordingStructuredLogger) subsystems(connectionID string) map[interface{}]bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	subsystems := map[interface{}]bool{}
+	for _, log := range l.logs {
+		if len(log.keyvals) == 4 && log.keyvals[3] == connectionID {
+			subsystems[log.keyvals[1]] = true
+		}
+	}
+
+	return subsystems
+}
+
+func TestStructuredLoggerFactory(t *testing.T) {
+	logger := &recordingStructuredLogger{level: logging.LogLevelInfo}
+
+	newStructuredLoggerFactory(logger, "").NewLogger("api").Errorf("failed %d", 1)
+	scoped := newStructuredLoggerFactory(logger, "PeerConnection-1").NewLogger("ice")
+	scoped.Info("info")
+	scoped.Debugf("not %s", "logged")
+	scoped.Trace("not logged")
+
+	assert.Equal(t, []recordedLog{
+		{logging.LogLevelError, "failed 1", []interface{}{LogFieldSubsystem, "api"}},
+		{logging.LogLevelInfo, "info", []interface{}{LogFieldSubsystem, "ice", LogFieldConnection, "PeerConnection-1"}},
+	}, logger.logs)
+}
+
+func TestSettingEngine_SetStructuredLogger(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	logger := &recordingStructuredLogger{level: logging.LogLevelTrace}
+	settingEngine := SettingEngine{}
+	settingEngine.SetStructuredLogger(logger)
+	pcOffer, pcAnswer, err := NewAPI(WithSettingEngine(settingEngine)).newPair(Configuration{})
+	require.NoError(t, err)
+
+	connected := untilConnectionState(PeerConnectionStateConnected, pcOffer, pcAnswer)
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	connected.Wait()
+	closePairNow(t, pcOffer, pcAnswer)
+
+	for _, pc := range []*PeerConnection{pcOffer, pcAnswer} {
+		subsystems := logger.subsystems(pc.statsID)
+		for _, subsystem := range []string{"pc", "ice", "dtls"} {
+			assert.True(t, subsystems[subsystem], subsystem)
+		}
+	}
+}
@@ -0,0 +1,491 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pion/transport/v3/deadline"
+	"github.com/pion/transport/v3/packetio"
+	"github.com/pion/webrtc/v4/internal/util"
+)
+
+// TrackBufferPolicy decides what happens to the packets of a remote track that arrive while
+// its receive buffer is full, because the application doesn't read the track fast enough.
+type TrackBufferPolicy int
+
+const (
+	// TrackBufferPolicyUnknown is the enum's zero-value.
+	TrackBufferPolicyUnknown TrackBufferPolicy = iota
+
+	// TrackBufferPolicyDropNewest drops the packets that arrive while the buffer is full, the
+	// application reads old packets first. This is the default.
+	TrackBufferPolicyDropNewest
+
+	// TrackBufferPolicyDropOldest drops the oldest packets of the buffer to make room for the
+	// ones that arrive, the application reads the most recent packets, which suits live media.
+	TrackBufferPolicyDropOldest
+)
+
+// This is done this way because of a linter.
+const (
+	trackBufferPolicyDropNewestStr = "drop-newest"
+	trackBufferPolicyDropOldestStr = "drop-oldest"
+)
+
+func (p TrackBufferPolicy) String() string {
+	switch p {
+	case TrackBufferPolicyDropNewest:
+		return trackBufferPolicyDropNewestStr
+	case TrackBufferPolicyDropOldest:
+		return trackBufferPolicyDropOldestStr
+	default:
+		return ErrUnknownType.Error()
+	}
+}
+
+// SetBufferPolicy sets what happens to the packets of the tracks of the RTPReceiver that arrive
+// while their receive buffer is full, and size, the number of packets the buffer of every track
+// holds. A size of 0 only limits the buffer to 1 MB, that limit always applies. By default the
+// buffer is a packetio.Buffer, which drops the newest packets once it holds 1 MB. The dropped
+// packets are counted by the PacketsDroppedByBuffer of the InboundRTPStreamStats.
+//
+// The policy applies to the buffers of the tracks the RTPReceiver receives now and later, RTX
+// included: from then on the packets are queued in a buffer applying the policy, the ones
+// already in the packetio.Buffer are read first. It doesn't apply to buffers created by
+// SettingEngine.BufferFactory.
+func (r *RTPReceiver) SetBufferPolicy(policy TrackBufferPolicy, size int) error {
+	switch {
+	case policy != TrackBufferPolicyDropNewest && policy != TrackBufferPolicyDropOldest:
+		return fmt.Errorf("%w: %s", errRTPReceiverInvalidBufferPolicy, policy)
+	case size < 0:
+		return fmt.Errorf("%w: size %d", errRTPReceiverInvalidBufferPolicy, size)
+	}
+
+	r.bufferPolicy.Store(trackBufferConfig{policy: policy, size: size})
+	for _, track := range r.Tracks() {
+		r.applyBufferPolicy(track.SSRC())
+		r.applyBufferPolicy(track.RtxSSRC())
+	}
+
+	return nil
+}
+
+// applyBufferPolicy applies the policy set with SetBufferPolicy to the buffer of ssrc.
+func (r *RTPReceiver) applyBufferPolicy(ssrc SSRC) {
+	config, ok := r.bufferPolicy.Load().(trackBufferConfig)
+	if !ok || ssrc == 0 {
+		return
+	}
+
+	if buffer := r.transport.rtpBuffer(ssrc); buffer != nil {
+		buffer.setPolicy(config)
+	}
+}
+
+// rtpBuffer returns the receive buffer of the SRTP stream of ssrc, nil if it wasn't created
+// yet or was created by SettingEngine.BufferFactory.
+func (t *DTLSTransport) rtpBuffer(ssrc SSRC) *rtpStreamBuffer {
+	t.streamBuffersLock.Lock()
+	defer t.streamBuffersLock.Unlock()
+
+	key := streamBufferKey{packetType: packetio.RTPBufferPacket, ssrc: uint32(ssrc)}
+	buffer, _ := t.streamBuffers[key].(*rtpStreamBuffer)
+
+	return buffer
+}
+
+// droppedPackets returns the number of packets of ssrc dropped by its receive buffer.
+func (t *DTLSTransport) droppedPackets(ssrc SSRC) uint64 {
+	if buffer := t.rtpBuffer(ssrc); buffer != nil {
+		return buffer.droppedPackets()
+	}
+
+	return 0
+}
+
+// rtpStreamBuffer is the default receive buffer of a SRTP stream. It is a packetio.Buffer,
+// through arrivalTimeBuffer, until a TrackBufferPolicy is set, then a trackBuffer applying it.
+// The packetio.Buffer is closed when the policy is set, its packets are read before the ones
+// of the trackBuffer.
+type rtpStreamBuffer struct {
+	mu        sync.Mutex
+	buffer    *arrivalTimeBuffer
+	policy    *trackBuffer // nil until a policy is set
+	drained   bool         // the packets of buffer were read, the reads go to policy
+	closed    bool
+	limitSize int
+	deadline  time.Time
+	dropped   uint64 // by buffer
+}
+
+func newRTPStreamBuffer(limitSize int) *rtpStreamBuffer {
+	packetBuffer := packetio.NewBuffer()
+	packetBuffer.SetLimitSize(limitSize)
+
+	return &rtpStreamBuffer{buffer: newArrivalTimeBuffer(packetBuffer), limitSize: limitSize}
+}
+
+func (b *rtpStreamBuffer) setPolicy(config trackBufferConfig) {
+	b.mu.Lock()
+	if b.policy != nil || b.closed {
+		if b.policy != nil {
+			b.policy.setPolicy(config)
+		}
+		b.mu.Unlock()
+
+		return
+	}
+
+	b.policy = newTrackBuffer(b.limitSize)
+	b.policy.setPolicy(config)
+	_ = b.policy.SetReadDeadline(b.deadline)
+	buffer := b.buffer
+	b.mu.Unlock()
+
+	// The reads move to the trackBuffer once they returned the packets left
+	_ = buffer.Close()
+}
+
+// Write never blocks, the lock is held so that no packet is written to the closed
+// packetio.Buffer while the policy is set.
+func (b *rtpStreamBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.policy != nil {
+		return b.policy.Write(p)
+	}
+
+	n, err := b.buffer.Write(p)
+	if errors.Is(err, packetio.ErrFull) {
+		b.dropped++
+	}
+
+	return n, err
+}
+
+func (b *rtpStreamBuffer) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	buffer, policy, drained := b.buffer, b.policy, b.drained
+	b.mu.Unlock()
+
+	if !drained {
+		n, err := buffer.Read(p)
+		if !errors.Is(err, io.EOF) {
+			return n, err
+		}
+
+		// The packetio.Buffer is closed, with the buffer or because a policy was set
+		b.mu.Lock()
+		policy = b.policy
+		b.drained = policy != nil
+		b.mu.Unlock()
+		if policy == nil {
+			return n, err
+		}
+	}
+
+	return policy.Read(p)
+}
+
+func (b *rtpStreamBuffer) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	policy := b.policy
+	b.mu.Unlock()
+
+	closeErrs := []error{b.buffer.Close()}
+	if policy != nil {
+		closeErrs = append(closeErrs, policy.Close())
+	}
+
+	return util.FlattenErrs(closeErrs)
+}
+
+// SetReadDeadline sets the deadline of Read, it is used by srtp.ReadStreamSRTP.
+func (b *rtpStreamBuffer) SetReadDeadline(deadline time.Time) error {
+	b.mu.Lock()
+	b.deadline = deadline
+	policy := b.policy
+	b.mu.Unlock()
+
+	if policy != nil {
+		_ = policy.SetReadDeadline(deadline)
+	}
+
+	return b.buffer.SetReadDeadline(deadline)
+}
+
+// Size returns the size of the packets of the buffer, see DTLSTransport.bufferedBytes.
+func (b *rtpStreamBuffer) Size() int {
+	b.mu.Lock()
+	policy := b.policy
+	b.mu.Unlock()
+
+	size := b.buffer.Size()
+	if policy != nil {
+		size += policy.Size()
+	}
+
+	return size
+}
+
+// lastArrival returns the arrival time of the packet returned by the last Read.
+func (b *rtpStreamBuffer) lastArrival() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.drained {
+		return b.policy.lastArrival()
+	}
+
+	return b.buffer.lastArrival()
+}
+
+func (b *rtpStreamBuffer) droppedPackets() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	dropped := b.dropped
+	if b.policy != nil {
+		dropped += b.policy.droppedPackets()
+	}
+
+	return dropped
+}
+
+type trackBufferConfig struct {
+	policy TrackBufferPolicy
+	size   int
+}
+
+type trackBufferPacket struct {
+	data    []byte
+	arrival time.Time
+}
+
+// trackBufferTimeoutError is returned when the read deadline of a trackBuffer expires, it is a
+// net.Error like the one of packetio.Buffer.
+type trackBufferTimeoutError struct{}
+
+func (trackBufferTimeoutError) Error() string   { return packetio.ErrTimeout.Error() }
+func (trackBufferTimeoutError) Unwrap() error   { return packetio.ErrTimeout }
+func (trackBufferTimeoutError) Timeout() bool   { return true }
+func (trackBufferTimeoutError) Temporary() bool { return true }
+
+// trackBuffer is the receive buffer of a SRTP stream once a TrackBufferPolicy is set, see
+// rtpStreamBuffer. It records when every packet was written to it by the SRTP session, that
+// is as soon as it was decrypted, like arrivalTimeBuffer. The storage of the packets read or
+// dropped is reused for the next ones.
+type trackBuffer struct {
+	mu      sync.Mutex
+	packets []trackBufferPacket // ring of count packets from head
+	head    int
+	count   int
+	free    [][]byte
+	size    int
+	closed  bool
+
+	limitSize int
+	config    trackBufferConfig
+	dropped   uint64
+	last      time.Time
+
+	readable     chan struct{}
+	done         chan struct{}
+	readDeadline *deadline.Deadline
+}
+
+func newTrackBuffer(limitSize int) *trackBuffer {
+	return &trackBuffer{
+		limitSize:    limitSize,
+		config:       trackBufferConfig{policy: TrackBufferPolicyDropNewest},
+		readable:     make(chan struct{}, 1),
+		done:         make(chan struct{}),
+		readDeadline: deadline.New(),
+	}
+}
+
+func (b *trackBuffer) setPolicy(config trackBufferConfig) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.config = config
+}
+
+// full reports whether a packet of n bytes doesn't fit, packetio.Buffer counts 2 more bytes
+// for every packet.
+func (b *trackBuffer) full(n int) bool {
+	return (b.config.size > 0 && b.count >= b.config.size) ||
+		(b.limitSize > 0 && b.size+2+n > b.limitSize)
+}
+
+func (b *trackBuffer) Write(p []byte) (int, error) {
+	arrival := time.Now()
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+
+		return 0, io.ErrClosedPipe
+	}
+	for b.full(len(p)) {
+		if b.config.policy != TrackBufferPolicyDropOldest || b.count == 0 {
+			b.dropped++
+			b.mu.Unlock()
+
+			return 0, packetio.ErrFull
+		}
+		b.recycle(b.pop().data)
+		b.dropped++
+	}
+
+	var data []byte
+	if n := len(b.free); n != 0 && cap(b.free[n-1]) >= len(p) {
+		data = b.free[n-1][:len(p)]
+		b.free[n-1] = nil
+		b.free = b.free[:n-1]
+	} else {
+		data = make([]byte, len(p))
+	}
+	copy(data, p)
+
+	b.push(trackBufferPacket{data: data, arrival: arrival})
+	b.mu.Unlock()
+
+	notifyChannel(b.readable)
+
+	return len(p), nil
+}
+
+// Read returns the next packet, io.ErrShortBuffer if p is too small for it, the rest of the
+// packet is then discarded. It blocks until a packet is written, the buffer is closed or the
+// read deadline expires.
+func (b *trackBuffer) Read(p []byte) (int, error) {
+	for {
+		select {
+		case <-b.readDeadline.Done():
+			return 0, trackBufferTimeoutError{}
+		default:
+		}
+
+		b.mu.Lock()
+		if b.count != 0 {
+			packet := b.pop()
+			b.last = packet.arrival
+			n := copy(p, packet.data)
+			b.recycle(packet.data)
+			b.mu.Unlock()
+
+			if n < len(packet.data) {
+				return n, io.ErrShortBuffer
+			}
+
+			return n, nil
+		}
+		if b.closed {
+			b.mu.Unlock()
+
+			return 0, io.EOF
+		}
+		b.mu.Unlock()
+
+		select {
+		case <-b.readable:
+		case <-b.done:
+		case <-b.readDeadline.Done():
+			return 0, trackBufferTimeoutError{}
+		}
+	}
+}
+
+// push queues a packet, with the lock held. The ring grows when it is full.
+func (b *trackBuffer) push(packet trackBufferPacket) {
+	if b.count == len(b.packets) {
+		packets := make([]trackBufferPacket, 2*len(b.packets)+1)
+		for i := 0; i < b.count; i++ {
+			packets[i] = b.packets[(b.head+i)%len(b.packets)]
+		}
+		b.packets, b.head = packets, 0
+	}
+
+	b.packets[(b.head+b.count)%len(b.packets)] = packet
+	b.count++
+	b.size += len(packet.data) + 2
+}
+
+// pop removes the oldest packet, with the lock held.
+func (b *trackBuffer) pop() trackBufferPacket {
+	packet := b.packets[b.head]
+	b.packets[b.head] = trackBufferPacket{}
+	b.head = (b.head + 1) % len(b.packets)
+	b.count--
+	b.size -= len(packet.data) + 2
+
+	return packet
+}
+
+// recycle keeps the storage of a packet read or dropped for the next Write, with the lock held.
+func (b *trackBuffer) recycle(data []byte) {
+	b.free = append(b.free, data[:0])
+}
+
+// Close unblocks the reads and fails the writes, the packets of the buffer can still be read.
+func (b *trackBuffer) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.closed {
+		b.closed = true
+		close(b.done)
+	}
+
+	return nil
+}
+
+// SetReadDeadline sets the deadline of Read, it is used by srtp.ReadStreamSRTP.
+func (b *trackBuffer) SetReadDeadline(t time.Time) error {
+	b.readDeadline.Set(t)
+
+	return nil
+}
+
+// Size returns the size of the packets of the buffer, see DTLSTransport.bufferedBytes.
+func (b *trackBuffer) Size() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.size
+}
+
+// lastArrival returns the arrival time of the packet returned by the last Read.
+func (b *trackBuffer) lastArrival() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.last
+}
+
+func (b *trackBuffer) droppedPackets() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.dropped
+}
+
+// notifyChannel wakes up a goroutine waiting on c without blocking.
+func notifyChannel(c chan struct{}) {
+	select {
+	case c <- struct{}{}:
+	default:
+	}
+}
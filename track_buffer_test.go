@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/packetio"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackBufferPolicy_String(t *testing.T) {
+	testCases := []struct {
+		policy         TrackBufferPolicy
+		expectedString string
+	}{
+		{TrackBufferPolicyUnknown, ErrUnknownType.Error()},
+		{TrackBufferPolicyDropNewest, "drop-newest"},
+		{TrackBufferPolicyDropOldest, "drop-oldest"},
+	}
+
+	for i, testCase := range testCases {
+		assert.Equal(t, testCase.expectedString, testCase.policy.String(), "testCase: %d %v", i, testCase)
+	}
+}
+
+func TestTrackBuffer(t *testing.T) {
+	readAll := func(t *testing.T, buffer *trackBuffer) []byte {
+		t.Helper()
+
+		read := []byte{}
+		for buffer.Size() != 0 {
+			packet := make([]byte, 1)
+			n, err := buffer.Read(packet)
+			require.NoError(t, err)
+			read = append(read, packet[:n]...)
+		}
+
+		return read
+	}
+
+	t.Run("drop newest", func(t *testing.T) {
+		buffer := newTrackBuffer(srtpBufferSize)
+		buffer.setPolicy(trackBufferConfig{policy: TrackBufferPolicyDropNewest, size: 2})
+
+		for _, packet := range []byte{1, 2, 3} {
+			_, err := buffer.Write([]byte{packet})
+			if packet == 3 {
+				assert.ErrorIs(t, err, packetio.ErrFull)
+			} else {
+				assert.NoError(t, err)
+			}
+		}
+		assert.Equal(t, []byte{1, 2}, readAll(t, buffer))
+		assert.Equal(t, uint64(1), buffer.droppedPackets())
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		buffer := newTrackBuffer(srtpBufferSize)
+		buffer.setPolicy(trackBufferConfig{policy: TrackBufferPolicyDropOldest, size: 2})
+
+		for _, packet := range []byte{1, 2, 3, 4} {
+			_, err := buffer.Write([]byte{packet})
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, []byte{3, 4}, readAll(t, buffer))
+		assert.Equal(t, uint64(2), buffer.droppedPackets())
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		buffer := newTrackBuffer(10)
+		buffer.setPolicy(trackBufferConfig{policy: TrackBufferPolicyDropOldest})
+
+		for _, packet := range []byte{1, 2, 3, 4} {
+			_, err := buffer.Write([]byte{packet, packet})
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 8, buffer.Size())
+		assert.Equal(t, uint64(2), buffer.droppedPackets())
+
+		// A packet larger than the buffer is dropped
+		_, err := buffer.Write(make([]byte, 10))
+		assert.ErrorIs(t, err, packetio.ErrFull)
+		assert.Equal(t, 0, buffer.Size())
+	})
+
+	t.Run("read", func(t *testing.T) {
+		buffer := newTrackBuffer(srtpBufferSize)
+
+		_, err := buffer.Write([]byte{1, 2})
+		assert.NoError(t, err)
+		packet := make([]byte, 1)
+		n, err := buffer.Read(packet)
+		assert.ErrorIs(t, err, io.ErrShortBuffer)
+		assert.Equal(t, 1, n)
+		assert.NotZero(t, buffer.lastArrival())
+
+		assert.NoError(t, buffer.SetReadDeadline(time.Now().Add(10*time.Millisecond)))
+		_, err = buffer.Read(packet)
+		var netErr net.Error
+		require.ErrorAs(t, err, &netErr)
+		assert.True(t, netErr.Timeout())
+		assert.ErrorIs(t, err, packetio.ErrTimeout)
+	})
+
+	t.Run("reuse", func(t *testing.T) {
+		buffer := newTrackBuffer(srtpBufferSize)
+		buffer.setPolicy(trackBufferConfig{policy: TrackBufferPolicyDropOldest, size: 2})
+
+		packet := make([]byte, 100)
+		allocs := testing.AllocsPerRun(100, func() {
+			_, err := buffer.Write(packet)
+			assert.NoError(t, err)
+			_, err = buffer.Write(packet)
+			assert.NoError(t, err)
+			_, err = buffer.Write(packet)
+			assert.NoError(t, err)
+			_, err = buffer.Read(packet)
+			assert.NoError(t, err)
+			_, err = buffer.Read(packet)
+			assert.NoError(t, err)
+		})
+		assert.Zero(t, allocs)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		buffer := newTrackBuffer(srtpBufferSize)
+
+		_, err := buffer.Write([]byte{1})
+		assert.NoError(t, err)
+		assert.NoError(t, buffer.Close())
+		_, err = buffer.Write([]byte{2})
+		assert.ErrorIs(t, err, io.ErrClosedPipe)
+
+		packet := make([]byte, 1)
+		_, err = buffer.Read(packet)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{1}, packet)
+		_, err = buffer.Read(packet)
+		assert.ErrorIs(t, err, io.EOF)
+	})
+}
+
+func TestRTPStreamBuffer(t *testing.T) {
+	read := func(t *testing.T, buffer *rtpStreamBuffer) byte {
+		t.Helper()
+
+		packet := make([]byte, 1)
+		_, err := buffer.Read(packet)
+		require.NoError(t, err)
+
+		return packet[0]
+	}
+
+	buffer := newRTPStreamBuffer(srtpBufferSize)
+	assert.Nil(t, buffer.policy)
+
+	for _, packet := range []byte{1, 2} {
+		_, err := buffer.Write([]byte{packet})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, byte(1), read(t, buffer))
+	assert.NotZero(t, buffer.lastArrival())
+
+	// The packets of the packetio.Buffer are read before the ones queued with the policy
+	buffer.setPolicy(trackBufferConfig{policy: TrackBufferPolicyDropOldest, size: 1})
+	require.NotNil(t, buffer.policy)
+	for _, packet := range []byte{3, 4} {
+		_, err := buffer.Write([]byte{packet})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, (1+2)*2, buffer.Size())
+	assert.Equal(t, byte(2), read(t, buffer))
+	assert.Equal(t, byte(4), read(t, buffer))
+	assert.Equal(t, uint64(1), buffer.droppedPackets())
+
+	assert.NoError(t, buffer.SetReadDeadline(time.Now().Add(10*time.Millisecond)))
+	_, err := buffer.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, packetio.ErrTimeout)
+
+	assert.NoError(t, buffer.SetReadDeadline(time.Time{}))
+	assert.NoError(t, buffer.Close())
+	_, err = buffer.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestRTPReceiver_SetBufferPolicy(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	track, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion")
+	require.NoError(t, err)
+	_, err = pcOffer.AddTrack(track)
+	require.NoError(t, err)
+
+	type remoteTrack struct {
+		track    *TrackRemote
+		receiver *RTPReceiver
+	}
+	remoteTracks := make(chan remoteTrack, 1)
+	pcAnswer.OnTrack(func(track *TrackRemote, receiver *RTPReceiver) {
+		// The track isn't read, its buffer fills up
+		assert.ErrorIs(t, receiver.SetBufferPolicy(TrackBufferPolicyUnknown, 0), errRTPReceiverInvalidBufferPolicy)
+		assert.ErrorIs(t, receiver.SetBufferPolicy(TrackBufferPolicyDropOldest, -1), errRTPReceiverInvalidBufferPolicy)
+		assert.NoError(t, receiver.SetBufferPolicy(TrackBufferPolicyDropOldest, 2))
+		remoteTracks <- remoteTrack{track: track, receiver: receiver}
+	})
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+
+	done := make(chan struct{})
+	go sendVideoUntilDone(t, done, []*TrackLocalStaticSample{track})
+	remote := <-remoteTracks
+
+	buffer := pcAnswer.dtlsTransport.rtpBuffer(remote.track.SSRC())
+	require.NotNil(t, buffer)
+	require.NotNil(t, buffer.policy)
+	assert.Equal(t, trackBufferConfig{policy: TrackBufferPolicyDropOldest, size: 2}, buffer.policy.config)
+
+	func() {
+		for {
+			stats, ok := pcAnswer.GetStats().GetInboundRTPStreamStats(remote.track)
+			require.True(t, ok)
+			if stats.PacketsDroppedByBuffer != 0 {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+
+	close(done)
+	closePairNow(t, pcOffer, pcAnswer)
+}
@@ -48,12 +48,36 @@ type TrackLocalContext interface {
 	RTCPReader() interceptor.RTCPReader
 }
 
+// TrackLocalContextWithMetadata is implemented by the TrackLocalContext passed by a PeerConnection.
+// It is a separate interface so that TrackLocalContext implementations outside of Pion keep
+// satisfying TrackLocalContext, a TrackLocal type-asserts it in Bind to read the metadata.
+type TrackLocalContextWithMetadata interface {
+	TrackLocalContext
+
+	// Metadata returns the application metadata of the bound TrackLocal, or nil if it has none.
+	// See TrackLocalWithMetadata
+	Metadata() interface{}
+}
+
+// TrackLocalWithMetadata is implemented by a TrackLocal that carries application metadata
+// (e.g. a tenant or participant ID). The metadata is opaque to Pion, it is never inspected
+// and is only passed through to TrackLocalContextWithMetadata.Metadata and to interceptors with the
+// AttributeTrackMetadata stream attribute.
+type TrackLocalWithMetadata interface {
+	TrackLocal
+
+	// Metadata returns the application metadata of this track
+	Metadata() interface{}
+}
+
 type baseTrackLocalContext struct {
 	id                     string
 	params                 RTPParameters
 	ssrc, ssrcRTX, ssrcFEC SSRC
 	writeStream            TrackLocalWriter
 	rtcpInterceptor        interceptor.RTCPReader
+	metadata               interface{}
+	random                 *randomSource
 }
 
 // CodecParameters returns the negotiated RTPCodecParameters. These are the codecs supported by both
@@ -99,6 +123,20 @@ func (t *baseTrackLocalContext) RTCPReader() interceptor.RTCPReader {
 	return t.rtcpInterceptor
 }
 
+// Metadata returns the application metadata of the bound TrackLocal, or nil if it has none.
+func (t *baseTrackLocalContext) Metadata() interface{} {
+	return t.metadata
+}
+
+// trackLocalMetadata returns the metadata of a TrackLocal if it implements TrackLocalWithMetadata.
+func trackLocalMetadata(track TrackLocal) interface{} {
+	if t, ok := track.(TrackLocalWithMetadata); ok {
+		return t.Metadata()
+	}
+
+	return nil
+}
+
 // TrackLocal is an interface that controls how the user can send media
 // The user can provide their own TrackLocal implementations, or use
 // the implementations in pkg/media.
@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pion/rtp"
+	"github.com/pion/rtp/codecs"
+)
+
+// WithKeyFrameCache makes the TrackLocalStaticRTP keep a copy of every packet of the last
+// keyframe written to it, and replay them to a PeerConnection the track is bound to
+// afterwards, right before the next packet it sends. A late joiner can then start decoding
+// without waiting for the next keyframe of the source. The replayed packets keep their
+// timestamps, their sequence numbers are rewritten to directly precede the one of the next
+// packet, so the remote receives a contiguous stream and sees neither a loss nor a
+// sequence number going backwards.
+//
+// Keyframes are detected for VP8 and H264, the packets of other codecs are not cached.
+// The cache holds a whole keyframe per track, which can be hundreds of kilobytes for high
+// resolutions, and it is kept as long as the track. Use OnKeyFrameNeeded to request a
+// keyframe from the source instead when there are many tracks or memory is tight.
+func WithKeyFrameCache() func(*TrackLocalStaticRTP) {
+	return func(s *TrackLocalStaticRTP) {
+		s.keyFrameCache = &keyFrameCache{}
+	}
+}
+
+// OnKeyFrameNeeded sets a handler that is called when the track is bound to a
+// PeerConnection and no keyframe can be replayed to it from the cache enabled with
+// WithKeyFrameCache. SFUs can use it to request a keyframe from the source, for
+// example with TrackRemote.RequestKeyFrame.
+func (s *TrackLocalStaticRTP) OnKeyFrameNeeded(handler func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onKeyFrameNeededHandler = handler
+}
+
+// OnKeyFrameNeeded sets a handler that is called when the track is bound to a
+// PeerConnection and no keyframe can be replayed to it, see
+// TrackLocalStaticRTP.OnKeyFrameNeeded.
+func (s *TrackLocalStaticSample) OnKeyFrameNeeded(handler func()) {
+	s.rtpTrack.OnKeyFrameNeeded(handler)
+}
+
+// keyFrameCache stores the packets of the last keyframe written to a track and the
+// bindings it still has to be replayed to.
+type keyFrameCache struct {
+	mu         sync.Mutex
+	packets    []*rtp.Packet
+	timestamp  uint32
+	collecting bool
+	pending    map[string]struct{}
+}
+
+// addBinding marks the binding with id to receive the cached keyframe, it returns
+// false if there is no keyframe to replay.
+func (c *keyFrameCache) addBinding(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.packets) == 0 {
+		return false
+	}
+
+	if c.pending == nil {
+		c.pending = map[string]struct{}{}
+	}
+	c.pending[id] = struct{}{}
+
+	return true
+}
+
+func (c *keyFrameCache) removeBinding(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.pending, id)
+}
+
+// update adds packet to the cache and returns the cached packets to send to each pending
+// binding before it. The packets returned must not be modified.
+func (c *keyFrameCache) update(packet *rtp.Packet, mimeType string) map[string][]*rtp.Packet {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if isKeyFrameStart(packet, mimeType) {
+		// The pending bindings get this keyframe as it is sent
+		c.packets = []*rtp.Packet{packet.Clone()}
+		c.timestamp = packet.Timestamp
+		c.collecting = true
+		c.pending = nil
+
+		return nil
+	}
+
+	var replay map[string][]*rtp.Packet
+	if len(c.pending) != 0 {
+		replay = make(map[string][]*rtp.Packet, len(c.pending))
+		for id := range c.pending {
+			replay[id] = c.packets
+		}
+		c.pending = nil
+	}
+
+	if c.collecting && packet.Timestamp == c.timestamp {
+		// Appending never modifies the packets returned above
+		c.packets = append(c.packets[:len(c.packets):len(c.packets)], packet.Clone())
+	} else {
+		c.collecting = false
+	}
+
+	return replay
+}
+
+// replayKeyFrame writes the cached packets of a keyframe to the binding, numbered to precede
+// the packet with sequence number next. sent is false if the write stream dropped them
+// because it isn't ready yet.
+func (b *trackBinding) replayKeyFrame(packets []*rtp.Packet, next uint16) (sent bool, err error) {
+	for i, cached := range packets {
+		header := cached.Header.Clone()
+		header.SequenceNumber = next - uint16(len(packets)-i)
+		header.SSRC = uint32(b.ssrc)
+		header.PayloadType = uint8(b.payloadType)
+		n, err := b.writeStream.WriteRTP(&header, cached.Payload)
+		if err != nil {
+			return false, err
+		} else if n == 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// isKeyFrameStart reports whether packet is the first packet of a keyframe.
+func isKeyFrameStart(packet *rtp.Packet, mimeType string) bool {
+	payload := packet.Payload
+	switch {
+	case strings.EqualFold(mimeType, MimeTypeVP8):
+		vp8 := codecs.VP8Packet{}
+		if _, err := vp8.Unmarshal(payload); err != nil || len(vp8.Payload) == 0 {
+			return false
+		}
+
+		// Start of partition 0 with the P bit of the frame tag unset
+		return vp8.S == 1 && vp8.PID == 0 && vp8.Payload[0]&0x01 == 0
+	case strings.EqualFold(mimeType, MimeTypeH264):
+		return isH264KeyFrameStart(payload)
+	default:
+		return false
+	}
+}
+
+// isH264KeyFrameStart reports whether the payload starts a frame with an IDR picture,
+// or the SPS sent in front of it.
+func isH264KeyFrameStart(payload []byte) bool {
+	const (
+		naluTypeIDR  = 5
+		naluTypeSPS  = 7
+		naluTypeSTAP = 24
+		naluTypeFUA  = 28
+	)
+
+	if len(payload) == 0 {
+		return false
+	}
+
+	switch naluType := payload[0] & 0x1f; naluType {
+	case naluTypeIDR, naluTypeSPS:
+		return true
+	case naluTypeSTAP:
+		for offset := 1; offset+2 < len(payload); {
+			size := int(payload[offset])<<8 | int(payload[offset+1])
+			if naluType := payload[offset+2] & 0x1f; naluType == naluTypeIDR || naluType == naluTypeSPS {
+				return true
+			}
+			offset += 2 + size
+		}
+	case naluTypeFUA:
+		return len(payload) > 1 && payload[1]&0x80 != 0 && payload[1]&0x1f == naluTypeIDR
+	}
+
+	return false
+}
@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsKeyFrameStart(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		mimeType string
+		payload  []byte
+		keyFrame bool
+	}{
+		{"VP8 keyframe", MimeTypeVP8, []byte{0x10, 0x00, 0x01}, true},
+		{"VP8 interframe", MimeTypeVP8, []byte{0x10, 0x01, 0x01}, false},
+		{"VP8 continuation", MimeTypeVP8, []byte{0x00, 0x00, 0x01}, false},
+		{"VP8 empty", MimeTypeVP8, []byte{}, false},
+		{"H264 IDR", MimeTypeH264, []byte{0x65, 0x01}, true},
+		{"H264 SPS", MimeTypeH264, []byte{0x67, 0x01}, true},
+		{"H264 non IDR slice", MimeTypeH264, []byte{0x41, 0x01}, false},
+		{"H264 STAP-A with SPS", MimeTypeH264, []byte{0x78, 0x00, 0x02, 0x09, 0x10, 0x00, 0x02, 0x67, 0x01}, true},
+		{"H264 STAP-A without SPS", MimeTypeH264, []byte{0x78, 0x00, 0x02, 0x09, 0x10}, false},
+		{"H264 FU-A IDR start", MimeTypeH264, []byte{0x7c, 0x85, 0x01}, true},
+		{"H264 FU-A IDR middle", MimeTypeH264, []byte{0x7c, 0x05, 0x01}, false},
+		{"Opus", MimeTypeOpus, []byte{0x10, 0x00}, false},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.keyFrame, isKeyFrameStart(&rtp.Packet{Payload: testCase.payload}, testCase.mimeType))
+		})
+	}
+}
+
+func TestKeyFrameCache(t *testing.T) {
+	cache := &keyFrameCache{}
+	assert.False(t, cache.addBinding("a"), "nothing to replay before a keyframe")
+
+	keyFrame := []*rtp.Packet{
+		{Header: rtp.Header{SequenceNumber: 1, Timestamp: 10}, Payload: []byte{0x10, 0x00}},
+		{Header: rtp.Header{SequenceNumber: 2, Timestamp: 10}, Payload: []byte{0x00, 0x01}},
+	}
+	for _, pkt := range keyFrame {
+		assert.Nil(t, cache.update(pkt, MimeTypeVP8))
+	}
+	assert.Nil(t, cache.update(&rtp.Packet{Header: rtp.Header{SequenceNumber: 3, Timestamp: 20}, Payload: []byte{0x10, 0x01}}, MimeTypeVP8))
+
+	assert.True(t, cache.addBinding("a"))
+	assert.True(t, cache.addBinding("b"))
+	cache.removeBinding("b")
+
+	replay := cache.update(&rtp.Packet{Header: rtp.Header{SequenceNumber: 4, Timestamp: 30}, Payload: []byte{0x10, 0x01}}, MimeTypeVP8)
+	assert.Equal(t, map[string][]*rtp.Packet{"a": keyFrame}, replay)
+	assert.Nil(t, cache.update(&rtp.Packet{Header: rtp.Header{SequenceNumber: 5, Timestamp: 40}}, MimeTypeVP8))
+
+	// A binding waiting for the cached keyframe gets the next one instead
+	assert.True(t, cache.addBinding("a"))
+	assert.Nil(t, cache.update(&rtp.Packet{Header: rtp.Header{SequenceNumber: 6, Timestamp: 50}, Payload: []byte{0x10, 0x00}}, MimeTypeVP8))
+	assert.Nil(t, cache.update(&rtp.Packet{Header: rtp.Header{SequenceNumber: 7, Timestamp: 60}}, MimeTypeVP8))
+}
+
+func Test_TrackLocalStatic_KeyFrameCache(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	track, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion", WithKeyFrameCache())
+	require.NoError(t, err)
+
+	var keyFrameNeeded atomic.Bool
+	track.OnKeyFrameNeeded(func() {
+		keyFrameNeeded.Store(true)
+	})
+
+	// The keyframe is written before the track is bound to the late joiner
+	keyFrame := []*rtp.Packet{
+		{Header: rtp.Header{Version: 2, SequenceNumber: 1, Timestamp: 1000}, Payload: []byte{0x10, 0x00, 0xaa}},
+		{Header: rtp.Header{Version: 2, SequenceNumber: 2, Timestamp: 1000}, Payload: []byte{0x00, 0xbb}},
+	}
+	for _, pkt := range keyFrame {
+		require.NoError(t, track.WriteRTP(pkt))
+	}
+
+	uncachedTrack, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeVP8}, "uncached", "pion")
+	require.NoError(t, err)
+
+	uncachedKeyFrameNeeded := make(chan struct{})
+	uncachedTrack.OnKeyFrameNeeded(func() {
+		close(uncachedKeyFrameNeeded)
+	})
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	_, err = pcOffer.AddTrack(track)
+	require.NoError(t, err)
+	_, err = pcOffer.AddTrack(uncachedTrack)
+	require.NoError(t, err)
+
+	received := make(chan []*rtp.Packet, 1)
+	onTrackFired, onTrackFiredFunc := context.WithCancel(context.Background())
+	pcAnswer.OnTrack(func(trackRemote *TrackRemote, _ *RTPReceiver) {
+		if trackRemote.ID() != track.ID() {
+			return
+		}
+
+		// The replayed keyframe and the live packet after it
+		var pkts []*rtp.Packet
+		for i := 0; i <= len(keyFrame); i++ {
+			pkt, _, readErr := trackRemote.ReadRTP()
+			if !assert.NoError(t, readErr) {
+				break
+			}
+			pkts = append(pkts, pkt)
+		}
+		received <- pkts
+		onTrackFiredFunc()
+	})
+
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	<-uncachedKeyFrameNeeded
+
+	for sequenceNumber := uint16(3); onTrackFired.Err() == nil; sequenceNumber++ {
+		time.Sleep(20 * time.Millisecond)
+
+		assert.NoError(t, track.WriteRTP(&rtp.Packet{
+			Header:  rtp.Header{Version: 2, SequenceNumber: sequenceNumber, Timestamp: uint32(sequenceNumber) * 1000},
+			Payload: []byte{0x10, 0x01, 0xcc},
+		}))
+	}
+
+	pkts := <-received
+	require.Len(t, pkts, len(keyFrame)+1)
+	live := pkts[len(keyFrame)]
+	for i, pkt := range pkts[:len(keyFrame)] {
+		// The sequence numbers are contiguous up to the live packet
+		assert.Equal(t, live.SequenceNumber-uint16(len(keyFrame)-i), pkt.SequenceNumber)
+		assert.Equal(t, keyFrame[i].Timestamp, pkt.Timestamp)
+		assert.Equal(t, keyFrame[i].Payload, pkt.Payload)
+	}
+	assert.Equal(t, []byte{0x10, 0x01, 0xcc}, live.Payload)
+	assert.False(t, keyFrameNeeded.Load())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}
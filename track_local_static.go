@@ -9,6 +9,7 @@ package webrtc
 import (
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/pion/rtp"
 	"github.com/pion/webrtc/v4/internal/util"
@@ -23,6 +24,9 @@ type trackBinding struct {
 	ssrc, ssrcRTX, ssrcFEC      SSRC
 	payloadType, payloadTypeRTX PayloadType
 	writeStream                 TrackLocalWriter
+
+	// all codecs negotiated for this binding, used by SwitchCodec
+	codecs []RTPCodecParameters
 }
 
 // TrackLocalStaticRTP  is a TrackLocal that has a pre-set codec and accepts RTP Packets.
@@ -34,6 +38,15 @@ type TrackLocalStaticRTP struct {
 	payloader         func(RTPCodecCapability) (rtp.Payloader, error)
 	id, rid, streamID string
 	rtpTimestamp      *uint32
+	metadata          interface{}
+
+	switchOnPayloadType  bool
+	onCodecSwitchHandler func(RTPCodecCapability)
+
+	keyFrameCache           *keyFrameCache
+	onKeyFrameNeededHandler func()
+
+	writeGroup *TrackLocalWriteGroup
 }
 
 // NewTrackLocalStaticRTP returns a TrackLocalStaticRTP.
@@ -77,6 +90,24 @@ func WithRTPTimestamp(timestamp uint32) func(*TrackLocalStaticRTP) {
 	}
 }
 
+// WithMetadata attaches opaque application metadata to the track. It is
+// not used by Pion, but is exposed through TrackLocalContext and interceptors.
+func WithMetadata(metadata interface{}) func(*TrackLocalStaticRTP) {
+	return func(s *TrackLocalStaticRTP) {
+		s.metadata = metadata
+	}
+}
+
+// WithPayloadTypeCodecSwitch makes WriteRTP switch the codec of the track, as with SwitchCodec,
+// when it is given a packet whose payload type belongs to another negotiated codec. The payload
+// type is looked up among the codecs negotiated by the PeerConnections the track is bound to,
+// so writers should use the payload types of the MediaEngine.
+func WithPayloadTypeCodecSwitch() func(*TrackLocalStaticRTP) {
+	return func(s *TrackLocalStaticRTP) {
+		s.switchOnPayloadType = true
+	}
+}
+
 // Bind is called by the PeerConnection after negotiation is complete
 // This asserts that the code requested is supported by the remote peer.
 // If so it sets up all the state (SSRC and PayloadType) to have a call.
@@ -97,8 +128,15 @@ func (s *TrackLocalStaticRTP) Bind(trackContext TrackLocalContext) (RTPCodecPara
 			payloadTypeRTX: findRTXPayloadType(codec.PayloadType, trackContext.CodecParameters()),
 			writeStream:    trackContext.WriteStream(),
 			id:             trackContext.ID(),
+			codecs:         trackContext.CodecParameters(),
 		})
 
+		if s.keyFrameCache == nil || !s.keyFrameCache.addBinding(trackContext.ID()) {
+			if handler := s.onKeyFrameNeededHandler; handler != nil {
+				go handler()
+			}
+		}
+
 		return codec, nil
 	}
 
@@ -113,6 +151,9 @@ func (s *TrackLocalStaticRTP) Unbind(t TrackLocalContext) error {
 
 	for i := range s.bindings {
 		if s.bindings[i].id == t.ID() {
+			if s.keyFrameCache != nil {
+				s.keyFrameCache.removeBinding(t.ID())
+			}
 			s.bindings[i] = s.bindings[len(s.bindings)-1]
 			s.bindings = s.bindings[:len(s.bindings)-1]
 
@@ -134,6 +175,9 @@ func (s *TrackLocalStaticRTP) StreamID() string { return s.streamID }
 // RID is the RTP stream identifier.
 func (s *TrackLocalStaticRTP) RID() string { return s.rid }
 
+// Metadata returns the application metadata set with WithMetadata.
+func (s *TrackLocalStaticRTP) Metadata() interface{} { return s.metadata }
+
 // Kind controls if this TrackLocal is audio or video.
 func (s *TrackLocalStaticRTP) Kind() RTPCodecType {
 	switch {
@@ -151,6 +195,97 @@ func (s *TrackLocalStaticRTP) Codec() RTPCodecCapability {
 	return s.codec
 }
 
+// SwitchCodec changes the codec the track is sent with, without renegotiation. This allows
+// falling back to a secondary codec when the encoder of the preferred one fails.
+//
+// The codec must have been negotiated with every PeerConnection the track is bound to: it has
+// to be registered in the MediaEngine and accepted by the remote, so that it appears in the
+// m-line next to the preferred codec. Otherwise ErrUnsupportedCodec is returned and the track
+// is left unchanged. A track that isn't bound yet will use the codec on its next Bind.
+// Retransmissions keep using the interceptors set up for the codec bound
+// first. The handler set with OnCodecSwitch is called after the switch.
+func (s *TrackLocalStaticRTP) SwitchCodec(codec RTPCodecCapability) error {
+	s.mu.Lock()
+	_, handler, err := s.switchCodec(codec, nil)
+	s.mu.Unlock()
+
+	if err == nil && handler != nil {
+		handler(codec)
+	}
+
+	return err
+}
+
+// OnCodecSwitch sets a handler that is called after the codec of the track changed. The
+// receiver can't decode the new codec until it gets a keyframe, so the handler should request
+// one from the encoder or from the source of the forwarded media.
+func (s *TrackLocalStaticRTP) OnCodecSwitch(handler func(codec RTPCodecCapability)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onCodecSwitchHandler = handler
+}
+
+// switchCodec switches the bindings to codec and returns the codec negotiated by the first
+// binding and the OnCodecSwitch handler to call, s.mu must be held. prepare, if not nil, is
+// called with the codec of the first binding before anything is changed, the track is left
+// unchanged if it fails.
+func (s *TrackLocalStaticRTP) switchCodec(
+	codec RTPCodecCapability,
+	prepare func(RTPCodecParameters) error,
+) (RTPCodecParameters, func(RTPCodecCapability), error) {
+	needle := RTPCodecParameters{RTPCodecCapability: codec}
+	negotiated := make([]RTPCodecParameters, len(s.bindings))
+	for i := range s.bindings {
+		match, matchType := codecParametersFuzzySearch(needle, s.bindings[i].codecs)
+		if matchType == codecMatchNone {
+			return RTPCodecParameters{}, nil, ErrUnsupportedCodec
+		}
+		negotiated[i] = match
+	}
+
+	first := needle
+	if len(negotiated) != 0 {
+		first = negotiated[0]
+	}
+	if prepare != nil {
+		if err := prepare(first); err != nil {
+			return RTPCodecParameters{}, nil, err
+		}
+	}
+
+	for i := range s.bindings {
+		s.bindings[i].payloadType = negotiated[i].PayloadType
+		s.bindings[i].payloadTypeRTX = findRTXPayloadType(negotiated[i].PayloadType, s.bindings[i].codecs)
+	}
+	s.codec = codec
+
+	return first, s.onCodecSwitchHandler, nil
+}
+
+// codecForPayloadType returns the negotiated codec with the payload type if it
+// isn't the codec currently sent.
+func (s *TrackLocalStaticRTP) codecForPayloadType(payloadType PayloadType) (RTPCodecCapability, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, b := range s.bindings {
+		if b.payloadType == payloadType {
+			return RTPCodecCapability{}, false
+		}
+	}
+
+	for _, b := range s.bindings {
+		for _, codec := range b.codecs {
+			if codec.PayloadType == payloadType && !strings.EqualFold(codec.MimeType, MimeTypeRTX) {
+				return codec.RTPCodecCapability, true
+			}
+		}
+	}
+
+	return RTPCodecCapability{}, false
+}
+
 // packetPool is a pool of packets used by WriteRTP and Write below
 // nolint:gochecknoglobals
 var rtpPacketPool = sync.Pool{
@@ -175,6 +310,14 @@ func getPacketAllocationFromPool() *rtp.Packet {
 // all PeerConnections. The error message will contain the ID of the failed
 // PeerConnections so you can remove them.
 func (s *TrackLocalStaticRTP) WriteRTP(p *rtp.Packet) error {
+	s.writeGroup.lock()
+	defer s.writeGroup.unlock()
+
+	return s.writeRTPCopy(p)
+}
+
+// writeRTPCopy is like WriteRTP, without waiting for the write group of the track.
+func (s *TrackLocalStaticRTP) writeRTPCopy(p *rtp.Packet) error {
 	packet := getPacketAllocationFromPool()
 
 	defer resetPacketPoolAllocation(packet)
@@ -186,12 +329,34 @@ func (s *TrackLocalStaticRTP) WriteRTP(p *rtp.Packet) error {
 
 // writeRTP is like WriteRTP, except that it may modify the packet p.
 func (s *TrackLocalStaticRTP) writeRTP(packet *rtp.Packet) error {
+	if s.switchOnPayloadType {
+		if codec, ok := s.codecForPayloadType(PayloadType(packet.PayloadType)); ok {
+			if err := s.SwitchCodec(codec); err != nil {
+				return err
+			}
+		}
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	var replay map[string][]*rtp.Packet
+	if s.keyFrameCache != nil {
+		replay = s.keyFrameCache.update(packet, s.codec.MimeType)
+	}
+
 	writeErrs := []error{}
 
 	for _, b := range s.bindings {
+		if cached, ok := replay[b.id]; ok {
+			if sent, err := b.replayKeyFrame(cached, packet.SequenceNumber); err != nil {
+				writeErrs = append(writeErrs, err)
+			} else if !sent {
+				// The transport isn't ready yet, try again with the next packet
+				s.keyFrameCache.addBinding(b.id)
+			}
+		}
+
 		packet.Header.SSRC = uint32(b.ssrc)
 		packet.Header.PayloadType = uint8(b.payloadType)
 		if _, err := b.writeStream.WriteRTP(&packet.Header, packet.Payload); err != nil {
@@ -215,6 +380,9 @@ func (s *TrackLocalStaticRTP) Write(b []byte) (n int, err error) {
 		return 0, err
 	}
 
+	s.writeGroup.lock()
+	defer s.writeGroup.unlock()
+
 	return len(b), s.writeRTP(packet)
 }
 
@@ -225,6 +393,9 @@ type TrackLocalStaticSample struct {
 	sequencer  rtp.Sequencer
 	rtpTrack   *TrackLocalStaticRTP
 	clockRate  float64
+
+	// RTP timestamp of the next sample, used to keep timestamps continuous on SwitchCodec
+	nextTimestamp atomic.Uint32
 }
 
 // NewTrackLocalStaticSample returns a TrackLocalStaticSample.
@@ -237,7 +408,6 @@ func NewTrackLocalStaticSample(
 	if err != nil {
 		return nil, err
 	}
-
 	return &TrackLocalStaticSample{
 		rtpTrack: rtpTrack,
 	}, nil
@@ -254,6 +424,9 @@ func (s *TrackLocalStaticSample) StreamID() string { return s.rtpTrack.StreamID(
 // RID is the RTP stream identifier.
 func (s *TrackLocalStaticSample) RID() string { return s.rtpTrack.RID() }
 
+// Metadata returns the application metadata set with WithMetadata.
+func (s *TrackLocalStaticSample) Metadata() interface{} { return s.rtpTrack.Metadata() }
+
 // Kind controls if this TrackLocal is audio or video.
 func (s *TrackLocalStaticSample) Kind() RTPCodecType { return s.rtpTrack.Kind() }
 
@@ -279,6 +452,21 @@ func (s *TrackLocalStaticSample) Bind(t TrackLocalContext) (RTPCodecParameters,
 		return codec, nil
 	}
 
+	random := randomSourceFromContext(t)
+	s.sequencer = random.sequencer()
+
+	// The first timestamp is known so that WriteComfortNoise can be called before any sample
+	timestamp := random.uint32()
+	if s.rtpTrack.rtpTimestamp != nil {
+		timestamp = *s.rtpTrack.rtpTimestamp
+	}
+	s.nextTimestamp.Store(timestamp)
+
+	return codec, s.createPacketizer(codec, rtp.WithTimestamp(timestamp))
+}
+
+// createPacketizer creates the packetizer for codec, s.rtpTrack.mu must be held.
+func (s *TrackLocalStaticSample) createPacketizer(codec RTPCodecParameters, options ...rtp.PacketizerOption) error {
 	payloadHandler := s.rtpTrack.payloader
 	if payloadHandler == nil {
 		payloadHandler = payloaderForCodec
@@ -286,15 +474,7 @@ func (s *TrackLocalStaticSample) Bind(t TrackLocalContext) (RTPCodecParameters,
 
 	payloader, err := payloadHandler(codec.RTPCodecCapability)
 	if err != nil {
-		return codec, err
-	}
-
-	s.sequencer = rtp.NewRandomSequencer()
-
-	options := []rtp.PacketizerOption{}
-
-	if s.rtpTrack.rtpTimestamp != nil {
-		options = append(options, rtp.WithTimestamp(*s.rtpTrack.rtpTimestamp))
+		return err
 	}
 
 	s.packetizer = rtp.NewPacketizerWithOptions(
@@ -307,7 +487,34 @@ func (s *TrackLocalStaticSample) Bind(t TrackLocalContext) (RTPCodecParameters,
 
 	s.clockRate = float64(codec.RTPCodecCapability.ClockRate)
 
-	return codec, nil
+	return nil
+}
+
+// SwitchCodec changes the codec the track is sent with, without renegotiation, see
+// TrackLocalStaticRTP.SwitchCodec. Samples written afterwards must be encoded with the new
+// codec. Sequence numbers and timestamps continue from the previous codec.
+func (s *TrackLocalStaticSample) SwitchCodec(codec RTPCodecCapability) error {
+	s.rtpTrack.mu.Lock()
+	_, handler, err := s.rtpTrack.switchCodec(codec, func(negotiated RTPCodecParameters) error {
+		if s.packetizer == nil {
+			return nil
+		}
+
+		return s.createPacketizer(negotiated, rtp.WithTimestamp(s.nextTimestamp.Load()))
+	})
+	s.rtpTrack.mu.Unlock()
+
+	if err == nil && handler != nil {
+		handler(codec)
+	}
+
+	return err
+}
+
+// OnCodecSwitch sets a handler that is called after the codec of the track changed,
+// see TrackLocalStaticRTP.OnCodecSwitch.
+func (s *TrackLocalStaticSample) OnCodecSwitch(handler func(codec RTPCodecCapability)) {
+	s.rtpTrack.OnCodecSwitch(handler)
 }
 
 // Unbind implements the teardown logic when the track is no longer needed. This happens
@@ -321,6 +528,14 @@ func (s *TrackLocalStaticSample) Unbind(t TrackLocalContext) error {
 // all PeerConnections. The error message will contain the ID of the failed
 // PeerConnections so you can remove them.
 func (s *TrackLocalStaticSample) WriteSample(sample media.Sample) error {
+	s.rtpTrack.writeGroup.lock()
+	defer s.rtpTrack.writeGroup.unlock()
+
+	return s.writeSample(sample)
+}
+
+// writeSample is like WriteSample, without waiting for the write group of the track.
+func (s *TrackLocalStaticSample) writeSample(sample media.Sample) error {
 	s.rtpTrack.mu.RLock()
 	packetizer := s.packetizer
 	clockRate := s.clockRate
@@ -340,10 +555,13 @@ func (s *TrackLocalStaticSample) WriteSample(sample media.Sample) error {
 		packetizer.SkipSamples(samples * uint32(sample.PrevDroppedPackets))
 	}
 	packets := packetizer.Packetize(sample.Data, samples)
+	if len(packets) != 0 {
+		s.nextTimestamp.Store(packets[0].Timestamp + samples)
+	}
 
 	writeErrs := []error{}
 	for _, p := range packets {
-		if err := s.rtpTrack.WriteRTP(p); err != nil {
+		if err := s.rtpTrack.writeRTPCopy(p); err != nil {
 			writeErrs = append(writeErrs, err)
 		}
 	}
@@ -366,9 +584,12 @@ func (s *TrackLocalStaticSample) GeneratePadding(samples uint32) error {
 
 	packets := p.GeneratePadding(samples)
 
+	s.rtpTrack.writeGroup.lock()
+	defer s.rtpTrack.writeGroup.unlock()
+
 	writeErrs := []error{}
 	for _, p := range packets {
-		if err := s.rtpTrack.WriteRTP(p); err != nil {
+		if err := s.rtpTrack.writeRTPCopy(p); err != nil {
 			writeErrs = append(writeErrs, err)
 		}
 	}
@@ -466,3 +466,132 @@ func Test_TrackLocalStatic_Timestamp(t *testing.T) {
 	<-onTrackFired.Done()
 	closePairNow(t, pcOffer, pcAnswer)
 }
+
+func Test_TrackLocalStatic_SwitchCodec(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	findPayloadType := func(codecs []RTPCodecParameters, mimeType string) PayloadType {
+		for _, codec := range codecs {
+			if codec.MimeType == mimeType {
+				return codec.PayloadType
+			}
+		}
+
+		return 0
+	}
+
+	// Waits until the remote track receives a packet with the payload type of mimeType
+	untilPayloadType := func(pc *PeerConnection, mimeType string) context.Context {
+		received, receivedFunc := context.WithCancel(context.Background())
+		pc.OnTrack(func(trackRemote *TrackRemote, receiver *RTPReceiver) {
+			payloadType := findPayloadType(receiver.GetParameters().Codecs, mimeType)
+			for {
+				pkt, _, err := trackRemote.ReadRTP()
+				if err != nil {
+					return
+				}
+				if pkt.PayloadType == uint8(payloadType) {
+					receivedFunc()
+				}
+			}
+		})
+
+		return received
+	}
+
+	t.Run("Sample", func(t *testing.T) {
+		pcOffer, pcAnswer, err := newPair()
+		require.NoError(t, err)
+
+		errNoPayloader := errors.New("no payloader")
+		track, err := NewTrackLocalStaticSample(
+			RTPCodecCapability{MimeType: MimeTypeOpus}, "audio", "pion",
+			WithPayloader(func(codec RTPCodecCapability) (rtp.Payloader, error) {
+				if codec.MimeType == MimeTypePCMA {
+					return nil, errNoPayloader
+				}
+
+				return payloaderForCodec(codec)
+			}),
+		)
+		require.NoError(t, err)
+		_, err = pcOffer.AddTrack(track)
+		require.NoError(t, err)
+
+		switched := make(chan RTPCodecCapability, 1)
+		track.OnCodecSwitch(func(codec RTPCodecCapability) {
+			switched <- codec
+		})
+
+		received := untilPayloadType(pcAnswer, MimeTypePCMU)
+		connected := untilConnectionState(PeerConnectionStateConnected, pcOffer, pcAnswer)
+		require.NoError(t, signalPair(pcOffer, pcAnswer))
+		connected.Wait()
+
+		// Codecs that weren't negotiated are rejected
+		assert.ErrorIs(t, track.SwitchCodec(RTPCodecCapability{MimeType: "audio/unknown"}), ErrUnsupportedCodec)
+		assert.Equal(t, MimeTypeOpus, track.Codec().MimeType)
+
+		// The bindings keep the codec when no packetizer can be created for the new one
+		payloadType := track.rtpTrack.bindings[0].payloadType
+		assert.ErrorIs(t, track.SwitchCodec(RTPCodecCapability{MimeType: MimeTypePCMA}), errNoPayloader)
+		assert.Equal(t, MimeTypeOpus, track.Codec().MimeType)
+		assert.Equal(t, payloadType, track.rtpTrack.bindings[0].payloadType)
+
+		require.NoError(t, track.SwitchCodec(RTPCodecCapability{MimeType: MimeTypePCMU}))
+		assert.Equal(t, MimeTypePCMU, (<-switched).MimeType)
+		assert.Equal(t, MimeTypePCMU, track.Codec().MimeType)
+
+		sendVideoUntilDone(t, received.Done(), []*TrackLocalStaticSample{track})
+
+		closePairNow(t, pcOffer, pcAnswer)
+	})
+
+	t.Run("PayloadType", func(t *testing.T) {
+		pcOffer, pcAnswer, err := newPair()
+		require.NoError(t, err)
+
+		track, err := NewTrackLocalStaticRTP(
+			RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion", WithPayloadTypeCodecSwitch(),
+		)
+		require.NoError(t, err)
+		sender, err := pcOffer.AddTrack(track)
+		require.NoError(t, err)
+
+		switched := make(chan RTPCodecCapability, 1)
+		track.OnCodecSwitch(func(codec RTPCodecCapability) {
+			switched <- codec
+		})
+
+		received := untilPayloadType(pcAnswer, MimeTypeVP9)
+		require.NoError(t, signalPair(pcOffer, pcAnswer))
+
+		payloadType := findPayloadType(sender.GetParameters().Codecs, MimeTypeVP9)
+		require.NotZero(t, payloadType)
+
+		func() {
+			ticker := time.NewTicker(20 * time.Millisecond)
+			defer ticker.Stop()
+			for sequenceNumber := uint16(0); ; sequenceNumber++ {
+				select {
+				case <-received.Done():
+					return
+				case <-ticker.C:
+					assert.NoError(t, track.WriteRTP(&rtp.Packet{
+						Header:  rtp.Header{Version: 2, SequenceNumber: sequenceNumber, PayloadType: uint8(payloadType)},
+						Payload: []byte{0x00},
+					}))
+				}
+			}
+		}()
+
+		assert.Equal(t, MimeTypeVP9, (<-switched).MimeType)
+		assert.Equal(t, MimeTypeVP9, track.Codec().MimeType)
+
+		closePairNow(t, pcOffer, pcAnswer)
+	})
+}
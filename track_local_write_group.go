@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/internal/util"
+	"github.com/pion/webrtc/v4/pkg/media"
+)
+
+// TrackLocalWriteGroup keeps the packets of tightly coupled tracks, such as the audio and
+// video of a single source, together in send order. Tracks join a group when they are
+// created with WithWriteGroup. The writes of the tracks of a group never interleave: each
+// WriteRTP, Write or WriteSample sends all its packets before the next write of any track
+// of the group starts, and Write sends the packets of several tracks as a single unit.
+//
+// The group only orders the writes, it isn't a pacer and doesn't hint one. Packets are
+// handed to the interceptors and the transports of the tracks in order, but tracks that
+// are not in the group, RTCP, retransmissions and interceptors that queue packets, such
+// as a pacer, can still send other packets in between or reorder them.
+type TrackLocalWriteGroup struct {
+	mu sync.Mutex
+}
+
+// NewTrackLocalWriteGroup returns a TrackLocalWriteGroup without tracks.
+func NewTrackLocalWriteGroup() *TrackLocalWriteGroup {
+	return &TrackLocalWriteGroup{}
+}
+
+// WithWriteGroup adds the track to group, see TrackLocalWriteGroup. It can also be passed
+// to NewTrackLocalStaticSample.
+func WithWriteGroup(group *TrackLocalWriteGroup) func(*TrackLocalStaticRTP) {
+	return func(s *TrackLocalStaticRTP) {
+		s.writeGroup = group
+	}
+}
+
+// Write calls f with a writer for the tracks of the group. The packets f writes with it are
+// queued, and sent together in the order they were written once f returns, before any
+// other write to the tracks of the group. Nothing is sent if f returns an error. The writer
+// must not be used once f returns. f can write to the tracks directly, or call Write again,
+// these writes are sent before the ones queued by f.
+func (g *TrackLocalWriteGroup) Write(f func(w *TrackLocalGroupWriter) error) error {
+	writer := &TrackLocalGroupWriter{group: g}
+	err := f(writer)
+	writer.group = nil
+	if err != nil {
+		return err
+	}
+
+	g.lock()
+	defer g.unlock()
+
+	writeErrs := []error{}
+	for _, write := range writer.writes {
+		if err := write(); err != nil {
+			writeErrs = append(writeErrs, err)
+		}
+	}
+
+	return util.FlattenErrs(writeErrs)
+}
+
+// lock waits for the writes of the other tracks of the group, it does nothing for a track
+// without group.
+func (g *TrackLocalWriteGroup) lock() {
+	if g != nil {
+		g.mu.Lock()
+	}
+}
+
+func (g *TrackLocalWriteGroup) unlock() {
+	if g != nil {
+		g.mu.Unlock()
+	}
+}
+
+// TrackLocalGroupWriter writes to the tracks of a TrackLocalWriteGroup during
+// TrackLocalWriteGroup.Write.
+type TrackLocalGroupWriter struct {
+	group  *TrackLocalWriteGroup
+	writes []func() error
+}
+
+// WriteRTP queues a RTP Packet for a track of the group, it is sent like by
+// TrackLocalStaticRTP.WriteRTP when TrackLocalWriteGroup.Write returns. The packet is
+// copied, it can be reused.
+func (w *TrackLocalGroupWriter) WriteRTP(track *TrackLocalStaticRTP, p *rtp.Packet) error {
+	if w.group == nil || track.writeGroup != w.group {
+		return errTrackLocalNotInWriteGroup
+	}
+
+	packet := p.Clone()
+	w.writes = append(w.writes, func() error {
+		return track.writeRTP(packet)
+	})
+
+	return nil
+}
+
+// WriteSample queues a Sample for a track of the group, it is sent like by
+// TrackLocalStaticSample.WriteSample when TrackLocalWriteGroup.Write returns. The data of
+// the sample is copied, it can be reused.
+func (w *TrackLocalGroupWriter) WriteSample(track *TrackLocalStaticSample, sample media.Sample) error {
+	if w.group == nil || track.rtpTrack.writeGroup != w.group {
+		return errTrackLocalNotInWriteGroup
+	}
+
+	sample.Data = append([]byte{}, sample.Data...)
+	w.writes = append(w.writes, func() error {
+		return track.writeSample(sample)
+	})
+
+	return nil
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/pkg/media"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTrackLocalWriter records the SSRC of the packets written to any of its bindings.
+type recordingTrackLocalWriter struct {
+	mu    sync.Mutex
+	ssrcs []uint32
+}
+
+func (w *recordingTrackLocalWriter) WriteRTP(header *rtp.Header, _ []byte) (int, error) {
+	// Leave time for a concurrent write to interleave
+	time.Sleep(time.Millisecond)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.ssrcs = append(w.ssrcs, header.SSRC)
+
+	return 1, nil
+}
+
+func (w *recordingTrackLocalWriter) Write([]byte) (int, error) {
+	return 0, nil
+}
+
+// take returns the SSRCs recorded since the last call.
+func (w *recordingTrackLocalWriter) take() []uint32 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ssrcs := w.ssrcs
+	w.ssrcs = nil
+
+	return ssrcs
+}
+
+func TestTrackLocalWriteGroup(t *testing.T) {
+	const audioSSRC, videoSSRC = 1, 2
+
+	group := NewTrackLocalWriteGroup()
+	writer := &recordingTrackLocalWriter{}
+
+	audio, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeOpus}, "audio", "pion", WithWriteGroup(group))
+	require.NoError(t, err)
+	audio.bindings = append(audio.bindings, trackBinding{ssrc: audioSSRC, writeStream: writer})
+
+	video, err := NewTrackLocalStaticSample(RTPCodecCapability{MimeType: MimeTypeVP8}, "video", "pion", WithWriteGroup(group))
+	require.NoError(t, err)
+	video.rtpTrack.bindings = append(video.rtpTrack.bindings, trackBinding{ssrc: videoSSRC, writeStream: writer})
+	video.sequencer = rtp.NewRandomSequencer()
+	require.NoError(t, video.createPacketizer(RTPCodecParameters{
+		RTPCodecCapability: RTPCodecCapability{MimeType: MimeTypeVP8, ClockRate: 90000},
+	}, rtp.WithSSRC(videoSSRC)))
+
+	ungrouped, err := NewTrackLocalStaticRTP(RTPCodecCapability{MimeType: MimeTypeOpus}, "audio", "pion")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			assert.NoError(t, audio.WriteRTP(&rtp.Packet{}))
+		}
+	}()
+
+	// A large frame is split in several packets, they are all sent before the audio
+	require.NoError(t, group.Write(func(w *TrackLocalGroupWriter) error {
+		assert.NoError(t, w.WriteRTP(audio, &rtp.Packet{}))
+		assert.ErrorIs(t, w.WriteRTP(ungrouped, &rtp.Packet{}), errTrackLocalNotInWriteGroup)
+
+		return w.WriteSample(video, media.Sample{Data: make([]byte, 3000), Duration: time.Second / 30})
+	}))
+	wg.Wait()
+
+	ssrcs := writer.take()
+	start := -1
+	for i, ssrc := range ssrcs {
+		if ssrc == videoSSRC {
+			start = i - 1
+
+			break
+		}
+	}
+	require.GreaterOrEqual(t, start, 0)
+
+	groupWrite := ssrcs[start : start+4]
+	assert.Equal(t, []uint32{audioSSRC, videoSSRC, videoSSRC, videoSSRC}, groupWrite)
+	for _, ssrc := range ssrcs[start+4:] {
+		assert.Equal(t, uint32(audioSSRC), ssrc)
+	}
+	assert.Len(t, ssrcs, 24)
+
+	var groupWriter *TrackLocalGroupWriter
+	require.NoError(t, group.Write(func(w *TrackLocalGroupWriter) error {
+		groupWriter = w
+
+		return nil
+	}))
+	assert.ErrorIs(t, groupWriter.WriteRTP(audio, &rtp.Packet{}), errTrackLocalNotInWriteGroup)
+
+	// Writing to a track, or to the group, during Write doesn't wait for it
+	require.NoError(t, group.Write(func(w *TrackLocalGroupWriter) error {
+		assert.NoError(t, w.WriteRTP(video.rtpTrack, &rtp.Packet{}))
+		assert.NoError(t, audio.WriteRTP(&rtp.Packet{}))
+
+		return group.Write(func(nested *TrackLocalGroupWriter) error {
+			return nested.WriteRTP(audio, &rtp.Packet{})
+		})
+	}))
+	assert.Equal(t, []uint32{audioSSRC, audioSSRC, videoSSRC}, writer.take())
+
+	// Nothing is sent when the function fails
+	assert.ErrorIs(t, group.Write(func(w *TrackLocalGroupWriter) error {
+		assert.NoError(t, w.WriteRTP(audio, &rtp.Packet{}))
+
+		return errTrackLocalNotInWriteGroup
+	}), errTrackLocalNotInWriteGroup)
+	assert.Empty(t, writer.take())
+}
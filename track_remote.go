@@ -32,6 +32,21 @@ type TrackRemote struct {
 	receiver         *RTPReceiver
 	peeked           []byte
 	peekedAttributes interceptor.Attributes
+
+	anomalyDetector  rtpAnomalyDetector
+	onAnomalyHandler func(RTPAnomaly)
+	duplicateFilter  *duplicatePacketFilter
+	clockSkew        *clockSkewEstimator
+	jitter           jitterEstimator
+	reception        rtpReceptionCounter
+
+	onSSRCChangeHandler func(oldSSRC, newSSRC SSRC)
+
+	dtmfEvents    dtmfEventTracker
+	onDTMFHandler func(DTMFEvent)
+
+	firstKeyFrameRead      bool
+	onFirstKeyFrameHandler func()
 }
 
 func newTrackRemote(kind RTPCodecType, ssrc, rtxSsrc SSRC, rid string, receiver *RTPReceiver) *TrackRemote {
@@ -41,6 +56,9 @@ func newTrackRemote(kind RTPCodecType, ssrc, rtxSsrc SSRC, rid string, receiver
 		rtxSsrc:  rtxSsrc,
 		rid:      rid,
 		receiver: receiver,
+
+		duplicateFilter: newDuplicatePacketFilter(receiver.api.settingEngine.duplicatePacketWindow),
+		clockSkew:       newClockSkewEstimator(receiver.api.settingEngine.clockSkewEstimationWindow),
 	}
 }
 
@@ -109,7 +127,9 @@ func (t *TrackRemote) Codec() RTPCodecParameters {
 	return t.codec
 }
 
-// Read reads data from the track.
+// Read reads data from the track. io.EOF is returned once the RTPReceiver
+// has been stopped, see SettingEngine.EnableReceiverDrainOnStop to read the
+// packets that were still buffered at that point.
 func (t *TrackRemote) Read(b []byte) (n int, attributes interceptor.Attributes, err error) {
 	t.mu.RLock()
 	receiver := t.receiver
@@ -128,30 +148,76 @@ func (t *TrackRemote) Read(b []byte) (n int, attributes interceptor.Attributes,
 		// released the lock.  Deal with it.
 		if data != nil {
 			n = copy(b, data)
-			err = t.checkAndUpdateTrack(b)
-
-			return n, attributes, err
+			if isEvent, handled := t.receiveDTMF(b[:n]); !handled {
+				if !isEvent {
+					err = t.checkAndUpdateTrack(b)
+				}
+				if err == nil {
+					t.checkFirstKeyFrame(b[:n])
+				}
+
+				return n, attributes, err
+			}
 		}
 	}
 
-	// If there's a separate RTX track and an RTX packet is available, return that
-	if rtxPacketReceived := receiver.readRTX(t); rtxPacketReceived != nil {
-		n = copy(b, rtxPacketReceived.pkt)
-		attributes = rtxPacketReceived.attributes
-		rtxPacketReceived.release()
-		err = nil
-	} else {
-		// If there's no separate RTX track (or there's a separate RTX track but no RTX packet waiting), wait for and return
-		// a packet from the main track
-		n, attributes, err = receiver.readRTP(b, t)
-		if err != nil {
-			return n, attributes, err
+	for {
+		// If there's a separate RTX track and an RTX packet is available, return that
+		if rtxPacketReceived := receiver.readRTX(t); rtxPacketReceived != nil {
+			n = copy(b, rtxPacketReceived.pkt)
+			attributes = rtxPacketReceived.attributes
+			rtxPacketReceived.release()
+			if t.duplicateFilter.isDuplicate(b[:n]) {
+				continue
+			}
+			err = nil
+			t.reception.update(b[:n], packetArrivalTime(attributes))
+			t.checkFirstKeyFrame(b[:n])
+		} else {
+			// If there's no separate RTX track (or there's a separate RTX track but no RTX packet waiting), wait for and return
+			// a packet from the main track
+			n, attributes, err = receiver.readRTP(b, t)
+			if err != nil {
+				return n, attributes, err
+			}
+			if t.duplicateFilter.isDuplicate(b[:n]) {
+				continue
+			}
+			// Telephone-events share the sequence numbers of the audio but not its timestamps
+			isEvent, handled := t.receiveDTMF(b[:n])
+			if handled {
+				continue
+			}
+			if isEvent {
+				return n, attributes, nil
+			}
+
+			t.detectAnomaly(b[:n])
+			err = t.checkAndUpdateTrack(b)
+			arrival := packetArrivalTime(attributes)
+			if err == nil && t.clockSkew != nil {
+				t.clockSkew.update(b[:n], t.Codec().ClockRate, arrival)
+			}
+			if err == nil {
+				t.reception.update(b[:n], arrival)
+				t.jitter.update(b[:n], t.Codec().ClockRate, arrival)
+				t.checkFirstKeyFrame(b[:n])
+			}
 		}
 
-		err = t.checkAndUpdateTrack(b)
+		return n, attributes, err
+	}
+}
+
+// packetArrivalTime returns the AttributeArrivalTime of a received packet, the time it was
+// decrypted rather than read by the application. It is the current time when the buffer
+// of the track was created by SettingEngine.BufferFactory, which doesn't record arrivals.
+func packetArrivalTime(attributes interceptor.Attributes) time.Time {
+	if arrival, ok := attributes.Get(AttributeArrivalTime).(time.Time); ok {
+		return arrival
 	}
 
-	return n, attributes, err
+	return time.Now()
 }
 
 // checkAndUpdateTrack checks payloadType for every incoming packet
@@ -170,6 +236,10 @@ func (t *TrackRemote) checkAndUpdateTrack(b []byte) error {
 		if err != nil {
 			return err
 		}
+		// Telephone-events and comfort noise are sent in between the packets of the audio codec
+		if len(t.params.Codecs) != 0 && (isTelephoneEvent(params.Codecs[0]) || isComfortNoise(params.Codecs[0])) {
+			return nil
+		}
 
 		t.kind = t.receiver.kind
 		t.payloadType = payloadType
@@ -180,6 +250,25 @@ func (t *TrackRemote) checkAndUpdateTrack(b []byte) error {
 	return nil
 }
 
+// updateCodecFmtp refreshes the codec parameters of the current payload type, so that
+// fmtp changes of a renegotiation are visible in Codec.
+func (t *TrackRemote) updateCodecFmtp() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.params.Codecs) == 0 {
+		return
+	}
+
+	params, err := t.receiver.api.mediaEngine.getRTPParametersByPayloadType(t.payloadType)
+	if err != nil {
+		return
+	}
+
+	t.codec = params.Codecs[0]
+	t.params = params
+}
+
 // ReadRTP is a convenience method that wraps Read and unmarshals for you.
 func (t *TrackRemote) ReadRTP() (*rtp.Packet, interceptor.Attributes, error) {
 	b := make([]byte, t.receiver.api.settingEngine.getReceiveMTU())
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import "github.com/pion/dtls/v3"
+
+// TransportDescription describes the secure transport of an established PeerConnection: the
+// selected ICE candidate pair, the DTLS role, the negotiated SRTP protection profile and the
+// state of the SCTP transport.
+//
+// A TransportDescription is read-only, it can't be restored into a PeerConnection. The ICE agent
+// can't be started from an existing candidate pair without running connectivity checks, and the
+// SCTP association state (TSNs, stream sequence numbers) can't be exported from pion/sctp, so a
+// remote would observe a new ICE session and a broken SCTP association. It holds no key material.
+type TransportDescription struct {
+	SelectedCandidatePair *ICECandidatePair          `json:"selectedCandidatePair"`
+	DTLSRole              DTLSRole                   `json:"dtlsRole"`
+	SRTPProtectionProfile dtls.SRTPProtectionProfile `json:"srtpProtectionProfile"`
+	SCTPTransportState    SCTPTransportState         `json:"sctpTransportState"`
+}
+
+// DescribeTransport returns a TransportDescription of the connection. The DTLS handshake must
+// be complete.
+func (pc *PeerConnection) DescribeTransport() (*TransportDescription, error) {
+	pair, err := pc.iceTransport.GetSelectedCandidatePair()
+	if err != nil {
+		return nil, err
+	} else if pair == nil {
+		return nil, errTransportDescriptionNoCandidatePair
+	}
+
+	profile, ok := pc.dtlsTransport.SRTPProtectionProfile()
+	if !ok {
+		return nil, errDtlsTransportNotStarted
+	}
+
+	return &TransportDescription{
+		SelectedCandidatePair: pair,
+		DTLSRole:              pc.dtlsTransport.role(),
+		SRTPProtectionProfile: profile,
+		SCTPTransportState:    pc.sctpTransport.State(),
+	}, nil
+}
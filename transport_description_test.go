@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2023 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+// +build !js
+
+package webrtc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pion/transport/v3/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerConnection_DescribeTransport(t *testing.T) {
+	lim := test.TimeOut(time.Second * 30)
+	defer lim.Stop()
+
+	report := test.CheckRoutines(t)
+	defer report()
+
+	pcOffer, pcAnswer, err := newPair()
+	require.NoError(t, err)
+
+	_, err = pcOffer.DescribeTransport()
+	assert.Error(t, err)
+
+	connected := untilConnectionState(PeerConnectionStateConnected, pcOffer, pcAnswer)
+	require.NoError(t, signalPair(pcOffer, pcAnswer))
+	connected.Wait()
+
+	offerDescription, err := pcOffer.DescribeTransport()
+	require.NoError(t, err)
+	answerDescription, err := pcAnswer.DescribeTransport()
+	require.NoError(t, err)
+
+	assert.NotNil(t, offerDescription.SelectedCandidatePair)
+	assert.NotEqual(t, offerDescription.DTLSRole, answerDescription.DTLSRole)
+	assert.NotZero(t, offerDescription.SRTPProtectionProfile)
+	assert.Equal(t, offerDescription.SRTPProtectionProfile, answerDescription.SRTPProtectionProfile)
+	assert.Equal(t, SCTPTransportStateConnecting, offerDescription.SCTPTransportState)
+
+	// The description must survive a round trip through JSON
+	raw, err := json.Marshal(offerDescription)
+	require.NoError(t, err)
+	restored := &TransportDescription{}
+	require.NoError(t, json.Unmarshal(raw, restored))
+	assert.Equal(t, offerDescription.DTLSRole, restored.DTLSRole)
+	assert.Equal(t, offerDescription.SRTPProtectionProfile, restored.SRTPProtectionProfile)
+	assert.Equal(t, offerDescription.SCTPTransportState, restored.SCTPTransportState)
+	assert.Equal(t, offerDescription.SelectedCandidatePair.String(), restored.SelectedCandidatePair.String())
+
+	closePairNow(t, pcOffer, pcAnswer)
+}